@@ -17,4 +17,7 @@
 //   - operation 参数会转成 `Parameters` 结构体字段；
 //   - request body 会转成 `in:"body"` 字段；
 //   - 2xx 响应会生成 `ResponseData` 返回类型及相关 schema 定义。
+//
+// 操作声明的 `callbacks` 与文档顶层的 `webhooks` 会生成接收方的请求类型。
+// 这些类型不带路径，接收方嵌入后实现 `Output` 即可作为 operator 挂载到自己的路由上。
 package clientgen
@@ -28,8 +28,9 @@ func init() {
 }
 
 type clientGen struct {
-	types map[string]*typ
-	oas   openapi.Payload
+	types     map[string]*typ
+	generated map[string]bool
+	oas       openapi.Payload
 }
 
 type typ struct {
@@ -44,6 +45,7 @@ func (g *clientGen) Name() string {
 
 func (g *clientGen) GenerateType(c gengo.Context, named *types.Named) error {
 	g.types = map[string]*typ{}
+	g.generated = map[string]bool{}
 
 	return g.generateClient(c, named)
 }
@@ -146,6 +148,33 @@ func (g *clientGen) generateClient(c gengo.Context, named *types.Named) error {
 		}
 	}
 
+	// 回调与 webhook 生成接收方的请求类型，不带路径，由接收方在挂载时决定
+	for _, operations := range g.oas.Paths.KeyValues() {
+		for _, op := range operations.KeyValues() {
+			if !o.ShouldGenerate(op) {
+				continue
+			}
+
+			for _, callback := range op.Callbacks.KeyValues() {
+				for _, item := range callback.KeyValues() {
+					for method, callbackOp := range item.KeyValues() {
+						if err := g.genOperation(c, "", gengo.UpperCamelCase(strings.ToLower(method)), callbackOp, o); err != nil {
+							return err
+						}
+					}
+				}
+			}
+		}
+	}
+
+	for _, item := range g.oas.Webhooks.KeyValues() {
+		for method, webhookOp := range item.KeyValues() {
+			if err := g.genOperation(c, "", gengo.UpperCamelCase(strings.ToLower(method)), webhookOp, o); err != nil {
+				return err
+			}
+		}
+	}
+
 	for _, k := range slices.Sorted(maps.Keys(g.types)) {
 		t := g.types[k]
 
@@ -169,6 +198,12 @@ func (g *clientGen) genOperation(c gengo.Context, path string, method string, op
 		}
 	}
 
+	// 同一请求类型可能同时作为回调与 webhook 声明
+	if g.generated[operationID] {
+		return nil
+	}
+	g.generated[operationID] = true
+
 	hasResponse := false
 	for statusOrStr := range operation.ResponsesObject.Responses {
 		status, _ := strconv.ParseInt(statusOrStr, 10, 64)
@@ -191,7 +226,7 @@ func (g *clientGen) genOperation(c gengo.Context, path string, method string, op
 	c.RenderT(`
 @doc
 type @Operation struct {
-	@courierhttpMethod@method `+"`"+`path:@path`+"`"+`
+	@courierhttpMethod@method@pathTag
 	
 	@Operation'Parameters
 }
@@ -210,8 +245,15 @@ type @Operation'Parameters struct {
 		"courierClientFromContext": snippet.ID("github.com/octohelm/courier/pkg/courier.ClientFromContext"),
 		"Operation":                snippet.ID(operationID),
 		"method":                   snippet.ID(method),
-		"path":                     snippet.Value(path),
 		"doc":                      snippet.Comment(operation.Description),
+		"pathTag": snippet.Snippets(func(yield func(snippet.Snippet) bool) {
+			if path == "" {
+				return
+			}
+			yield(snippet.T(` `+"`"+`path:@path`+"`", snippet.Args{
+				"path": snippet.Value(path),
+			}))
+		}),
 		"ResponseData": snippet.Snippets(func(yield func(snippet.Snippet) bool) {
 			if hasResponse {
 				if !yield(snippet.T(`
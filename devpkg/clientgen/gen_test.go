@@ -0,0 +1,117 @@
+package clientgen_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/octohelm/gengo/pkg/gengo"
+	. "github.com/octohelm/x/testing/v2"
+
+	_ "github.com/octohelm/courier/devpkg/clientgen"
+)
+
+const spec = `{
+  "openapi": "3.1.0",
+  "info": {"title": "demo", "version": ""},
+  "paths": {
+    "/api/subscriptions": {
+      "post": {
+        "operationId": "Subscribe",
+        "requestBody": {
+          "content": {"application/json": {"schema": {"type": "object", "properties": {"callbackUrl": {"type": "string"}}}}}
+        },
+        "responses": {"204": {"description": ""}},
+        "callbacks": {
+          "itemCreated": {
+            "{$request.body#/callbackUrl}": {
+              "post": {
+                "operationId": "ItemCreatedCallback",
+                "parameters": [{"name": "Webhook-Signature", "in": "header", "required": true, "schema": {"type": "string"}}],
+                "requestBody": {
+                  "content": {"application/json": {"schema": {"type": "object", "properties": {"id": {"type": "string"}}}}}
+                },
+                "responses": {"204": {"description": ""}}
+              }
+            }
+          }
+        }
+      }
+    }
+  },
+  "webhooks": {
+    "item.deleted": {
+      "post": {
+        "operationId": "ItemDeletedWebhook",
+        "requestBody": {
+          "content": {"application/json": {"schema": {"type": "object", "properties": {"id": {"type": "string"}}}}}
+        },
+        "responses": {"204": {"description": ""}}
+      }
+    }
+  }
+}`
+
+func TestClientGenCallbacksAndWebhooks(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		_, _ = rw.Write([]byte(spec))
+	}))
+	t.Cleanup(srv.Close)
+
+	// 生成器从类型注释读取文档地址，因此 fixture 包在测试时按服务地址写出
+	dir := filepath.Join("testdata", "callbackclient")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = os.RemoveAll(dir)
+	})
+
+	src := "package callbackclient\n\n" +
+		"// +gengo:client:openapi=" + srv.URL + "/api\n" +
+		"// +gengo:client:typegen-policy=All\n" +
+		"type Client struct{}\n"
+
+	if err := os.WriteFile(filepath.Join(dir, "client.go"), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := gengo.NewExecutor(&gengo.GeneratorArgs{
+		Entrypoint:         []string{"github.com/octohelm/courier/devpkg/clientgen/testdata/callbackclient"},
+		OutputFileBaseName: "zz_generated",
+		Globals:            map[string][]string{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.Execute(context.Background(), gengo.GetRegisteredGenerators()...); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "zz_generated.client.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	generated := string(data)
+
+	Then(t, "普通操作带有路径",
+		Expect(regexp.MustCompile("type Subscribe struct \\{\\s+courierhttp\\.MethodPost `path:\"/api/subscriptions\"`").MatchString(generated), Equal(true)),
+	)
+
+	Then(t, "回调生成不带路径的请求类型",
+		Expect(regexp.MustCompile(`type ItemCreatedCallback struct \{\s+courierhttp\.MethodPost\s+ItemCreatedCallbackParameters`).MatchString(generated), Equal(true)),
+		Expect(regexp.MustCompile("`name:\"Webhook-Signature\" in:\"header\"`").MatchString(generated), Equal(true)),
+	)
+
+	Then(t, "webhook 生成不带路径的请求类型",
+		Expect(regexp.MustCompile(`type ItemDeletedWebhook struct \{\s+courierhttp\.MethodPost\s+ItemDeletedWebhookParameters`).MatchString(generated), Equal(true)),
+		Expect(regexp.MustCompile(`func \(ItemDeletedWebhook\) ResponseData\(\) \(?\*courier\.NoContent`).MatchString(generated), Equal(true)),
+	)
+}
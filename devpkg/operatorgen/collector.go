@@ -0,0 +1,83 @@
+package operatorgen
+
+import (
+	"go/ast"
+	"go/types"
+	"strings"
+	"sync"
+
+	"github.com/octohelm/gengo/pkg/gengo"
+	typex "github.com/octohelm/x/types"
+
+	"github.com/octohelm/courier/pkg/statuserror"
+)
+
+// NewStatusErrorCollector 创建只收集 statuserror 而不生成代码的 `operator` 生成器。
+//
+// 扫描规则与生成 `ResponseErrors` 时一致，供错误信息提取等工具复用。
+func NewStatusErrorCollector() *StatusErrorCollector {
+	return &StatusErrorCollector{
+		scanner:     newStatusErrScanner(),
+		descriptors: map[string]*statuserror.Descriptor{},
+	}
+}
+
+type StatusErrorCollector struct {
+	scanner *statusErrScanner
+
+	mu          sync.Mutex
+	descriptors map[string]*statuserror.Descriptor
+}
+
+func (g *StatusErrorCollector) Name() string {
+	return "operator"
+}
+
+func (g *StatusErrorCollector) GenerateType(c gengo.Context, named *types.Named) error {
+	if !ast.IsExported(named.Obj().Name()) {
+		return gengo.ErrSkip
+	}
+
+	if !isCourierOperator(c, typex.FromTType(types.NewPointer(named)), (&operatorGen{}).resolvePkg) {
+		return gengo.ErrSkip
+	}
+
+	method, ok := typex.FromTType(types.NewPointer(named)).MethodByName("Output")
+	if !ok {
+		return gengo.ErrSkip
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for _, statusError := range g.scanner.StatusErrorsInFunc(c, method.(*typex.TMethod).Func) {
+		d := *statusError
+
+		// 具名错误类型的 Code 为完整类型路径，需与运行时 statuserror.ErrCodeOf 对齐
+		if strings.Contains(d.Code, "/") {
+			if i := strings.LastIndex(d.Code, "."); i > 0 {
+				d.Code = statuserror.ErrCodeOfTypeName(d.Code[:i], d.Code[i+1:])
+			}
+		}
+
+		if d.Code == "" {
+			continue
+		}
+
+		g.descriptors[d.Code] = &d
+	}
+
+	return gengo.ErrSkip
+}
+
+// Messages 返回已收集的错误码及其默认信息模板。
+func (g *StatusErrorCollector) Messages() map[string]string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	messages := make(map[string]string, len(g.descriptors))
+	for code, d := range g.descriptors {
+		messages[code] = d.Message
+	}
+	return messages
+}
@@ -6,11 +6,18 @@
 //     为当前包生成 `init()` 注册代码，效果等价于
 //     `RouterVar.Register(courier.NewRouter(&Operator{}))`。
 //     该参数可重复声明，用于注册到多个 router 变量。
+//   - `+gengo:operator:deprecated=<date>`、`+gengo:operator:sunset=<date>`、
+//     `+gengo:operator:successor=<url>`
+//     生成 `Deprecation()`，运行时据此输出 `Deprecation`、`Sunset` 与后继版本 `Link` 响应头；
+//     时间格式为 `2006-01-02` 或 RFC 3339。
 //
 // 生成内容主要包括：
 //
 //   - 根据 `Output` 方法推断 `ResponseData`、`ResponseContent`、
 //     `ResponseStatusCode`、`ResponseContentType`；
 //   - 扫描 `Output` 返回链路中的 `statuserror`，补充 `ResponseErrors`；
-//   - 在声明了 `register` 参数时自动输出路由注册入口。
+//   - 在声明了 `register` 参数时自动输出路由注册入口；
+//   - 在声明了 `deprecated` 参数时输出弃用信息。
+//
+// `NewStatusErrorCollector` 复用同一套错误扫描，只收集错误码与信息模板，供 `i18n-extract` 生成翻译文件。
 package operatorgen
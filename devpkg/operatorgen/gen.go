@@ -8,6 +8,7 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/octohelm/gengo/pkg/gengo"
 	"github.com/octohelm/gengo/pkg/gengo/snippet"
@@ -41,6 +42,7 @@ func (g *operatorGen) GenerateType(c gengo.Context, named *types.Named) error {
 	}
 
 	g.generateRegister(c, named)
+	g.generateDeprecation(c, named)
 	g.generateReturns(c, named)
 	return nil
 }
@@ -286,6 +288,77 @@ func init() {
 	}
 }
 
+func (g *operatorGen) generateDeprecation(c gengo.Context, named *types.Named) {
+	if g.hasMethod(named, "Deprecation") {
+		return
+	}
+
+	tags, _ := c.Doc(named.Obj())
+
+	deprecated, ok := tags["gengo:operator:deprecated"]
+	if !ok {
+		return
+	}
+
+	if len(deprecated) == 0 || deprecated[0] == "" {
+		c.Logger().Warn(fmt.Errorf("%s: `gengo:operator:deprecated` requires a date, use `path:\",deprecated\"` instead", named))
+		return
+	}
+
+	parseTime := func(name string, values []string) (int64, bool) {
+		if len(values) == 0 || values[0] == "" {
+			return 0, false
+		}
+		for _, layout := range []string{time.DateOnly, time.RFC3339} {
+			if t, err := time.Parse(layout, values[0]); err == nil {
+				return t.Unix(), true
+			}
+		}
+		c.Logger().Warn(fmt.Errorf("%s: invalid %s time %q", named, name, values[0]))
+		return 0, false
+	}
+
+	at, ok := parseTime("deprecated", deprecated)
+	if !ok {
+		return
+	}
+
+	c.RenderT(`
+func (@Type) Deprecation() @courierhttpDeprecation {
+	return @courierhttpDeprecation{
+		@fields
+	}
+}
+
+`, snippet.Args{
+		"Type":                   snippet.ID(named.Obj()),
+		"courierhttpDeprecation": snippet.PkgExposeFor[courierhttp.Deprecation](),
+		"fields": snippet.Snippets(func(yield func(snippet.Snippet) bool) {
+			if !yield(snippet.T("At: @timeUnix(@at, 0).UTC(),\n", snippet.Args{
+				"timeUnix": snippet.ID("time.Unix"),
+				"at":       snippet.Value(at),
+			})) {
+				return
+			}
+
+			if sunset, ok := parseTime("sunset", tags["gengo:operator:sunset"]); ok {
+				if !yield(snippet.T("Sunset: @timeUnix(@sunset, 0).UTC(),\n", snippet.Args{
+					"timeUnix": snippet.ID("time.Unix"),
+					"sunset":   snippet.Value(sunset),
+				})) {
+					return
+				}
+			}
+
+			if successor := tags["gengo:operator:successor"]; len(successor) > 0 && successor[0] != "" {
+				yield(snippet.T("Successor: @successor,\n", snippet.Args{
+					"successor": snippet.Value(successor[0]),
+				}))
+			}
+		}),
+	})
+}
+
 func (g *operatorGen) resolvePkg(c gengo.Context, importPath string) *types.Package {
 	return c.Package(importPath).Pkg()
 }
@@ -0,0 +1,65 @@
+package operatorgen_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/octohelm/gengo/pkg/gengo"
+	. "github.com/octohelm/x/testing/v2"
+
+	_ "github.com/octohelm/courier/devpkg/operatorgen"
+)
+
+func TestOperatorGenDeprecation(t *testing.T) {
+	dir := filepath.Join("testdata", "deprecated")
+	output := filepath.Join(dir, "zz_generated.operator.go")
+
+	t.Cleanup(func() {
+		_ = os.Remove(output)
+	})
+
+	c, err := gengo.NewExecutor(&gengo.GeneratorArgs{
+		Entrypoint:         []string{"github.com/octohelm/courier/devpkg/operatorgen/testdata/deprecated"},
+		OutputFileBaseName: "zz_generated",
+		Globals:            map[string][]string{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.Execute(context.Background(), gengo.GetRegisteredGenerators()...); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	generated := string(data)
+
+	Then(t, "按 deprecated、sunset、successor 生成 Deprecation",
+		Expect(regexp.MustCompile(`func \(GetOrgV1\) Deprecation\(\) courierhttp\.Deprecation \{
+\s+return courierhttp\.Deprecation\{
+\s+At:\s+time\.Unix\(1735689600, 0\)\.UTC\(\),
+\s+Sunset:\s+time\.Unix\(1767225600, 0\)\.UTC\(\),
+\s+Successor:\s+"/v2/orgs/\{name\}",
+\s+\}
+\}`).MatchString(generated), Equal(true)),
+	)
+
+	Then(t, "仅声明 deprecated 时只生成弃用时间",
+		Expect(regexp.MustCompile(`func \(ListOrgV1\) Deprecation\(\) courierhttp\.Deprecation \{
+\s+return courierhttp\.Deprecation\{
+\s+At:\s+time\.Unix\(1735689600, 0\)\.UTC\(\),
+\s+\}
+\}`).MatchString(generated), Equal(true)),
+	)
+
+	Then(t, "deprecated 未声明日期时不生成",
+		Expect(regexp.MustCompile(`func \(DeleteOrgV1\) Deprecation\(\)`).MatchString(generated), Equal(false)),
+	)
+}
@@ -0,0 +1,45 @@
+// +gengo:operator:register=R
+package deprecated
+
+import (
+	"context"
+
+	"github.com/octohelm/courier/pkg/courier"
+	"github.com/octohelm/courier/pkg/courierhttp"
+)
+
+var R = courier.NewRouter()
+
+// +gengo:operator:deprecated=2025-01-01
+// +gengo:operator:sunset=2026-01-01T00:00:00Z
+// +gengo:operator:successor=/v2/orgs/{name}
+type GetOrgV1 struct {
+	courierhttp.MethodGet `path:"/v1/orgs/{name}"`
+
+	Name string `name:"name" in:"path"`
+}
+
+func (r *GetOrgV1) Output(ctx context.Context) (any, error) {
+	return nil, nil
+}
+
+// +gengo:operator:deprecated=2025-01-01
+type ListOrgV1 struct {
+	courierhttp.MethodGet `path:"/v1/orgs"`
+}
+
+func (r *ListOrgV1) Output(ctx context.Context) (any, error) {
+	return nil, nil
+}
+
+// 未声明日期时不生成
+// +gengo:operator:deprecated
+type DeleteOrgV1 struct {
+	courierhttp.MethodDelete `path:"/v1/orgs/{name}"`
+
+	Name string `name:"name" in:"path"`
+}
+
+func (r *DeleteOrgV1) Output(ctx context.Context) (any, error) {
+	return nil, nil
+}
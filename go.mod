@@ -5,6 +5,8 @@ go 1.26.4
 tool (
 	github.com/octohelm/courier/tool/internal/cmd/fmt
 	github.com/octohelm/courier/tool/internal/cmd/gen
+	github.com/octohelm/courier/tool/internal/cmd/i18n-extract
+	github.com/octohelm/courier/tool/internal/cmd/mock-server
 	github.com/octohelm/courier/tool/internal/cmd/skills-install
 )
 
@@ -21,6 +23,7 @@ require (
 	github.com/juju/ansiterm v1.0.0
 	golang.org/x/net v0.56.0
 	golang.org/x/sync v0.21.0
+	golang.org/x/text v0.39.0
 	k8s.io/apimachinery v0.36.2
 )
 
@@ -31,7 +34,6 @@ require (
 	github.com/mattn/go-isatty v0.0.22 // indirect
 	golang.org/x/mod v0.37.0 // indirect
 	golang.org/x/sys v0.46.0 // indirect
-	golang.org/x/text v0.39.0 // indirect
 	golang.org/x/tools v0.47.0 // indirect
 	mvdan.cc/gofumpt v0.10.0 // indirect
 )
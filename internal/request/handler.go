@@ -1,8 +1,10 @@
 package request
 
 import (
+	"bufio"
 	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"strings"
 	"sync"
@@ -30,6 +32,9 @@ type RouteHandler interface {
 	Summary() string
 	Description() string
 	Deprecated() bool
+	ErrorFormat() courierhttp.ErrorFormat
+	Version() courierhttp.VersionInfo
+	Versioning() courierhttp.Versioning
 
 	Operators() []*courier.OperatorFactory
 }
@@ -42,6 +47,7 @@ func NewRouteHandlers(route courier.Route, service string, routeMiddlewares ...h
 
 	basePath := "/"
 	path := ""
+	versionAt := 0
 
 	err := route.RangeOperator(func(f *courier.OperatorFactory, i int) error {
 		m := metaFrom(f)
@@ -54,6 +60,23 @@ func NewRouteHandlers(route courier.Route, service string, routeMiddlewares ...h
 			path += "/" + m.Path
 		}
 
+		if m.ErrorFormat != "" {
+			h.errorFormat = m.ErrorFormat
+		}
+
+		if m.Version.Version != "" {
+			h.version = m.Version
+			versionAt = len(path)
+		}
+
+		if !m.Deprecation.IsZero() {
+			h.deprecation = m.Deprecation
+		}
+
+		if !m.Versioning.IsZero() {
+			h.versioning = m.Versioning
+		}
+
 		if f.IsLast {
 			h.operationID = f.Type.Name()
 			h.deprecated = m.Deprecated
@@ -82,6 +105,23 @@ func NewRouteHandlers(route courier.Route, service string, routeMiddlewares ...h
 		return nil, err
 	}
 
+	if h.deprecation.IsZero() {
+		h.deprecation = h.version.Deprecation
+	}
+
+	h.deprecated = h.deprecated || !h.deprecation.IsZero()
+
+	if h.version.Version != "" {
+		if h.versioning.IsZero() {
+			h.versioning = courierhttp.DefaultVersioning
+		}
+
+		if h.versioning.Path {
+			// 版本前缀位于 Version 操作符声明处
+			path = path[:versionAt] + "/v" + h.version.Version + path[versionAt:]
+		}
+	}
+
 	h.segments = pathpattern.Parse(pathpattern.NormalizePath(basePath + path))
 
 	methods := strings.Split(h.method, ",")
@@ -110,7 +150,11 @@ type routeHandler struct {
 	segments     pathpattern.Segments
 	summary      string
 	deprecated   bool
+	deprecation  courierhttp.Deprecation
 	description  string
+	errorFormat  courierhttp.ErrorFormat
+	version      courierhttp.VersionInfo
+	versioning   courierhttp.Versioning
 	operators    []*courier.OperatorFactory
 	transformers []transport.IncomingTransport
 	middleware   handler.Middleware
@@ -150,6 +194,18 @@ func (h *routeHandler) Deprecated() bool {
 	return h.deprecated
 }
 
+func (h *routeHandler) ErrorFormat() courierhttp.ErrorFormat {
+	return h.errorFormat
+}
+
+func (h *routeHandler) Version() courierhttp.VersionInfo {
+	return h.version
+}
+
+func (h *routeHandler) Versioning() courierhttp.Versioning {
+	return h.versioning
+}
+
 func (h *routeHandler) Operators() []*courier.OperatorFactory {
 	return h.operators
 }
@@ -160,7 +216,27 @@ func (h routeHandler) cloneWithMethod(m string) RouteHandler {
 	return &h
 }
 
-func (h *routeHandler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+func (h *routeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rw := &committedResponseWriter{ResponseWriter: w}
+
+	defer func() {
+		if v := recover(); v != nil {
+			if v == http.ErrAbortHandler {
+				panic(v)
+			}
+
+			ctx := r.Context()
+			err := courierhttp.WrapError(courierhttp.Recovered(ctx, v))
+
+			// 已输出部分响应时无法再写入错误响应，中断连接让客户端感知响应不完整
+			if rw.committed {
+				panic(http.ErrAbortHandler)
+			}
+
+			_ = err.(courierhttp.ResponseWriter).WriteResponse(ctx, rw, httprequest.From(r))
+		}
+	}()
+
 	h.once.Do(func() {
 		var hh http.Handler = &routeHttpHandler{
 			routeHandler: h,
@@ -178,6 +254,16 @@ func (h *routeHandler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
 
 		h.finalHandler = hh
 	})
+
+	if h.errorFormat != courierhttp.ErrorFormatDefault {
+		r = r.WithContext(courierhttp.ContextWithErrorFormat(r.Context(), h.errorFormat))
+	}
+
+	if h.deprecated {
+		h.deprecation.WriteHeader(rw.Header())
+		courierhttp.RecordDeprecatedUsage(r.Context(), h.deprecation)
+	}
+
 	h.finalHandler.ServeHTTP(rw, r)
 }
 
@@ -241,3 +327,36 @@ func (h *routeHttpHandler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
 type WithPreHandlerMiddleware interface {
 	PreHandlerMiddleware(h http.Handler) http.Handler
 }
+
+// committedResponseWriter 记录响应是否已开始输出
+type committedResponseWriter struct {
+	http.ResponseWriter
+
+	committed bool
+}
+
+func (w *committedResponseWriter) WriteHeader(statusCode int) {
+	if statusCode >= http.StatusOK || statusCode == http.StatusSwitchingProtocols {
+		w.committed = true
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *committedResponseWriter) Write(p []byte) (int, error) {
+	w.committed = true
+	return w.ResponseWriter.Write(p)
+}
+
+func (w *committedResponseWriter) Flush() {
+	w.committed = true
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+func (w *committedResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	w.committed = true
+	return http.NewResponseController(w.ResponseWriter).Hijack()
+}
+
+func (w *committedResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -16,6 +16,10 @@ type meta struct {
 	Summary     string
 	Description string
 	Deprecated  bool
+	Deprecation courierhttp.Deprecation
+	ErrorFormat courierhttp.ErrorFormat
+	Version     courierhttp.VersionInfo
+	Versioning  courierhttp.Versioning
 }
 
 var courierhttpPkgPath = reflect.TypeFor[courierhttp.MethodGet]().PkgPath()
@@ -87,6 +91,22 @@ func metaFrom(o *courier.OperatorFactory) *meta {
 		m.Path = pathDescriber.Path()
 	}
 
+	if errorFormatDescriber, ok := op.(courierhttp.ErrorFormatDescriber); ok {
+		m.ErrorFormat = errorFormatDescriber.ErrorFormat()
+	}
+
+	if deprecationDescriber, ok := op.(courierhttp.DeprecationDescriber); ok {
+		m.Deprecation = deprecationDescriber.Deprecation()
+	}
+
+	if versionDescriber, ok := op.(courierhttp.VersionDescriber); ok {
+		m.Version = versionDescriber.VersionInfo()
+	}
+
+	if versioningDescriber, ok := op.(courierhttp.VersioningDescriber); ok {
+		m.Versioning = versioningDescriber.Versioning()
+	}
+
 	return m
 }
 
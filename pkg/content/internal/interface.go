@@ -1,6 +1,7 @@
 package internal
 
 import (
+	"io"
 	"net/http"
 )
 
@@ -23,3 +24,10 @@ type FilenameGetter interface {
 type FilenameSetter interface {
 	SetFilename(f string)
 }
+
+// FileHeader 表示 multipart 中的上传文件。
+type FileHeader interface {
+	io.ReadCloser
+	Filename() string
+	Header() http.Header
+}
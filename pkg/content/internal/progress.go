@@ -0,0 +1,30 @@
+package internal
+
+import (
+	"context"
+)
+
+// UploadProgress 描述请求体的写入进度。
+type UploadProgress struct {
+	// 当前写入的字段名
+	Name string
+	// 当前写入的文件名
+	Filename string
+	// 已写入的字节数
+	Written int64
+	// 请求体总字节数，未知时为 -1
+	Total int64
+}
+
+type UploadProgressFunc = func(p UploadProgress)
+
+type contextUploadProgress struct{}
+
+func ContextWithUploadProgress(ctx context.Context, fn UploadProgressFunc) context.Context {
+	return context.WithValue(ctx, contextUploadProgress{}, fn)
+}
+
+func UploadProgressFromContext(ctx context.Context) (UploadProgressFunc, bool) {
+	fn, ok := ctx.Value(contextUploadProgress{}).(UploadProgressFunc)
+	return fn, ok && fn != nil
+}
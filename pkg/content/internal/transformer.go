@@ -90,7 +90,8 @@ func (v *transformers) New(option transformOption) (Transformer, error) {
 			}
 
 			if option.action == "marshal" {
-				if v.implements(option.tpe, ioReadCloserType) {
+				// 仅声明为 io.Reader 的字段按 octet 输出，其他实现了 io.Reader 的类型保持原有选择
+				if v.implements(option.tpe, ioReadCloserType) || option.tpe == ioReaderType {
 					mediaType = "octet"
 				} else if option.tpe.Implements(encodingTextMarshalerType) {
 					mediaType = "plain"
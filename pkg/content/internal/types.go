@@ -12,6 +12,7 @@ var (
 	stringType = reflect.TypeFor[string]()
 	bytesType  = reflect.TypeFor[[]byte]()
 
+	ioReaderType     = reflect.TypeFor[io.Reader]()
 	ioReadCloserType = reflect.TypeFor[io.ReadCloser]()
 
 	encodingTextMarshalerType   = reflect.TypeFor[encoding.TextMarshaler]()
@@ -0,0 +1,22 @@
+package content
+
+import (
+	"context"
+
+	"github.com/octohelm/courier/pkg/content/internal"
+)
+
+// UploadProgress 描述 multipart 请求体的上传进度。
+type UploadProgress = internal.UploadProgress
+
+type UploadProgressFunc = internal.UploadProgressFunc
+
+// ContextWithUploadProgress 注入上传进度回调，回调在写入请求体的协程中执行。
+func ContextWithUploadProgress(ctx context.Context, fn UploadProgressFunc) context.Context {
+	return internal.ContextWithUploadProgress(ctx, fn)
+}
+
+// UploadProgressFromContext 获取上传进度回调。
+func UploadProgressFromContext(ctx context.Context) (UploadProgressFunc, bool) {
+	return internal.UploadProgressFromContext(ctx)
+}
@@ -12,6 +12,12 @@ import (
 
 type Transformer = internal.Transformer
 
+// FileHeader 表示 multipart 中的上传文件。
+//
+// 请求体字段声明为 `iter.Seq2[FileHeader, error]` 时启用流式读取，
+// 文件部分不会预先缓存，由 operator 按顺序消费。
+type FileHeader = internal.FileHeader
+
 func New(typ reflect.Type, mediaTypeOrAlias string, action string) (Transformer, error) {
 	return internal.New(typ, mediaTypeOrAlias, action)
 }
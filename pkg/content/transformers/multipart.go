@@ -44,6 +44,7 @@ func (p *multipartTransformer) MediaType() string {
 
 const (
 	defaultMaxMemory = 32 << 20 // 32 MB
+	defaultMaxParts  = 1000     // 与 mime/multipart ReadForm 的默认值一致
 )
 
 type withFilename interface {
@@ -53,7 +54,13 @@ type withFilename interface {
 var withFilenameType = reflect.TypeFor[withFilename]()
 
 func (p *multipartTransformer) ReadAs(ctx context.Context, r io.ReadCloser, vv any) error {
-	defer r.Close()
+	streaming := false
+	defer func() {
+		// 流式读取时由文件序列负责关闭
+		if !streaming {
+			_ = r.Close()
+		}
+	}()
 
 	v := jsonflags.Unwrap(vv)
 
@@ -67,12 +74,6 @@ func (p *multipartTransformer) ReadAs(ctx context.Context, r io.ReadCloser, vv a
 		return err
 	}
 
-	reader := multipart.NewReader(r, params["boundary"])
-	form, err := reader.ReadForm(defaultMaxMemory)
-	if err != nil {
-		return err
-	}
-
 	rv, ok := v.(reflect.Value)
 	if !ok {
 		rv = reflect.ValueOf(v)
@@ -90,6 +91,23 @@ func (p *multipartTransformer) ReadAs(ctx context.Context, r io.ReadCloser, vv a
 		return err
 	}
 
+	reader := multipart.NewReader(r, params["boundary"])
+
+	for sf := range fields.StructField() {
+		if sf.Type == fileHeaderSeqType {
+			if err := p.readAsStream(ctx, r, reader, pv, fields, sf); err != nil {
+				return err
+			}
+			streaming = true
+			return nil
+		}
+	}
+
+	form, err := reader.ReadForm(defaultMaxMemory)
+	if err != nil {
+		return err
+	}
+
 	var errs []error
 
 	for sf := range fields.StructField() {
@@ -125,87 +143,224 @@ func (p *multipartTransformer) ReadAs(ctx context.Context, r io.ReadCloser, vv a
 }
 
 func (mt *multipartTransformer) Prepare(ctx context.Context, v any) (internal.Content, error) {
+	rv, ok := v.(reflect.Value)
+	if !ok {
+		rv = reflect.ValueOf(v)
+	}
+
+	for rv.Kind() == reflect.Pointer {
+		rv = rv.Elem()
+	}
+
+	parts, err := mt.prepareParts(ctx, rv)
+	if err != nil {
+		return nil, err
+	}
+
+	boundary := multipart.NewWriter(io.Discard).Boundary()
+
 	c := NewContent(mt.mediaType)
-	c.ReadCloser = AsReaderCloser(ctx, func(w io.WriteCloser) func() error {
-		mw := multipart.NewWriter(w)
-		c.contentType = mw.FormDataContentType()
+	c.contentType = mime.FormatMediaType(mt.mediaType, map[string]string{"boundary": boundary})
+
+	if n, ok := multipartContentLength(boundary, parts); ok {
+		c.SetContentLength(n)
+	}
 
+	progress, _ := internal.UploadProgressFromContext(ctx)
+
+	c.ReadCloser = AsReaderCloser(ctx, func(w io.WriteCloser) func() error {
 		return func() error {
-			defer w.Close()
+			pw := &progressWriter{w: w, fn: progress, p: internal.UploadProgress{Total: c.contentLength}}
 
-			rv, ok := v.(reflect.Value)
-			if ok {
-				v = rv.Interface()
+			err := writeParts(pw, boundary, parts)
+			if err != nil {
+				if x, ok := w.(interface{ CloseWithError(err error) error }); ok {
+					_ = x.CloseWithError(err)
+				}
+				return err
 			}
 
-			for rv.Kind() == reflect.Pointer {
-				rv = rv.Elem()
-			}
+			return w.Close()
+		}
+	})
 
-			pv := &internal.ParamValue{}
-			pv.Value = rv
+	return c, nil
+}
 
-			s, err := jsonflags.Structs.StructFields(pv.Type())
-			if err != nil {
-				return err
+type multipartPart struct {
+	name     string
+	filename string
+	header   textproto.MIMEHeader
+	content  internal.Content
+}
+
+func (mt *multipartTransformer) prepareParts(ctx context.Context, rv reflect.Value) (parts []*multipartPart, err error) {
+	defer func() {
+		if err != nil {
+			for _, p := range parts {
+				_ = p.content.Close()
 			}
+		}
+	}()
+
+	pv := &internal.ParamValue{}
+	pv.Value = rv
+
+	s, err := jsonflags.Structs.StructFields(pv.Type())
+	if err != nil {
+		return nil, err
+	}
 
-			for sf := range s.StructField() {
-				for sfv := range pv.Values(sf) {
-					if sfv.IsZero() {
-						if sf.Omitzero || sf.Omitempty {
-							continue
-						}
-					}
+	for sf := range s.StructField() {
+		for sfv := range pv.Values(sf) {
+			if sfv.IsZero() {
+				if sf.Omitzero || sf.Omitempty {
+					continue
+				}
+			}
 
-					params := map[string]string{
-						"name": sf.Name,
-					}
+			part := &multipartPart{
+				name:   sf.Name,
+				header: textproto.MIMEHeader{},
+			}
 
-					fv := sfv.Interface()
-					if withFilename, ok := fv.(interface{ Filename() string }); ok {
-						params["filename"] = withFilename.Filename()
-					}
+			params := map[string]string{
+				"name": sf.Name,
+			}
 
-					header := textproto.MIMEHeader{}
+			fv := sfv.Interface()
 
-					header.Set("Content-Disposition", mime.FormatMediaType("form-data", params))
+			// 透传自带的 part 头，如转发收到的 FileHeader
+			if withHeader, ok := fv.(internal.HeaderGetter); ok {
+				for k, values := range withHeader.Header() {
+					part.header[textproto.CanonicalMIMEHeaderKey(k)] = values
+				}
+			}
 
-					cw, err := internal.New(sfv.Type(), sf.Tag.Get("mime"), "marshal")
-					if err != nil {
-						return err
-					}
+			if withFilename, ok := fv.(internal.FilenameGetter); ok {
+				part.filename = withFilename.Filename()
+				params["filename"] = part.filename
+			}
 
-					c, err := cw.Prepare(ctx, sfv)
-					if err != nil {
-						return err
-					}
+			part.header.Set("Content-Disposition", mime.FormatMediaType("form-data", params))
 
-					if ct := c.GetContentType(); ct != "" {
-						header.Set("Content-Type", ct)
-					}
+			cw, err := internal.New(sfv.Type(), sf.Tag.Get("mime"), "marshal")
+			if err != nil {
+				return parts, err
+			}
 
-					if withContentType, ok := fv.(interface{ ContentType() string }); ok {
-						header.Set("Content-Type", withContentType.ContentType())
-					}
+			c, err := cw.Prepare(ctx, sfv)
+			if err != nil {
+				return parts, err
+			}
 
-					if i := c.GetContentLength(); i > -1 {
-						header.Set("Content-Length", strconv.FormatInt(i, 10))
-					}
+			part.content = c
+			parts = append(parts, part)
 
-					p, err := mw.CreatePart(header)
-					if err != nil {
-						return err
-					}
+			if ct := c.GetContentType(); ct != "" && part.header.Get("Content-Type") == "" {
+				part.header.Set("Content-Type", ct)
+			}
 
-					if _, err := io.Copy(p, c); err != nil {
-						return err
-					}
+			if withContentType, ok := fv.(internal.ContentTypeGetter); ok {
+				if ct := withContentType.ContentType(); ct != "" {
+					part.header.Set("Content-Type", ct)
 				}
 			}
-			return mw.Close()
+
+			part.header.Del("Content-Length")
+
+			if i := c.GetContentLength(); i > -1 {
+				part.header.Set("Content-Length", strconv.FormatInt(i, 10))
+			}
 		}
-	})
+	}
 
-	return c, nil
+	return parts, nil
+}
+
+func writeParts(pw *progressWriter, boundary string, parts []*multipartPart) error {
+	mw := multipart.NewWriter(pw)
+	if err := mw.SetBoundary(boundary); err != nil {
+		return err
+	}
+
+	for i, part := range parts {
+		pw.p.Name = part.name
+		pw.p.Filename = part.filename
+
+		w, err := mw.CreatePart(part.header)
+		if err != nil {
+			closeParts(parts[i:])
+			return err
+		}
+
+		_, err = io.Copy(w, part.content)
+		_ = part.content.Close()
+		if err != nil {
+			closeParts(parts[i+1:])
+			return err
+		}
+	}
+
+	return mw.Close()
+}
+
+func closeParts(parts []*multipartPart) {
+	for _, p := range parts {
+		_ = p.content.Close()
+	}
+}
+
+// multipartContentLength 在所有 part 长度已知时计算请求体总长度。
+func multipartContentLength(boundary string, parts []*multipartPart) (int64, bool) {
+	cw := &countWriter{}
+
+	mw := multipart.NewWriter(cw)
+	if err := mw.SetBoundary(boundary); err != nil {
+		return -1, false
+	}
+
+	n := int64(0)
+
+	for _, part := range parts {
+		size := part.content.GetContentLength()
+		if size < 0 {
+			return -1, false
+		}
+		n += size
+
+		if _, err := mw.CreatePart(part.header); err != nil {
+			return -1, false
+		}
+	}
+
+	if err := mw.Close(); err != nil {
+		return -1, false
+	}
+
+	return n + cw.n, true
+}
+
+type countWriter struct {
+	n int64
+}
+
+func (w *countWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
+
+type progressWriter struct {
+	w  io.Writer
+	fn internal.UploadProgressFunc
+	p  internal.UploadProgress
+}
+
+func (w *progressWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	if n > 0 && w.fn != nil {
+		w.p.Written += int64(n)
+		w.fn(w.p)
+	}
+	return n, err
 }
@@ -0,0 +1,329 @@
+package transformers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/octohelm/courier/internal/jsonflags"
+	"github.com/octohelm/courier/pkg/content/internal"
+	"github.com/octohelm/courier/pkg/statuserror"
+	validatorerrors "github.com/octohelm/courier/pkg/validator/errors"
+)
+
+var fileHeaderSeqType = reflect.TypeFor[iter.Seq2[internal.FileHeader, error]]()
+
+type ErrPartTooLarge struct {
+	statuserror.RequestEntityTooLarge
+
+	Name     string
+	Filename string
+	MaxSize  int64
+}
+
+func (e *ErrPartTooLarge) Error() string {
+	if e.Filename == "" {
+		return fmt.Sprintf("field %s exceeds the limit of %d bytes", e.Name, e.MaxSize)
+	}
+	return fmt.Sprintf("file %q of %s exceeds the limit of %d bytes", e.Filename, e.Name, e.MaxSize)
+}
+
+type ErrFormTooLarge struct {
+	statuserror.RequestEntityTooLarge
+
+	MaxSize  int64
+	MaxParts int
+}
+
+func (e *ErrFormTooLarge) Error() string {
+	if e.MaxParts > 0 {
+		return fmt.Sprintf("multipart form exceeds the limit of %d fields", e.MaxParts)
+	}
+	return fmt.Sprintf("fields of multipart form exceed the limit of %d bytes", e.MaxSize)
+}
+
+type ErrPartContentTypeNotAllowed struct {
+	statuserror.UnsupportedMediaType
+
+	Name        string
+	Filename    string
+	ContentType string
+}
+
+func (e *ErrPartContentTypeNotAllowed) Error() string {
+	return fmt.Sprintf("content type %q of file %q is not allowed for %s", e.ContentType, e.Filename, e.Name)
+}
+
+type ErrUnexpectedPart struct {
+	statuserror.BadRequest
+
+	Name string
+}
+
+func (e *ErrUnexpectedPart) Error() string {
+	return fmt.Sprintf("unexpected multipart part %q, non-file fields must precede files", e.Name)
+}
+
+// partLimits 来自流式字段的标签：
+// `maxSize:"10MiB"` 限制单个文件大小，`accept:"image/*,application/pdf"` 限制文件类型。
+type partLimits struct {
+	name    string
+	maxSize int64
+	accept  []string
+}
+
+func partLimitsOf(sf *jsonflags.StructField) (*partLimits, error) {
+	l := &partLimits{name: sf.Name}
+
+	if maxSize, ok := sf.Tag.Lookup("maxSize"); ok {
+		n, err := parseSize(maxSize)
+		if err != nil {
+			return nil, fmt.Errorf("invalid maxSize of %s: %w", sf.Name, err)
+		}
+		l.maxSize = n
+	}
+
+	if accept, ok := sf.Tag.Lookup("accept"); ok {
+		for a := range strings.SplitSeq(accept, ",") {
+			if a = strings.TrimSpace(a); a != "" {
+				l.accept = append(l.accept, strings.ToLower(a))
+			}
+		}
+	}
+
+	return l, nil
+}
+
+func (l *partLimits) allow(contentType string) bool {
+	if len(l.accept) == 0 {
+		return true
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	for _, a := range l.accept {
+		if a == "*/*" || a == mediaType {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(a, "/*"); ok && strings.HasPrefix(mediaType, prefix+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (l *partLimits) open(part *multipart.Part) (internal.FileHeader, error) {
+	if part.FileName() == "" || part.FormName() != l.name {
+		return nil, &ErrUnexpectedPart{Name: part.FormName()}
+	}
+
+	header := http.Header(part.Header)
+
+	contentType := header.Get("Content-Type")
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	if !l.allow(contentType) {
+		return nil, &ErrPartContentTypeNotAllowed{Name: l.name, Filename: part.FileName(), ContentType: contentType}
+	}
+
+	return &filePart{part: part, header: header, maxSize: l.maxSize}, nil
+}
+
+type filePart struct {
+	part    *multipart.Part
+	header  http.Header
+	maxSize int64
+	read    int64
+	// 超出大小后不再读取，后续调用均返回错误
+	exceeded bool
+}
+
+func (f *filePart) Filename() string {
+	return f.part.FileName()
+}
+
+func (f *filePart) Header() http.Header {
+	return f.header
+}
+
+func (f *filePart) Read(p []byte) (int, error) {
+	if f.maxSize <= 0 {
+		return f.part.Read(p)
+	}
+
+	if f.exceeded {
+		return 0, f.errTooLarge()
+	}
+
+	if remain := f.maxSize - f.read + 1; int64(len(p)) > remain {
+		p = p[:remain]
+	}
+
+	n, err := f.part.Read(p)
+	f.read += int64(n)
+
+	if f.read > f.maxSize {
+		f.exceeded = true
+		return n - int(f.read-f.maxSize), f.errTooLarge()
+	}
+
+	return n, err
+}
+
+func (f *filePart) errTooLarge() error {
+	return &ErrPartTooLarge{Name: f.part.FormName(), Filename: f.part.FileName(), MaxSize: f.maxSize}
+}
+
+func (f *filePart) Close() error {
+	return f.part.Close()
+}
+
+func (p *multipartTransformer) readAsStream(ctx context.Context, body io.Closer, reader *multipart.Reader, pv *internal.ParamValue, fields *jsonflags.StructFields, stream *jsonflags.StructField) error {
+	limits, err := partLimitsOf(stream)
+	if err != nil {
+		return err
+	}
+
+	values := url.Values{}
+
+	// 与 ReadForm 一致，限制文件之前字段的总大小与数量
+	size := int64(0)
+	parts := 0
+
+	var first *multipart.Part
+
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return err
+		}
+
+		if part.FileName() != "" {
+			first = part
+			break
+		}
+
+		if parts++; parts > defaultMaxParts {
+			return &ErrFormTooLarge{MaxParts: defaultMaxParts}
+		}
+
+		data, err := io.ReadAll(io.LimitReader(part, defaultMaxMemory+1))
+		if err != nil {
+			return err
+		}
+		if len(data) > defaultMaxMemory {
+			return &ErrPartTooLarge{Name: part.FormName(), MaxSize: defaultMaxMemory}
+		}
+
+		if size += int64(len(part.FormName()) + len(data)); size > defaultMaxMemory {
+			return &ErrFormTooLarge{MaxSize: defaultMaxMemory}
+		}
+
+		values.Add(part.FormName(), string(data))
+	}
+
+	// 文件之前的字段先行解析，出错时不再读取文件
+	var errs []error
+
+	for sf := range fields.StructField() {
+		if sf == stream || sf.Type.Implements(withFilenameType) || pv.CanMultiple(sf) && sf.Type.Elem().Implements(withFilenameType) {
+			continue
+		}
+
+		if err := pv.UnmarshalValues(ctx, sf, values[sf.Name]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if err := validatorerrors.Join(errs...); err != nil {
+		return err
+	}
+
+	stream.GetOrNewAt(pv.Value).Set(reflect.ValueOf(fileHeaderSeq(body, reader, first, limits)))
+
+	return nil
+}
+
+func fileHeaderSeq(body io.Closer, reader *multipart.Reader, first *multipart.Part, limits *partLimits) iter.Seq2[internal.FileHeader, error] {
+	consumed := false
+
+	return func(yield func(internal.FileHeader, error) bool) {
+		if consumed {
+			yield(nil, errors.New("multipart stream can only be consumed once"))
+			return
+		}
+		consumed = true
+
+		defer body.Close()
+
+		for part := first; part != nil; {
+			f, err := limits.open(part)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			ok := yield(f, nil)
+			_ = f.Close()
+			if !ok {
+				return
+			}
+
+			next, err := reader.NextPart()
+			if err != nil {
+				if !errors.Is(err, io.EOF) {
+					yield(nil, err)
+				}
+				return
+			}
+			part = next
+		}
+	}
+}
+
+var sizeUnits = []struct {
+	suffix string
+	n      int64
+}{
+	{"KiB", 1 << 10},
+	{"MiB", 1 << 20},
+	{"GiB", 1 << 30},
+	{"KB", 1000},
+	{"MB", 1000 * 1000},
+	{"GB", 1000 * 1000 * 1000},
+	{"B", 1},
+}
+
+func parseSize(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+
+	for _, u := range sizeUnits {
+		if v, ok := strings.CutSuffix(s, u.suffix); ok {
+			n, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+			if err != nil {
+				return 0, err
+			}
+			return n * u.n, nil
+		}
+	}
+
+	return strconv.ParseInt(s, 10, 64)
+}
@@ -1,15 +1,25 @@
 package transformers_test
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
 	"io"
+	"iter"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
 	"reflect"
+	"strings"
 	"testing"
 
 	. "github.com/octohelm/x/testing/v2"
 
 	"github.com/octohelm/courier/internal/testingutil"
+	"github.com/octohelm/courier/pkg/content"
 	"github.com/octohelm/courier/pkg/content/internal"
+	"github.com/octohelm/courier/pkg/content/transformers"
 )
 
 func TestMultipartTransformerRoundTrip(t *testing.T) {
@@ -144,3 +154,236 @@ func (f File) ContentType() string {
 func (f File) Filename() string {
 	return f.Name
 }
+
+func TestMultipartTransformerStreaming(t *testing.T) {
+	type Data struct {
+		A     string                                `json:"a" validate:"@string[1,]"`
+		Files iter.Seq2[internal.FileHeader, error] `json:"files" maxSize:"8B" accept:"text/*"`
+	}
+
+	newRequest := func(a string, files ...File) *http.Request {
+		b := bytes.NewBuffer(nil)
+		w := multipart.NewWriter(b)
+		_ = w.WriteField("a", a)
+		for _, f := range files {
+			h := textproto.MIMEHeader{}
+			h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="files"; filename=%q`, f.Name))
+			h.Set("Content-Type", f.Type)
+			pw, _ := w.CreatePart(h)
+			_, _ = pw.Write(f.Data)
+		}
+		_ = w.Close()
+
+		req, _ := http.NewRequest(http.MethodPost, "/", b)
+		req.Header.Set("Content-Type", w.FormDataContentType())
+		return req
+	}
+
+	consume := func(seq iter.Seq2[internal.FileHeader, error]) (names []string, err error) {
+		for f, err := range seq {
+			if err != nil {
+				return names, err
+			}
+			data, err := io.ReadAll(f)
+			if err != nil {
+				return names, err
+			}
+			names = append(names, f.Filename()+":"+string(data))
+		}
+		return names, nil
+	}
+
+	Then(t, "文件按顺序流式读取",
+		ExpectMust(func() error {
+			op := struct {
+				Body Data `in:"body" mime:"multipart"`
+			}{}
+
+			req := newRequest("s",
+				File{Name: "1.txt", Type: "text/plain", Data: []byte("one")},
+				File{Name: "2.txt", Type: "text/plain", Data: []byte("two")},
+			)
+			if err := internal.UnmarshalRequest(req, &op); err != nil {
+				return err
+			}
+			if op.Body.A != "s" {
+				return errContent("unexpected field value")
+			}
+			names, err := consume(op.Body.Files)
+			if err != nil {
+				return err
+			}
+			if !reflect.DeepEqual(names, []string{"1.txt:one", "2.txt:two"}) {
+				return errContent("unexpected files")
+			}
+			return nil
+		}),
+	)
+
+	Then(t, "文件之前的字段先行校验",
+		ExpectMust(func() error {
+			op := struct {
+				Body Data `in:"body" mime:"multipart"`
+			}{}
+			if err := internal.UnmarshalRequest(newRequest("", File{Name: "1.txt", Type: "text/plain", Data: []byte("one")}), &op); err == nil {
+				return errContent("expected validation error")
+			}
+			return nil
+		}),
+	)
+
+	Then(t, "超出大小或类型不允许时返回错误",
+		ExpectMust(func() error {
+			op := struct {
+				Body Data `in:"body" mime:"multipart"`
+			}{}
+			if err := internal.UnmarshalRequest(newRequest("s", File{Name: "1.txt", Type: "text/plain", Data: []byte("0123456789")}), &op); err != nil {
+				return err
+			}
+			if _, err := consume(op.Body.Files); !errors.As(err, new(*transformers.ErrPartTooLarge)) {
+				return errContent("expected ErrPartTooLarge")
+			}
+			return nil
+		}),
+		ExpectMust(func() error {
+			op := struct {
+				Body Data `in:"body" mime:"multipart"`
+			}{}
+			if err := internal.UnmarshalRequest(newRequest("s", File{Name: "1.txt", Type: "text/plain", Data: []byte("0123456789")}), &op); err != nil {
+				return err
+			}
+			for f, err := range op.Body.Files {
+				if err != nil {
+					return err
+				}
+				p := make([]byte, 4)
+				for {
+					if _, err := f.Read(p); err != nil {
+						break
+					}
+				}
+				// 超出大小后的读取不返回负数
+				if n, err := f.Read(p); n != 0 || !errors.As(err, new(*transformers.ErrPartTooLarge)) {
+					return errContent("expected ErrPartTooLarge after exceeded")
+				}
+			}
+			return nil
+		}),
+		ExpectMust(func() error {
+			op := struct {
+				Body Data `in:"body" mime:"multipart"`
+			}{}
+			// 文件之前的字段超出内存上限时报错而非截断
+			err := internal.UnmarshalRequest(newRequest(strings.Repeat("a", 32<<20+1), File{Name: "1.txt", Type: "text/plain", Data: []byte("one")}), &op)
+			if !errors.As(err, new(*transformers.ErrPartTooLarge)) {
+				return errContent("expected ErrPartTooLarge for field")
+			}
+			return nil
+		}),
+		ExpectMust(func() error {
+			op := struct {
+				Body Data `in:"body" mime:"multipart"`
+			}{}
+			if err := internal.UnmarshalRequest(newRequest("s", File{Name: "1.png", Type: "image/png", Data: []byte("png")}), &op); err != nil {
+				return err
+			}
+			if _, err := consume(op.Body.Files); !errors.As(err, new(*transformers.ErrPartContentTypeNotAllowed)) {
+				return errContent("expected ErrPartContentTypeNotAllowed")
+			}
+			return nil
+		}),
+	)
+	newFieldsRequest := func(n int, value string) *http.Request {
+		b := bytes.NewBuffer(nil)
+		w := multipart.NewWriter(b)
+		for i := range n {
+			_ = w.WriteField(fmt.Sprintf("f%d", i), value)
+		}
+		_ = w.Close()
+
+		req, _ := http.NewRequest(http.MethodPost, "/", b)
+		req.Header.Set("Content-Type", w.FormDataContentType())
+		return req
+	}
+
+	Then(t, "文件之前的字段总数或总大小超出上限时报错",
+		ExpectMust(func() error {
+			op := struct {
+				Body Data `in:"body" mime:"multipart"`
+			}{}
+			if err := internal.UnmarshalRequest(newFieldsRequest(1001, "v"), &op); !errors.As(err, new(*transformers.ErrFormTooLarge)) {
+				return errContent("expected ErrFormTooLarge for too many fields")
+			}
+			return nil
+		}),
+		ExpectMust(func() error {
+			op := struct {
+				Body Data `in:"body" mime:"multipart"`
+			}{}
+			if err := internal.UnmarshalRequest(newFieldsRequest(3, strings.Repeat("a", 12<<20)), &op); !errors.As(err, new(*transformers.ErrFormTooLarge)) {
+				return errContent("expected ErrFormTooLarge for too large fields")
+			}
+			return nil
+		}),
+	)
+}
+
+func TestMultipartTransformerContentLength(t *testing.T) {
+	type Data struct {
+		A    string    `json:"a"`
+		File io.Reader `json:"file"`
+	}
+
+	Then(t, "part 长度均已知时计算 Content-Length 并上报上传进度",
+		ExpectMust(func() error {
+			written := int64(0)
+			total := int64(0)
+
+			ctx := content.ContextWithUploadProgress(context.Background(), func(p content.UploadProgress) {
+				written = p.Written
+				total = p.Total
+			})
+
+			req, err := internal.NewRequest(ctx, "POST", "/", struct {
+				Body Data `in:"body" mime:"multipart"`
+			}{
+				Body: Data{A: "s", File: strings.NewReader("text")},
+			})
+			if err != nil {
+				return err
+			}
+
+			data, err := io.ReadAll(req.Body)
+			if err != nil {
+				return err
+			}
+
+			if req.ContentLength != int64(len(data)) {
+				return errContent(fmt.Sprintf("content length %d not match body size %d", req.ContentLength, len(data)))
+			}
+			if written != req.ContentLength || total != req.ContentLength {
+				return errContent("unexpected upload progress")
+			}
+			return nil
+		}),
+	)
+
+	Then(t, "part 长度未知时不设置 Content-Length",
+		ExpectMust(func() error {
+			req, err := internal.NewRequest(context.Background(), "POST", "/", struct {
+				Body Data `in:"body" mime:"multipart"`
+			}{
+				Body: Data{A: "s", File: io.MultiReader(strings.NewReader("text"))},
+			})
+			if err != nil {
+				return err
+			}
+			defer req.Body.Close()
+
+			if req.ContentLength > 0 {
+				return errContent("unexpected content length")
+			}
+			return nil
+		}),
+	)
+}
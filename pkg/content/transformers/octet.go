@@ -122,9 +122,11 @@ func (p *octecTransformer) Prepare(ctx context.Context, v any) (internal.Content
 
 	switch x := v.(type) {
 	case io.ReadCloser:
+		c.SetContentLength(lenOf(x))
 		c.ReadCloser = x
 		return c, nil
 	case io.Reader:
+		c.SetContentLength(lenOf(x))
 		c.ReadCloser = io.NopCloser(x)
 		return c, nil
 	case []byte:
@@ -149,3 +151,11 @@ func (p *octecTransformer) Prepare(ctx context.Context, v any) (internal.Content
 		return c, nil
 	}
 }
+
+// lenOf 返回 reader 剩余可读的长度，如 *bytes.Reader、*strings.Reader，未知时返回 -1
+func lenOf(r io.Reader) int64 {
+	if x, ok := r.(interface{ Len() int }); ok {
+		return int64(x.Len())
+	}
+	return -1
+}
@@ -27,6 +27,22 @@ func (v directJSON) MarshalJSON() ([]byte, error) {
 	return []byte(`{"value":"` + v.Value + `"}`), nil
 }
 
+type jsonReader struct {
+	strings.Reader
+}
+
+func (jsonReader) MarshalJSON() ([]byte, error) {
+	return []byte(`{}`), nil
+}
+
+type textReader struct {
+	strings.Reader
+}
+
+func (textReader) MarshalText() ([]byte, error) {
+	return []byte("text"), nil
+}
+
 type failingJSON struct{}
 
 func (f failingJSON) MarshalJSON() ([]byte, error) {
@@ -58,6 +74,10 @@ func TestTransformerMediaTypesAndFallbacks(t *testing.T) {
 		}{
 			{typ: reflect.TypeFor[string](), action: "marshal", expect: "text/plain"},
 			{typ: reflect.TypeFor[[]byte](), action: "marshal", expect: "application/octet-stream"},
+			{typ: reflect.TypeFor[io.Reader](), action: "marshal", expect: "application/octet-stream"},
+			{typ: reflect.TypeFor[io.ReadCloser](), action: "marshal", expect: "application/octet-stream"},
+			{typ: reflect.TypeFor[*jsonReader](), action: "marshal", expect: "application/json"},
+			{typ: reflect.TypeFor[*textReader](), action: "marshal", expect: "text/plain"},
 			{typ: reflect.TypeFor[struct{ Name string }](), mediaType: "json", action: "marshal", expect: "application/json"},
 			{typ: reflect.TypeFor[struct{ Name string }](), mediaType: "urlencoded", action: "marshal", expect: "application/x-www-form-urlencoded"},
 			{typ: reflect.TypeFor[struct{ Name string }](), mediaType: "multipart", action: "marshal", expect: "multipart/form-data"},
@@ -69,7 +89,7 @@ func TestTransformerMediaTypesAndFallbacks(t *testing.T) {
 				return err
 			}
 			if tr.MediaType() != c.expect {
-				return errTransformer("unexpected media type: " + tr.MediaType())
+				return errTransformer("unexpected media type of " + c.typ.String() + ": " + tr.MediaType())
 			}
 		}
 		return nil
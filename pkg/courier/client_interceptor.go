@@ -0,0 +1,87 @@
+package courier
+
+import (
+	"context"
+)
+
+// Invoker 执行一次客户端调用。
+type Invoker = func(ctx context.Context, req any, metas ...Metadata) Result
+
+// Interceptor 拦截客户端调用。
+//
+// 可在调用前按请求 operator 类型调整请求或元数据，也可用 WrapResult 包装返回的 Result，
+// 在 Into 之后处理解码后的结果与错误。
+type Interceptor = func(next Invoker) Invoker
+
+// ChainInterceptors 组合多个 Interceptor，与 HttpTransports 一致，后声明的在外层。
+func ChainInterceptors(interceptors ...Interceptor) Interceptor {
+	return func(next Invoker) Invoker {
+		for _, intercept := range interceptors {
+			next = intercept(next)
+		}
+		return next
+	}
+}
+
+// InterceptorFor 创建仅拦截 Op 类型请求的 Interceptor，其他请求直接透传。
+func InterceptorFor[Op any](intercept func(ctx context.Context, req Op, next Invoker, metas ...Metadata) Result) Interceptor {
+	return func(next Invoker) Invoker {
+		return func(ctx context.Context, req any, metas ...Metadata) Result {
+			if op, ok := req.(Op); ok {
+				return intercept(ctx, op, next, metas...)
+			}
+			return next(ctx, req, metas...)
+		}
+	}
+}
+
+// Intercept 为任意 Client 添加拦截器。
+func Intercept(c Client, interceptors ...Interceptor) Client {
+	return &interceptedClient{
+		do: ChainInterceptors(interceptors...)(c.Do),
+	}
+}
+
+type interceptedClient struct {
+	do Invoker
+}
+
+func (c *interceptedClient) Do(ctx context.Context, req any, metas ...Metadata) Result {
+	return c.do(ctx, req, metas...)
+}
+
+// ResultFunc 以函数实现 Result。
+type ResultFunc func(v any) (Metadata, error)
+
+func (fn ResultFunc) Into(v any) (Metadata, error) {
+	return fn(v)
+}
+
+// WrapResult 以 into 替换 r 的 Into，并转发 r 的 StatusCode 与 Meta，
+// 使包装后的 Result 仍可获取响应状态码与元数据。
+func WrapResult(r Result, into func(v any) (Metadata, error)) Result {
+	return &wrappedResult{Result: r, into: into}
+}
+
+type wrappedResult struct {
+	Result
+	into func(v any) (Metadata, error)
+}
+
+func (r *wrappedResult) Into(v any) (Metadata, error) {
+	return r.into(v)
+}
+
+func (r *wrappedResult) StatusCode() int {
+	if x, ok := r.Result.(interface{ StatusCode() int }); ok {
+		return x.StatusCode()
+	}
+	return 0
+}
+
+func (r *wrappedResult) Meta() Metadata {
+	if x, ok := r.Result.(interface{ Meta() Metadata }); ok {
+		return x.Meta()
+	}
+	return Metadata{}
+}
@@ -0,0 +1,59 @@
+package courier
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	. "github.com/octohelm/x/testing/v2"
+)
+
+type getUser struct {
+	ID string
+}
+
+var errUserNotFound = errors.New("user not found")
+
+func TestInterceptors(t *testing.T) {
+	var calls []string
+
+	trace := func(name string) Interceptor {
+		return func(next Invoker) Invoker {
+			return func(ctx context.Context, req any, metas ...Metadata) Result {
+				calls = append(calls, name)
+				return next(ctx, req, metas...)
+			}
+		}
+	}
+
+	c := Intercept(
+		testClient{result: testResult{into: func(v any) error { return errors.New("404 NotFound") }}},
+		trace("inner"),
+		InterceptorFor(func(ctx context.Context, req *getUser, next Invoker, metas ...Metadata) Result {
+			r := next(ctx, req, append(metas, Metadata{"X-User-Id": {req.ID}})...)
+
+			return WrapResult(r, func(v any) (Metadata, error) {
+				meta, err := r.Into(v)
+				if err != nil && strings.HasPrefix(err.Error(), "404") {
+					return meta, errUserNotFound
+				}
+				return meta, err
+			})
+		}),
+		trace("outer"),
+	)
+
+	_, err := c.Do(context.Background(), &getUser{ID: "1"}).Into(nil)
+
+	Then(t, "按声明顺序由内向外组合，并按请求类型转换错误",
+		Expect(calls, Equal([]string{"outer", "inner"})),
+		Expect(errors.Is(err, errUserNotFound), Equal(true)),
+	)
+
+	_, err = c.Do(context.Background(), &struct{}{}).Into(nil)
+
+	Then(t, "其他类型的请求不受影响",
+		Expect(errors.Is(err, errUserNotFound), Equal(false)),
+	)
+}
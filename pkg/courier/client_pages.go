@@ -0,0 +1,72 @@
+package courier
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"reflect"
+)
+
+// Pageable 表示分页响应数据。
+type Pageable[T any] interface {
+	PageItems() []T
+	// NextPageToken 返回下一页标记，为空表示已到末页
+	NextPageToken() string
+}
+
+// PageTokenSetter 表示可设置分页标记的请求。
+type PageTokenSetter interface {
+	SetPageToken(token string)
+}
+
+// DoPages 依次请求所有分页，并逐条产出数据。
+//
+// req 需同时声明 `ResponseData()` 且其返回值实现 Pageable[T]；翻页时会就地更新 req 的分页标记。
+// 出错时产出一次错误并终止；服务端返回的下一页标记与刚请求的标记相同时视为出错，避免无限翻页。
+func DoPages[T any](ctx context.Context, c Client, req PageTokenSetter, metas ...Metadata) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+
+		newResponseData := reflect.ValueOf(req).MethodByName("ResponseData")
+		if !newResponseData.IsValid() || newResponseData.Type().NumIn() != 0 || newResponseData.Type().NumOut() != 1 {
+			yield(zero, fmt.Errorf("%T 缺少 ResponseData 方法", req))
+			return
+		}
+
+		requested := ""
+
+		for {
+			data := newResponseData.Call(nil)[0].Interface()
+
+			page, ok := data.(Pageable[T])
+			if !ok {
+				yield(zero, fmt.Errorf("%T 的响应 %T 不是分页数据", req, data))
+				return
+			}
+
+			if _, err := c.Do(ctx, req, metas...).Into(data); err != nil {
+				yield(zero, err)
+				return
+			}
+
+			for _, item := range page.PageItems() {
+				if !yield(item, nil) {
+					return
+				}
+			}
+
+			token := page.NextPageToken()
+			if token == "" {
+				return
+			}
+
+			if token == requested {
+				yield(zero, fmt.Errorf("%T 的下一页标记 %q 与当前页相同", req, token))
+				return
+			}
+
+			requested = token
+			req.SetPageToken(token)
+		}
+	}
+}
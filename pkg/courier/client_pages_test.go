@@ -0,0 +1,56 @@
+package courier
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/octohelm/x/testing/v2"
+)
+
+type listItems struct {
+	PageToken string
+}
+
+func (req *listItems) SetPageToken(token string) {
+	req.PageToken = token
+}
+
+func (*listItems) ResponseData() *itemPage {
+	return new(itemPage)
+}
+
+type itemPage struct {
+	Items []string
+	Next  string
+}
+
+func (p *itemPage) PageItems() []string {
+	return p.Items
+}
+
+func (p *itemPage) NextPageToken() string {
+	return p.Next
+}
+
+func TestDoPages(t *testing.T) {
+	c := testClient{result: testResult{into: func(v any) error {
+		*v.(*itemPage) = itemPage{Items: []string{"a"}, Next: "loop"}
+		return nil
+	}}}
+
+	items := make([]string, 0)
+	var err error
+
+	for item, e := range DoPages[string](context.Background(), c, &listItems{}) {
+		if e != nil {
+			err = e
+			break
+		}
+		items = append(items, item)
+	}
+
+	Then(t, "下一页标记重复时终止翻页并返回错误",
+		Expect(items, Equal([]string{"a", "a"})),
+		Expect(err.Error(), Equal(`*courier.listItems 的下一页标记 "loop" 与当前页相同`)),
+	)
+}
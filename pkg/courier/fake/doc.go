@@ -0,0 +1,14 @@
+// Package fake 提供用于单元测试的 `courier.Client` 实现。
+//
+// 按请求 operator 类型注册处理函数，无需启动 HTTP 服务即可测试依赖生成客户端的代码：
+//
+//	c := &fake.Client{}
+//	fake.On(c, func(ctx context.Context, req *ListOrgs) (*OrgList, error) {
+//		return &OrgList{}, nil
+//	})
+//
+// 响应经 JSON 编解码写入 `Result.Into` 的目标，处理函数返回的错误按服务端的错误响应格式编码后，
+// 再如真实客户端一样还原为 `*statuserror.Descriptor`。调用记录可通过 `Calls` 与 `CallsOf` 断言。
+//
+// +gengo:runtimedoc=false
+package fake
@@ -0,0 +1,180 @@
+package fake
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"slices"
+	"sync"
+
+	"github.com/go-json-experiment/json"
+
+	"github.com/octohelm/courier/pkg/courier"
+	"github.com/octohelm/courier/pkg/statuserror"
+)
+
+// Client 为按请求 operator 类型分发的 courier.Client。
+type Client struct {
+	// 错误来源，对应 statuserror.Descriptor 的 Source
+	Source string
+	// 与 client.Client 一致，用于自定义错误的解码目标
+	NewError func() error
+
+	mu       sync.Mutex
+	handlers map[reflect.Type]handler
+	calls    []Call
+}
+
+type handler = func(ctx context.Context, req any) (any, error)
+
+// Call 为一次调用记录。
+type Call struct {
+	Operator any
+	Metadata courier.Metadata
+}
+
+// On 注册 Op 类型请求的处理函数，重复注册时覆盖。
+func On[Op any, Resp any](c *Client, fn func(ctx context.Context, req Op) (Resp, error)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.handlers == nil {
+		c.handlers = map[reflect.Type]handler{}
+	}
+
+	c.handlers[reflect.TypeFor[Op]()] = func(ctx context.Context, req any) (any, error) {
+		return fn(ctx, req.(Op))
+	}
+}
+
+// Calls 返回全部调用记录。
+func (c *Client) Calls() []Call {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return slices.Clone(c.calls)
+}
+
+// CallsOf 返回 Op 类型请求的调用记录。
+func CallsOf[Op any](c *Client) []Op {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var ops []Op
+	for _, call := range c.calls {
+		if op, ok := call.Operator.(Op); ok {
+			ops = append(ops, op)
+		}
+	}
+	return ops
+}
+
+// Reset 清空调用记录。
+func (c *Client) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.calls = nil
+}
+
+func (c *Client) Do(ctx context.Context, req any, metas ...courier.Metadata) courier.Result {
+	c.mu.Lock()
+	c.calls = append(c.calls, Call{Operator: req, Metadata: courier.FromMetas(metas...)})
+	h, ok := c.handlers[reflect.TypeOf(req)]
+	c.mu.Unlock()
+
+	if !ok {
+		return &result{c: c, err: &ErrNoHandler{Type: fmt.Sprintf("%T", req)}}
+	}
+
+	resp, err := h(ctx, req)
+
+	return &result{c: c, resp: resp, err: err}
+}
+
+type ErrNoHandler struct {
+	statuserror.NotImplemented
+
+	Type string
+}
+
+func (e *ErrNoHandler) Error() string {
+	return fmt.Sprintf("no fake handler registered for %s", e.Type)
+}
+
+type result struct {
+	c    *Client
+	resp any
+	err  error
+}
+
+func (r *result) Into(v any) (courier.Metadata, error) {
+	meta := courier.Metadata{}
+	if carrier, ok := r.resp.(courier.MetadataCarrier); ok {
+		meta = carrier.Meta()
+	}
+
+	if r.err != nil {
+		return meta, r.decodeError()
+	}
+
+	switch x := v.(type) {
+	case nil:
+		return meta, nil
+	case *any:
+		return meta, nil
+	case *io.ReadCloser:
+		if rc, ok := r.resp.(io.ReadCloser); ok {
+			*x = rc
+			return meta, nil
+		}
+	}
+
+	if r.resp == nil {
+		return meta, nil
+	}
+
+	// 经 JSON 编解码，与真实响应一样不共享引用
+	data, err := json.Marshal(r.resp)
+	if err != nil {
+		return meta, statuserror.Wrap(err, http.StatusInternalServerError, "ResponseEncodeFailed")
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return meta, statuserror.Wrap(fmt.Errorf("unmarshal to %T failed: %w", v, err), http.StatusInternalServerError, "ResponseDecodeFailed")
+	}
+
+	return meta, nil
+}
+
+// decodeError 按服务端错误响应编码后再解码，与真实客户端收到的错误一致
+func (r *result) decodeError() error {
+	errResp := statuserror.AsErrorResponse(r.err, r.c.Source)
+
+	raw, err := json.Marshal(errResp)
+	if err != nil {
+		return err
+	}
+
+	var target error = &statuserror.Descriptor{}
+	if r.c.NewError != nil {
+		target = r.c.NewError()
+	}
+
+	switch x := target.(type) {
+	case interface {
+		UnmarshalErrorResponse(statusCode int, respBody []byte) error
+	}:
+		if err := x.UnmarshalErrorResponse(errResp.StatusCode(), raw); err != nil {
+			return err
+		}
+	default:
+		if err := json.Unmarshal(raw, target); err != nil {
+			return err
+		}
+	}
+
+	return target
+}
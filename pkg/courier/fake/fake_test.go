@@ -0,0 +1,91 @@
+package fake_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	. "github.com/octohelm/x/testing/v2"
+
+	"github.com/octohelm/courier/pkg/courier"
+	"github.com/octohelm/courier/pkg/courier/fake"
+	"github.com/octohelm/courier/pkg/statuserror"
+)
+
+type Org struct {
+	Name string `json:"name"`
+}
+
+type OrgList struct {
+	Items []Org `json:"items"`
+}
+
+type ListOrgs struct {
+	Size int
+}
+
+func (ListOrgs) ResponseData() *OrgList {
+	return new(OrgList)
+}
+
+type DeleteOrg struct {
+	Name string
+}
+
+func (DeleteOrg) ResponseData() *courier.NoContent {
+	return new(courier.NoContent)
+}
+
+type ErrOrgNotFound struct {
+	statuserror.NotFound
+
+	Name string
+}
+
+func (e *ErrOrgNotFound) Error() string {
+	return "org " + e.Name + " not found"
+}
+
+func TestClient(t *testing.T) {
+	c := &fake.Client{}
+
+	fake.On(c, func(ctx context.Context, req *ListOrgs) (*OrgList, error) {
+		return &OrgList{Items: []Org{{Name: "a"}}[:req.Size]}, nil
+	})
+
+	fake.On(c, func(ctx context.Context, req *DeleteOrg) (*courier.NoContent, error) {
+		return nil, &ErrOrgNotFound{Name: req.Name}
+	})
+
+	list, err := courier.DoWith(context.Background(), c, &ListOrgs{Size: 1}, courier.Metadata{"X-Trace": {"1"}})
+
+	Then(t, "按请求类型返回响应",
+		Expect(err, Equal[error](nil)),
+		Expect(list, Equal(&OrgList{Items: []Org{{Name: "a"}}})),
+	)
+
+	_, err = courier.DoWith(context.Background(), c, &DeleteOrg{Name: "x"})
+
+	descriptor := &statuserror.Descriptor{}
+
+	Then(t, "错误与远程调用解码后的错误一致",
+		Expect(errors.As(err, &descriptor), Equal(true)),
+		Expect(descriptor.StatusCode(), Equal(http.StatusNotFound)),
+		Expect(descriptor.Code, Equal(statuserror.ErrCodeOf(&ErrOrgNotFound{}))),
+		Expect(descriptor.Message, Equal("org x not found")),
+	)
+
+	_, err = c.Do(context.Background(), &struct{}{}).Into(nil)
+
+	Then(t, "未注册的请求返回 501",
+		Expect(errors.As(err, &descriptor), Equal(true)),
+		Expect(descriptor.StatusCode(), Equal(http.StatusNotImplemented)),
+	)
+
+	Then(t, "记录调用",
+		Expect(len(c.Calls()), Equal(3)),
+		Expect(c.Calls()[0].Metadata.Get("X-Trace"), Equal("1")),
+		Expect(fake.CallsOf[*DeleteOrg](c), Equal([]*DeleteOrg{{Name: "x"}})),
+	)
+}
@@ -0,0 +1,36 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/octohelm/courier/pkg/courier"
+	"github.com/octohelm/courier/pkg/courierhttp"
+)
+
+// NewRouter 创建挂载于 basePath 的管理路由，所有接口需先通过 authorizer 鉴权。
+//
+// authorizer 为中间 operator，其 Output 返回错误即拒绝访问；为 nil 时拒绝所有请求。
+func NewRouter(basePath string, authorizer courier.Operator) courier.Router {
+	if authorizer == nil {
+		authorizer = &DenyAll{}
+	}
+
+	return courier.NewRouter(courierhttp.Group(basePath), authorizer).With(
+		courier.NewRouter(&Profiles{}),
+		courier.NewRouter(&Profile{}),
+		courier.NewRouter(&Goroutines{}),
+		courier.NewRouter(&BuildInfo{}),
+		courier.NewRouter(&Routes{}),
+		courier.NewRouter(&Operations{}),
+		courier.NewRouter(&RuntimeStats{}),
+	)
+}
+
+type handlerUpgrader struct {
+	http.Handler
+}
+
+func (h handlerUpgrader) Upgrade(rw http.ResponseWriter, req *http.Request) error {
+	h.ServeHTTP(rw, req)
+	return nil
+}
@@ -0,0 +1,81 @@
+package admin_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	. "github.com/octohelm/x/testing/v2"
+
+	"github.com/octohelm/courier/pkg/courierhttp"
+	"github.com/octohelm/courier/pkg/courierhttp/admin"
+	"github.com/octohelm/courier/pkg/courierhttp/handler/httprouter"
+)
+
+func TestAdmin(t *testing.T) {
+	h, err := httprouter.New(courierhttp.GroupRouter("/").With(
+		admin.NewRouter("/admin", admin.NewTokenAuthorizer("secret")),
+	), "test")
+	Then(t, "构建 httprouter handler 成功", Expect(err, Equal[error](nil)))
+
+	serve := func(path string, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rw := httptest.NewRecorder()
+		h.ServeHTTP(rw, req)
+		return rw
+	}
+
+	Then(t, "未携带或携带错误 token 时拒绝访问",
+		Expect(serve("/admin/runtime", "").Code, Equal(http.StatusUnauthorized)),
+		Expect(serve("/admin/runtime", "wrong").Code, Equal(http.StatusUnauthorized)),
+	)
+
+	routes := serve("/admin/routes", "secret")
+	Then(t, "路由表包含管理接口自身",
+		Expect(routes.Code, Equal(http.StatusOK)),
+		Expect(strings.Contains(routes.Body.String(), `"path":"/admin/routes"`), Equal(true)),
+		Expect(strings.Contains(routes.Body.String(), `"admin.TokenAuthorizer"`), Equal(true)),
+	)
+
+	operations := serve("/admin/operations", "secret")
+	Then(t, "可列出 OpenAPI 操作",
+		Expect(operations.Code, Equal(http.StatusOK)),
+		Expect(strings.Contains(operations.Body.String(), `"operationID":"RuntimeStats"`), Equal(true)),
+	)
+
+	Then(t, "可获取构建信息与运行时统计",
+		Expect(strings.Contains(serve("/admin/build-info", "secret").Body.String(), `"goVersion"`), Equal(true)),
+		Expect(strings.Contains(serve("/admin/runtime", "secret").Body.String(), `"numGoroutine"`), Equal(true)),
+	)
+
+	Then(t, "可获取 pprof profile 与 goroutine 堆栈",
+		Expect(strings.Contains(serve("/admin/pprof", "secret").Body.String(), `"name":"heap"`), Equal(true)),
+		Expect(serve("/admin/pprof/heap?debug=1", "secret").Code, Equal(http.StatusOK)),
+		Expect(strings.HasPrefix(serve("/admin/goroutines", "secret").Body.String(), "goroutine "), Equal(true)),
+	)
+
+	Then(t, "可获取命令行参数，未知 profile 返回 404",
+		Expect(serve("/admin/pprof/cmdline", "secret").Body.String(), Equal(strings.Join(os.Args, "\x00"))),
+		Expect(serve("/admin/pprof/unknown", "secret").Code, Equal(http.StatusNotFound)),
+	)
+
+	_, pattern := http.DefaultServeMux.Handler(httptest.NewRequest(http.MethodGet, "/debug/pprof/", nil))
+	Then(t, "不在 http.DefaultServeMux 上注册 pprof",
+		Expect(pattern, Equal("")),
+	)
+}
+
+func TestAdminDenyAll(t *testing.T) {
+	h, err := httprouter.New(courierhttp.GroupRouter("/").With(admin.NewRouter("/admin", nil)), "test")
+	Then(t, "构建 httprouter handler 成功", Expect(err, Equal[error](nil)))
+
+	rw := httptest.NewRecorder()
+	h.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/admin/runtime", nil))
+
+	Then(t, "未配置鉴权时拒绝所有访问", Expect(rw.Code, Equal(http.StatusForbidden)))
+}
@@ -0,0 +1,78 @@
+package admin
+
+import (
+	"context"
+	"crypto/subtle"
+	"strings"
+
+	"github.com/octohelm/courier/pkg/courier"
+	"github.com/octohelm/courier/pkg/statuserror"
+)
+
+type ErrUnauthorized struct {
+	statuserror.Unauthorized
+}
+
+func (e *ErrUnauthorized) Error() string {
+	return "admin access requires a valid token"
+}
+
+type ErrForbidden struct {
+	statuserror.Forbidden
+}
+
+func (e *ErrForbidden) Error() string {
+	return "admin access is disabled"
+}
+
+// DenyAll 拒绝所有访问，为未配置鉴权时的缺省行为。
+type DenyAll struct{}
+
+func (DenyAll) ResponseErrors() []error {
+	return []error{
+		&ErrForbidden{},
+	}
+}
+
+func (*DenyAll) Output(ctx context.Context) (any, error) {
+	return nil, &ErrForbidden{}
+}
+
+// NewTokenAuthorizer 创建以静态 Bearer Token 鉴权的中间 operator。
+func NewTokenAuthorizer(tokens ...string) *TokenAuthorizer {
+	return &TokenAuthorizer{tokens: tokens}
+}
+
+// TokenAuthorizer 校验 `Authorization: Bearer <token>`。
+type TokenAuthorizer struct {
+	Authorization string `name:"Authorization,omitzero" in:"header"`
+
+	tokens []string
+}
+
+func (a *TokenAuthorizer) InitFrom(o courier.Operator) {
+	if x, ok := o.(*TokenAuthorizer); ok {
+		a.tokens = x.tokens
+	}
+}
+
+func (TokenAuthorizer) ResponseErrors() []error {
+	return []error{
+		&ErrUnauthorized{},
+	}
+}
+
+func (a *TokenAuthorizer) Output(ctx context.Context) (any, error) {
+	token, ok := strings.CutPrefix(a.Authorization, "Bearer ")
+	if !ok || token == "" {
+		return nil, &ErrUnauthorized{}
+	}
+
+	for _, t := range a.tokens {
+		if subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1 {
+			return nil, nil
+		}
+	}
+
+	return nil, &ErrUnauthorized{}
+}
@@ -0,0 +1,9 @@
+// Package admin 提供可直接挂载的管理与调试 operator。
+//
+// `NewRouter` 在指定基础路径下注册 pprof、goroutine 堆栈、`debug.BuildInfo`、
+// 路由表、OpenAPI 操作列表与运行时统计等接口，
+// 所有接口都先经过传入的鉴权中间 operator，未提供时一律拒绝访问，
+// 可通过 `NewTokenAuthorizer` 使用静态 Bearer Token 鉴权。
+//
+// +gengo:runtimedoc=false
+package admin
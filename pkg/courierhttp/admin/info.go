@@ -0,0 +1,161 @@
+package admin
+
+import (
+	"context"
+	"runtime"
+	"runtime/debug"
+	"time"
+
+	"github.com/octohelm/courier/pkg/courierhttp"
+	"github.com/octohelm/courier/pkg/statuserror"
+)
+
+type ErrBuildInfoUnavailable struct {
+	statuserror.NotFound
+}
+
+func (e *ErrBuildInfoUnavailable) Error() string {
+	return "build info is unavailable"
+}
+
+// Module 描述构建依赖的模块。
+type Module struct {
+	Path    string  `json:"path"`
+	Version string  `json:"version,omitzero"`
+	Sum     string  `json:"sum,omitzero"`
+	Replace *Module `json:"replace,omitzero"`
+}
+
+// BuildInfoData 为 `debug.BuildInfo` 的输出格式。
+type BuildInfoData struct {
+	GoVersion string            `json:"goVersion"`
+	Path      string            `json:"path"`
+	Main      Module            `json:"main"`
+	Deps      []Module          `json:"deps,omitzero"`
+	Settings  map[string]string `json:"settings,omitzero"`
+}
+
+// BuildInfo 输出当前二进制的构建信息。
+type BuildInfo struct {
+	courierhttp.MethodGet `path:"/build-info"`
+}
+
+func (BuildInfo) ResponseErrors() []error {
+	return []error{
+		&ErrBuildInfoUnavailable{},
+	}
+}
+
+func (*BuildInfo) Output(ctx context.Context) (any, error) {
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return nil, &ErrBuildInfoUnavailable{}
+	}
+
+	data := &BuildInfoData{
+		GoVersion: bi.GoVersion,
+		Path:      bi.Path,
+		Main:      moduleOf(&bi.Main),
+		Deps:      make([]Module, 0, len(bi.Deps)),
+		Settings:  make(map[string]string, len(bi.Settings)),
+	}
+
+	for _, dep := range bi.Deps {
+		data.Deps = append(data.Deps, moduleOf(dep))
+	}
+
+	for _, s := range bi.Settings {
+		data.Settings[s.Key] = s.Value
+	}
+
+	return data, nil
+}
+
+func moduleOf(m *debug.Module) Module {
+	mod := Module{
+		Path:    m.Path,
+		Version: m.Version,
+		Sum:     m.Sum,
+	}
+	if m.Replace != nil {
+		replace := moduleOf(m.Replace)
+		mod.Replace = &replace
+	}
+	return mod
+}
+
+// MemoryStats 为 `runtime.MemStats` 中的常用指标，单位为字节。
+type MemoryStats struct {
+	Alloc       uint64 `json:"alloc"`
+	TotalAlloc  uint64 `json:"totalAlloc"`
+	Sys         uint64 `json:"sys"`
+	HeapAlloc   uint64 `json:"heapAlloc"`
+	HeapSys     uint64 `json:"heapSys"`
+	HeapInuse   uint64 `json:"heapInuse"`
+	HeapObjects uint64 `json:"heapObjects"`
+	StackInuse  uint64 `json:"stackInuse"`
+}
+
+// GCStats 描述垃圾回收情况。
+type GCStats struct {
+	NumGC         uint32    `json:"numGC"`
+	LastGC        time.Time `json:"lastGC,omitzero"`
+	PauseTotalNs  uint64    `json:"pauseTotalNs"`
+	NextGC        uint64    `json:"nextGC"`
+	GCCPUFraction float64   `json:"gcCPUFraction"`
+}
+
+// RuntimeStatsData 为运行时统计的输出格式。
+type RuntimeStatsData struct {
+	GoVersion    string      `json:"goVersion"`
+	GOOS         string      `json:"goos"`
+	GOARCH       string      `json:"goarch"`
+	NumCPU       int         `json:"numCPU"`
+	GOMAXPROCS   int         `json:"gomaxprocs"`
+	NumGoroutine int         `json:"numGoroutine"`
+	NumCgoCall   int64       `json:"numCgoCall"`
+	Memory       MemoryStats `json:"memory"`
+	GC           GCStats     `json:"gc"`
+}
+
+// RuntimeStats 输出运行时与 GC 统计。
+type RuntimeStats struct {
+	courierhttp.MethodGet `path:"/runtime"`
+}
+
+func (*RuntimeStats) Output(ctx context.Context) (any, error) {
+	ms := &runtime.MemStats{}
+	runtime.ReadMemStats(ms)
+
+	data := &RuntimeStatsData{
+		GoVersion:    runtime.Version(),
+		GOOS:         runtime.GOOS,
+		GOARCH:       runtime.GOARCH,
+		NumCPU:       runtime.NumCPU(),
+		GOMAXPROCS:   runtime.GOMAXPROCS(0),
+		NumGoroutine: runtime.NumGoroutine(),
+		NumCgoCall:   runtime.NumCgoCall(),
+		Memory: MemoryStats{
+			Alloc:       ms.Alloc,
+			TotalAlloc:  ms.TotalAlloc,
+			Sys:         ms.Sys,
+			HeapAlloc:   ms.HeapAlloc,
+			HeapSys:     ms.HeapSys,
+			HeapInuse:   ms.HeapInuse,
+			HeapObjects: ms.HeapObjects,
+			StackInuse:  ms.StackInuse,
+		},
+		GC: GCStats{
+			NumGC:         ms.NumGC,
+			PauseTotalNs:  ms.PauseTotalNs,
+			NextGC:        ms.NextGC,
+			GCCPUFraction: ms.GCCPUFraction,
+		},
+	}
+
+	if ms.LastGC > 0 {
+		data.GC.LastGC = time.Unix(0, int64(ms.LastGC)).UTC()
+	}
+
+	return data, nil
+}
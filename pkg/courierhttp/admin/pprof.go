@@ -0,0 +1,160 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/octohelm/courier/pkg/courierhttp"
+	"github.com/octohelm/courier/pkg/statuserror"
+)
+
+type ErrProfileNotFound struct {
+	statuserror.NotFound
+
+	Name string
+}
+
+func (e *ErrProfileNotFound) Error() string {
+	return fmt.Sprintf("profile %s not found", e.Name)
+}
+
+// ProfileInfo 描述一个可用的 pprof profile。
+type ProfileInfo struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// Profiles 列出可用的 pprof profile。
+type Profiles struct {
+	courierhttp.MethodGet `path:"/pprof"`
+}
+
+func (*Profiles) Output(ctx context.Context) (any, error) {
+	profiles := make([]ProfileInfo, 0)
+	for _, p := range pprof.Profiles() {
+		profiles = append(profiles, ProfileInfo{Name: p.Name(), Count: p.Count()})
+	}
+
+	for _, name := range []string{"cmdline", "profile", "trace"} {
+		profiles = append(profiles, ProfileInfo{Name: name})
+	}
+
+	slices.SortFunc(profiles, func(a, b ProfileInfo) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return profiles, nil
+}
+
+// Profile 输出指定的 pprof profile。
+//
+// `profile` 与 `trace` 按 seconds 采集 CPU profile 与执行追踪，其余 profile 支持 debug 与 gc 参数。
+// 直接基于 runtime/pprof 与 runtime/trace 实现，不引入 net/http/pprof，
+// 避免其 init 在 http.DefaultServeMux 上注册未经鉴权的 /debug/pprof/ 处理器。
+type Profile struct {
+	courierhttp.MethodGet `path:"/pprof/{name}"`
+
+	Name string `name:"name" in:"path"`
+	// 采集时长（秒），profile 默认 30，trace 默认 1
+	Seconds int `name:"seconds,omitzero" in:"query"`
+	// 非 0 时输出文本格式
+	Debug int `name:"debug,omitzero" in:"query"`
+	// 非 0 时在输出 heap 前执行 GC
+	GC int `name:"gc,omitzero" in:"query"`
+}
+
+func (Profile) ResponseErrors() []error {
+	return []error{
+		&ErrProfileNotFound{},
+	}
+}
+
+func (p *Profile) Output(ctx context.Context) (any, error) {
+	switch p.Name {
+	case "cmdline":
+		return handlerUpgrader{http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			_, _ = fmt.Fprint(rw, strings.Join(os.Args, "\x00"))
+		})}, nil
+	case "profile":
+		return handlerUpgrader{http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.Header().Set("Content-Type", "application/octet-stream")
+			rw.Header().Set("Content-Disposition", `attachment; filename="profile"`)
+
+			if err := pprof.StartCPUProfile(rw); err != nil {
+				rw.Header().Del("Content-Disposition")
+				http.Error(rw, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			sleep(req.Context(), p.duration(30*time.Second))
+			pprof.StopCPUProfile()
+		})}, nil
+	case "trace":
+		return handlerUpgrader{http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.Header().Set("Content-Type", "application/octet-stream")
+			rw.Header().Set("Content-Disposition", `attachment; filename="trace"`)
+
+			if err := trace.Start(rw); err != nil {
+				rw.Header().Del("Content-Disposition")
+				http.Error(rw, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			sleep(req.Context(), p.duration(time.Second))
+			trace.Stop()
+		})}, nil
+	}
+
+	profile := pprof.Lookup(p.Name)
+	if profile == nil {
+		return nil, &ErrProfileNotFound{Name: p.Name}
+	}
+
+	return handlerUpgrader{http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if p.Name == "heap" && p.GC > 0 {
+			runtime.GC()
+		}
+
+		if p.Debug != 0 {
+			rw.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		} else {
+			rw.Header().Set("Content-Type", "application/octet-stream")
+			rw.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, p.Name))
+		}
+
+		_ = profile.WriteTo(rw, p.Debug)
+	})}, nil
+}
+
+func (p *Profile) duration(defaults time.Duration) time.Duration {
+	if p.Seconds > 0 {
+		return time.Duration(p.Seconds) * time.Second
+	}
+	return defaults
+}
+
+func sleep(ctx context.Context, d time.Duration) {
+	select {
+	case <-time.After(d):
+	case <-ctx.Done():
+	}
+}
+
+// Goroutines 输出全部 goroutine 的堆栈。
+type Goroutines struct {
+	courierhttp.MethodGet `path:"/goroutines"`
+}
+
+func (*Goroutines) Output(ctx context.Context) (any, error) {
+	return handlerUpgrader{http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_ = pprof.Lookup("goroutine").WriteTo(rw, 2)
+	})}, nil
+}
@@ -0,0 +1,70 @@
+package admin
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/octohelm/courier/pkg/courierhttp"
+	"github.com/octohelm/courier/pkg/courierhttp/handler/httprouter"
+	"github.com/octohelm/courier/pkg/openapi"
+)
+
+// Routes 以 JSON 输出与 `httprouter.RouteSnapshot` 一致的路由表。
+type Routes struct {
+	courierhttp.MethodGet `path:"/routes"`
+}
+
+func (*Routes) Output(ctx context.Context) (any, error) {
+	if x, ok := courierhttp.OperationInfoProviderFromContext(ctx); ok {
+		if p, ok := x.(interface{ Routes() []httprouter.RouteInfo }); ok {
+			return p.Routes(), nil
+		}
+	}
+	return []httprouter.RouteInfo{}, nil
+}
+
+// OperationSummary 描述 OpenAPI 中的一个操作。
+type OperationSummary struct {
+	OperationID string   `json:"operationID"`
+	Method      string   `json:"method"`
+	Path        string   `json:"path"`
+	Summary     string   `json:"summary,omitzero"`
+	Tags        []string `json:"tags,omitzero"`
+	Deprecated  bool     `json:"deprecated,omitzero"`
+}
+
+// Operations 列出当前服务 OpenAPI 中加载的操作。
+type Operations struct {
+	courierhttp.MethodGet `path:"/operations"`
+}
+
+func (*Operations) Output(ctx context.Context) (any, error) {
+	ops := make([]OperationSummary, 0)
+
+	if x, ok := courierhttp.OperationInfoProviderFromContext(ctx); ok {
+		if p, ok := x.(interface{ OpenAPI() *openapi.OpenAPI }); ok {
+			for path, item := range p.OpenAPI().Paths.KeyValues() {
+				for method, op := range item.KeyValues() {
+					ops = append(ops, OperationSummary{
+						OperationID: op.OperationId,
+						Method:      strings.ToUpper(method),
+						Path:        path,
+						Summary:     op.Summary,
+						Tags:        op.Tags,
+						Deprecated:  op.Deprecated != nil && *op.Deprecated,
+					})
+				}
+			}
+		}
+	}
+
+	slices.SortFunc(ops, func(a, b OperationSummary) int {
+		if c := strings.Compare(a.Path, b.Path); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Method, b.Method)
+	})
+
+	return ops, nil
+}
@@ -0,0 +1,239 @@
+package auth_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-json-experiment/json"
+	. "github.com/octohelm/x/testing/v2"
+
+	"github.com/octohelm/courier/pkg/courier"
+	"github.com/octohelm/courier/pkg/courierhttp"
+	"github.com/octohelm/courier/pkg/courierhttp/auth"
+	"github.com/octohelm/courier/pkg/courierhttp/client"
+	"github.com/octohelm/courier/pkg/courierhttp/handler/httprouter"
+	"github.com/octohelm/courier/pkg/statuserror"
+)
+
+type WhoAmI struct {
+	courierhttp.MethodPost `path:"/whoami"`
+
+	Query string            `name:"q,omitzero" in:"query"`
+	Body  map[string]string `in:"body"`
+}
+
+func (r *WhoAmI) Output(ctx context.Context) (any, error) {
+	keyID, _ := auth.KeyIDFromContext(ctx)
+	return map[string]string{"keyID": keyID, "name": r.Body["name"], "q": r.Query}, nil
+}
+
+func TestSignature(t *testing.T) {
+	h, err := httprouter.New(courierhttp.GroupRouter("/").With(
+		courier.NewRouter(auth.NewSignatureVerifier(map[string][]byte{"svc-a": []byte("secret")}, time.Minute), &WhoAmI{}),
+	), "test")
+	Then(t, "构建 httprouter handler 成功", Expect(err, Equal[error](nil)))
+
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+
+	call := func(transports ...client.HttpTransport) (map[string]string, error) {
+		c := &client.Client{Endpoint: srv.URL, HttpTransports: transports}
+		ret := map[string]string{}
+		_, err := c.Do(context.Background(), &WhoAmI{Query: "x y", Body: map[string]string{"name": "a"}}).Into(&ret)
+		return ret, err
+	}
+
+	ret, err := call(auth.Sign("svc-a", []byte("secret"), "Content-Type"))
+
+	Then(t, "签名正确时通过校验，请求体可正常读取",
+		Expect(err, Equal[error](nil)),
+		Expect(ret, Equal(map[string]string{"keyID": "svc-a", "name": "a", "q": "x y"})),
+	)
+
+	_, err = call(auth.Sign("svc-a", []byte("wrong")))
+
+	Then(t, "密钥错误时拒绝",
+		Expect(statusCodeOf(err), Equal(http.StatusUnauthorized)),
+	)
+
+	_, err = call(
+		client.HttpTransportFunc(func(req *http.Request, next client.RoundTrip) (*http.Response, error) {
+			// 签名后篡改请求头
+			req.Header.Set("Content-Type", "text/plain")
+			return next(req)
+		}),
+		auth.Sign("svc-a", []byte("secret"), "Content-Type"),
+	)
+
+	Then(t, "签名请求头被篡改时拒绝",
+		Expect(statusCodeOf(err), Equal(http.StatusUnauthorized)),
+	)
+
+	_, err = call()
+
+	Then(t, "未签名时拒绝",
+		Expect(statusCodeOf(err), Equal(http.StatusUnauthorized)),
+	)
+
+	var verifyErr error
+
+	_, _ = call(
+		client.HttpTransportFunc(func(req *http.Request, next client.RoundTrip) (*http.Response, error) {
+			body, _ := io.ReadAll(req.Body)
+			req.Body = io.NopCloser(bytes.NewReader(body))
+
+			// 未指定偏差时仍校验时间戳
+			_, verifyErr = auth.VerifySignature(map[string][]byte{"svc-a": []byte("secret")}, req, body, time.Now().Add(time.Hour), 0)
+			return next(req)
+		}),
+		auth.Sign("svc-a", []byte("secret")),
+	)
+
+	Then(t, "偏差为 0 时使用默认偏差",
+		Expect(errors.As(verifyErr, new(*auth.ErrTimestampOutOfTolerance)), Equal(true)),
+	)
+
+	limited, err := httprouter.New(courierhttp.GroupRouter("/").With(
+		courier.NewRouter(auth.NewSignatureVerifier(map[string][]byte{"svc-a": []byte("secret")}, 0).WithMaxBodySize(8), &WhoAmI{}),
+	), "test")
+	Then(t, "构建 httprouter handler 成功", Expect(err, Equal[error](nil)))
+
+	limitedSrv := httptest.NewServer(limited)
+	t.Cleanup(limitedSrv.Close)
+
+	c := &client.Client{Endpoint: limitedSrv.URL, HttpTransports: []client.HttpTransport{auth.Sign("svc-a", []byte("secret"))}}
+	_, err = c.Do(context.Background(), &WhoAmI{Body: map[string]string{"name": "a"}}).Into(nil)
+
+	Then(t, "请求体超出限制时拒绝",
+		Expect(statusCodeOf(err), Equal(http.StatusRequestEntityTooLarge)),
+	)
+}
+
+func TestOAuth2(t *testing.T) {
+	issued := atomic.Int32{}
+
+	authSrv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		id, secret, ok := req.BasicAuth()
+		if !ok || id != "client" || secret != "s3cret" {
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		_ = req.ParseForm()
+
+		n := issued.Add(1)
+
+		tok := map[string]any{
+			"access_token": "t" + string(rune('0'+n)),
+			"token_type":   "bearer",
+			"expires_in":   3600,
+		}
+
+		switch req.PostForm.Get("grant_type") {
+		case "client_credentials":
+			if req.PostForm.Get("scope") != "read write" {
+				rw.WriteHeader(http.StatusBadRequest)
+				return
+			}
+		case "refresh_token":
+			tok["refresh_token"] = req.PostForm.Get("refresh_token") + "+"
+		}
+
+		rw.Header().Set("Content-Type", "application/json")
+		_ = json.MarshalWrite(rw, tok)
+	}))
+	t.Cleanup(authSrv.Close)
+
+	apiSrv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("X-Authorization", req.Header.Get("Authorization"))
+		rw.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(apiSrv.Close)
+
+	endpoint := auth.TokenEndpoint{
+		Client:       &client.Client{Endpoint: authSrv.URL},
+		ClientID:     "client",
+		ClientSecret: "s3cret",
+	}
+
+	call := func(transport client.HttpTransport) string {
+		c := &client.Client{HttpTransports: []client.HttpTransport{transport}}
+		req, _ := http.NewRequest(http.MethodGet, apiSrv.URL, nil)
+		meta, _ := c.Do(context.Background(), req).Into(nil)
+		return meta.Get("X-Authorization")
+	}
+
+	t.Run("client credentials 令牌被缓存并在过期前后台刷新", func(t *testing.T) {
+		issued.Store(0)
+
+		src := auth.ReuseTokenSource(&auth.ClientCredentials{TokenEndpoint: endpoint, Scopes: []string{"read", "write"}}, 2*time.Hour)
+		transport := auth.OAuth2(src)
+
+		first := call(transport)
+		second := call(transport)
+
+		Then(t, "刷新完成前继续使用当前令牌",
+			Expect(first, Equal("Bearer t1")),
+			Expect(second, Equal("Bearer t1")),
+		)
+
+		Then(t, "后台刷新后使用新令牌",
+			ExpectMust(func() error {
+				for range 100 {
+					if call(transport) == "Bearer t2" {
+						return nil
+					}
+					time.Sleep(10 * time.Millisecond)
+				}
+				return context.DeadlineExceeded
+			}),
+		)
+	})
+
+	t.Run("refresh token 轮换", func(t *testing.T) {
+		issued.Store(0)
+
+		src := &auth.RefreshTokenSource{TokenEndpoint: endpoint, RefreshToken: "r"}
+
+		tok, err := src.Token(context.Background())
+
+		Then(t, "获取令牌并更新 refresh token",
+			Expect(err, Equal[error](nil)),
+			Expect(tok.AccessToken, Equal("t1")),
+			Expect(tok.Expiry.After(time.Now()), Equal(true)),
+			Expect(src.RefreshToken, Equal("r+")),
+		)
+	})
+
+	t.Run("客户端凭证错误", func(t *testing.T) {
+		src := &auth.ClientCredentials{TokenEndpoint: auth.TokenEndpoint{Client: endpoint.Client, ClientID: "client"}}
+
+		_, err := src.Token(context.Background())
+
+		Then(t, "返回授权服务的错误",
+			Expect(statusCodeOf(err), Equal(http.StatusUnauthorized)),
+		)
+	})
+
+	t.Run("静态凭证", func(t *testing.T) {
+		Then(t, "携带 Authorization",
+			Expect(call(auth.Bearer("abc")), Equal("Bearer abc")),
+			Expect(call(auth.Basic("u", "p")), Equal("Basic dTpw")),
+		)
+	})
+}
+
+func statusCodeOf(err error) int {
+	var e statuserror.WithStatusCode
+	if errors.As(err, &e) {
+		return e.StatusCode()
+	}
+	return 0
+}
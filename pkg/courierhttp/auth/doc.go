@@ -0,0 +1,14 @@
+// Package auth 提供服务间认证所需的客户端 transport 与服务端校验。
+//
+// 客户端 transport（`client.HttpTransport`）：
+//
+//   - `Bearer`、`Basic` 携带静态凭证；
+//   - `OAuth2` 从 `TokenSource` 获取令牌，`ClientCredentials` 与 `RefreshTokenSource` 经 courier 客户端调用令牌端点，
+//     `ReuseTokenSource` 缓存令牌并在过期前后台刷新；
+//   - `Sign` 以 HMAC-SHA256 对请求方法、路径、查询参数、指定请求头与请求体摘要签名。
+//
+// 服务端将 `SignatureVerifier` 作为中间 operator 挂在路由链上校验签名，
+// 通过后可由 `KeyIDFromContext` 获取调用方的密钥标识。
+//
+// +gengo:runtimedoc=false
+package auth
@@ -0,0 +1,29 @@
+package auth
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/octohelm/courier/pkg/statuserror"
+)
+
+type ErrInvalidSignature struct {
+	statuserror.Unauthorized
+
+	Reason string
+}
+
+func (e *ErrInvalidSignature) Error() string {
+	return fmt.Sprintf("invalid request signature: %s", e.Reason)
+}
+
+type ErrTimestampOutOfTolerance struct {
+	statuserror.Unauthorized
+
+	Timestamp time.Time
+	Tolerance time.Duration
+}
+
+func (e *ErrTimestampOutOfTolerance) Error() string {
+	return fmt.Sprintf("signature timestamp %s is out of tolerance %s", e.Timestamp.UTC().Format(time.RFC3339), e.Tolerance)
+}
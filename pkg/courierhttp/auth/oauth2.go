@@ -0,0 +1,245 @@
+package auth
+
+import (
+	"cmp"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/octohelm/courier/pkg/courierhttp"
+	"github.com/octohelm/courier/pkg/courierhttp/client"
+)
+
+// Token 为 OAuth2 访问令牌。
+type Token struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type,omitzero"`
+	RefreshToken string `json:"refresh_token,omitzero"`
+	ExpiresIn    int64  `json:"expires_in,omitzero"`
+	// 过期时间，由 ExpiresIn 计算，为零表示不过期
+	Expiry time.Time `json:"-"`
+}
+
+// Type 返回令牌类型，默认 Bearer。
+func (t *Token) Type() string {
+	if t.TokenType == "" || strings.EqualFold(t.TokenType, "bearer") {
+		return "Bearer"
+	}
+	return t.TokenType
+}
+
+// TokenSource 提供访问令牌。
+type TokenSource interface {
+	Token(ctx context.Context) (*Token, error)
+}
+
+// OAuth2 为请求携带 src 提供的访问令牌。
+func OAuth2(src TokenSource) client.HttpTransport {
+	return client.HttpTransportFunc(func(req *http.Request, next client.RoundTrip) (*http.Response, error) {
+		tok, err := src.Token(req.Context())
+		if err != nil {
+			return nil, fmt.Errorf("get oauth2 token failed: %w", err)
+		}
+
+		r := req.Clone(req.Context())
+		r.Header.Set("Authorization", tok.Type()+" "+tok.AccessToken)
+		return next(r)
+	})
+}
+
+// TokenEndpoint 为 OAuth2 令牌端点配置。
+type TokenEndpoint struct {
+	// 访问授权服务的客户端，Endpoint 为授权服务地址，不应再配置 OAuth2 transport
+	Client *client.Client
+	// 令牌端点路径，默认 /oauth/token
+	TokenPath    string
+	ClientID     string
+	ClientSecret string
+}
+
+type tokenRequest struct {
+	courierhttp.MethodPost
+
+	Authorization string    `name:"Authorization,omitzero" in:"header"`
+	Body          tokenForm `in:"body" mime:"urlencoded"`
+
+	path string
+}
+
+func (r *tokenRequest) Path() string {
+	return r.path
+}
+
+type tokenForm struct {
+	GrantType    string `json:"grant_type"`
+	Scope        string `json:"scope,omitzero"`
+	RefreshToken string `json:"refresh_token,omitzero"`
+}
+
+func (e *TokenEndpoint) exchange(ctx context.Context, form tokenForm) (*Token, error) {
+	req := &tokenRequest{
+		path: cmp.Or(e.TokenPath, "/oauth/token"),
+		Body: form,
+	}
+
+	if e.ClientID != "" {
+		// RFC 6749 2.3.1 要求先对 client_id 与 client_secret 做 urlencode
+		credentials := url.QueryEscape(e.ClientID) + ":" + url.QueryEscape(e.ClientSecret)
+		req.Authorization = "Basic " + base64.StdEncoding.EncodeToString([]byte(credentials))
+	}
+
+	tok := &Token{}
+	if _, err := e.Client.Do(ctx, req).Into(tok); err != nil {
+		return nil, err
+	}
+
+	if tok.AccessToken == "" {
+		return nil, errors.New("token endpoint returned empty access_token")
+	}
+
+	if tok.ExpiresIn > 0 {
+		tok.Expiry = time.Now().Add(time.Duration(tok.ExpiresIn) * time.Second)
+	}
+
+	return tok, nil
+}
+
+// ClientCredentials 以 client_credentials 授权方式获取令牌。
+type ClientCredentials struct {
+	TokenEndpoint
+
+	Scopes []string
+}
+
+func (c *ClientCredentials) Token(ctx context.Context) (*Token, error) {
+	return c.exchange(ctx, tokenForm{
+		GrantType: "client_credentials",
+		Scope:     strings.Join(c.Scopes, " "),
+	})
+}
+
+// RefreshTokenSource 以 refresh_token 授权方式获取令牌，授权服务轮换 refresh_token 时自动更新。
+type RefreshTokenSource struct {
+	TokenEndpoint
+
+	RefreshToken string
+	Scopes       []string
+
+	mu sync.Mutex
+}
+
+func (s *RefreshTokenSource) Token(ctx context.Context) (*Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tok, err := s.exchange(ctx, tokenForm{
+		GrantType:    "refresh_token",
+		RefreshToken: s.RefreshToken,
+		Scope:        strings.Join(s.Scopes, " "),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if tok.RefreshToken != "" {
+		s.RefreshToken = tok.RefreshToken
+	} else {
+		tok.RefreshToken = s.RefreshToken
+	}
+
+	return tok, nil
+}
+
+// ReuseTokenSource 缓存 src 获取的令牌。
+//
+// 令牌进入过期前 earlyExpiry 的窗口后，仍返回当前令牌并在后台刷新；令牌已过期时同步刷新。
+func ReuseTokenSource(src TokenSource, earlyExpiry time.Duration) TokenSource {
+	return &reuseTokenSource{
+		src:         src,
+		earlyExpiry: earlyExpiry,
+		now:         time.Now,
+	}
+}
+
+type reuseTokenSource struct {
+	src         TokenSource
+	earlyExpiry time.Duration
+	now         func() time.Time
+
+	// fetchMu 保证同时只有一个刷新请求
+	fetchMu    sync.Mutex
+	mu         sync.Mutex
+	token      *Token
+	refreshing bool
+}
+
+func (s *reuseTokenSource) Token(ctx context.Context) (*Token, error) {
+	if tok, ok := s.cached(ctx); ok {
+		return tok, nil
+	}
+
+	s.fetchMu.Lock()
+	defer s.fetchMu.Unlock()
+
+	// 等待期间可能已被其他请求刷新
+	if tok, ok := s.cached(ctx); ok {
+		return tok, nil
+	}
+
+	return s.fetch(ctx)
+}
+
+func (s *reuseTokenSource) cached(ctx context.Context) (*Token, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tok := s.token
+	if tok == nil {
+		return nil, false
+	}
+
+	if tok.Expiry.IsZero() {
+		return tok, true
+	}
+
+	now := s.now()
+	if !now.Before(tok.Expiry) {
+		return nil, false
+	}
+
+	if !now.Before(tok.Expiry.Add(-s.earlyExpiry)) && !s.refreshing {
+		s.refreshing = true
+
+		go func() {
+			s.fetchMu.Lock()
+			defer s.fetchMu.Unlock()
+
+			_, _ = s.fetch(context.WithoutCancel(ctx))
+
+			s.mu.Lock()
+			s.refreshing = false
+			s.mu.Unlock()
+		}()
+	}
+
+	return tok, true
+}
+
+func (s *reuseTokenSource) fetch(ctx context.Context) (*Token, error) {
+	tok, err := s.src.Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.token = tok
+	s.mu.Unlock()
+
+	return tok, nil
+}
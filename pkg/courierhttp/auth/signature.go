@@ -0,0 +1,199 @@
+package auth
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/octohelm/courier/pkg/courierhttp/client"
+)
+
+const (
+	SignatureAlgorithm = "HMAC-SHA256"
+
+	HeaderSignatureTimestamp = "X-Signature-Timestamp"
+	HeaderContentDigest      = "Content-Digest"
+)
+
+// 始终参与签名的请求头
+var requiredSignedHeaders = []string{"content-digest", "host", "x-signature-timestamp"}
+
+// Sign 返回对请求签名的 HttpTransport，headers 为额外参与签名的请求头。
+//
+// 签名写入 `Authorization: HMAC-SHA256 KeyId=<keyID>,SignedHeaders=<h1;h2>,Signature=<base64>`，
+// 同时携带 `X-Signature-Timestamp` 与请求体摘要 `Content-Digest`。
+func Sign(keyID string, secret []byte, headers ...string) client.HttpTransport {
+	signedHeaders := slices.Clone(requiredSignedHeaders)
+	for _, h := range headers {
+		signedHeaders = append(signedHeaders, strings.ToLower(h))
+	}
+	slices.Sort(signedHeaders)
+	signedHeaders = slices.Compact(signedHeaders)
+
+	return client.HttpTransportFunc(func(req *http.Request, next client.RoundTrip) (*http.Response, error) {
+		r := req.Clone(req.Context())
+
+		body, err := readBody(r)
+		if err != nil {
+			return nil, err
+		}
+
+		if r.Host == "" {
+			r.Host = r.URL.Host
+		}
+
+		r.Header.Set(HeaderSignatureTimestamp, strconv.FormatInt(time.Now().Unix(), 10))
+		r.Header.Set(HeaderContentDigest, contentDigest(body))
+
+		r.Header.Set("Authorization", SignatureAlgorithm+
+			" KeyId="+keyID+
+			",SignedHeaders="+strings.Join(signedHeaders, ";")+
+			",Signature="+signature(secret, canonicalRequest(r, signedHeaders)))
+
+		return next(r)
+	})
+}
+
+// VerifySignature 校验请求签名，body 为请求体，返回签名使用的密钥标识。
+//
+// keys 为密钥标识到密钥的映射，拒绝与 now 偏差超过 tolerance 的签名时间戳以防止重放，
+// tolerance 不大于 0 时使用 DefaultSignatureTolerance。
+func VerifySignature(keys map[string][]byte, req *http.Request, body []byte, now time.Time, tolerance time.Duration) (string, error) {
+	scheme, params, ok := strings.Cut(req.Header.Get("Authorization"), " ")
+	if !ok || scheme != SignatureAlgorithm {
+		return "", &ErrInvalidSignature{Reason: "missing signature"}
+	}
+
+	keyID, signedHeaders, sig := "", []string(nil), ""
+
+	for p := range strings.SplitSeq(params, ",") {
+		k, v, _ := strings.Cut(strings.TrimSpace(p), "=")
+		switch k {
+		case "KeyId":
+			keyID = v
+		case "SignedHeaders":
+			signedHeaders = strings.Split(v, ";")
+		case "Signature":
+			sig = v
+		}
+	}
+
+	secret, ok := keys[keyID]
+	if !ok {
+		return "", &ErrInvalidSignature{Reason: "unknown key"}
+	}
+
+	for _, h := range requiredSignedHeaders {
+		if !slices.Contains(signedHeaders, h) {
+			return "", &ErrInvalidSignature{Reason: "header " + h + " should be signed"}
+		}
+	}
+
+	unix, err := strconv.ParseInt(req.Header.Get(HeaderSignatureTimestamp), 10, 64)
+	if err != nil {
+		return "", &ErrInvalidSignature{Reason: "invalid timestamp"}
+	}
+
+	ts := time.Unix(unix, 0)
+
+	if tolerance <= 0 {
+		tolerance = DefaultSignatureTolerance
+	}
+
+	if d := now.Sub(ts); d > tolerance || d < -tolerance {
+		return "", &ErrTimestampOutOfTolerance{Timestamp: ts, Tolerance: tolerance}
+	}
+
+	if req.Header.Get(HeaderContentDigest) != contentDigest(body) {
+		return "", &ErrInvalidSignature{Reason: "content digest mismatch"}
+	}
+
+	if !hmac.Equal([]byte(sig), []byte(signature(secret, canonicalRequest(req, signedHeaders)))) {
+		return "", &ErrInvalidSignature{Reason: "signature mismatch"}
+	}
+
+	return keyID, nil
+}
+
+// canonicalRequest 按 方法、路径、排序后的查询参数、签名请求头、签名请求头列表 逐行拼接
+func canonicalRequest(req *http.Request, signedHeaders []string) string {
+	b := &strings.Builder{}
+
+	b.WriteString(req.Method)
+	b.WriteString("\n")
+	b.WriteString(req.URL.EscapedPath())
+	b.WriteString("\n")
+
+	query, _ := url.ParseQuery(req.URL.RawQuery)
+	b.WriteString(query.Encode())
+	b.WriteString("\n")
+
+	for _, h := range signedHeaders {
+		b.WriteString(h)
+		b.WriteString(":")
+		if h == "host" {
+			b.WriteString(req.Host)
+		} else {
+			for i, v := range req.Header.Values(h) {
+				if i > 0 {
+					b.WriteString(",")
+				}
+				b.WriteString(strings.TrimSpace(v))
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString(strings.Join(signedHeaders, ";"))
+
+	return b.String()
+}
+
+func signature(secret []byte, canonical string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(canonical))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// contentDigest 按 RFC 9530 计算 `sha-256=:<base64>:`
+func contentDigest(body []byte) string {
+	sum := sha256.Sum256(body)
+	return "sha-256=:" + base64.StdEncoding.EncodeToString(sum[:]) + ":"
+}
+
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	if req.GetBody != nil {
+		rc, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		return io.ReadAll(rc)
+	}
+
+	data, err := io.ReadAll(req.Body)
+	_ = req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	req.Body = io.NopCloser(bytes.NewReader(data))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+	req.ContentLength = int64(len(data))
+
+	return data, nil
+}
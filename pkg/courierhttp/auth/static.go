@@ -0,0 +1,26 @@
+package auth
+
+import (
+	"encoding/base64"
+	"net/http"
+
+	"github.com/octohelm/courier/pkg/courierhttp/client"
+)
+
+// Bearer 为请求携带 `Authorization: Bearer <token>`。
+func Bearer(token string) client.HttpTransport {
+	return withAuthorization("Bearer " + token)
+}
+
+// Basic 为请求携带 HTTP Basic 认证。
+func Basic(username string, password string) client.HttpTransport {
+	return withAuthorization("Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password)))
+}
+
+func withAuthorization(authorization string) client.HttpTransport {
+	return client.HttpTransportFunc(func(req *http.Request, next client.RoundTrip) (*http.Response, error) {
+		r := req.Clone(req.Context())
+		r.Header.Set("Authorization", authorization)
+		return next(r)
+	})
+}
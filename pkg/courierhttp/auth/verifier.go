@@ -0,0 +1,97 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/octohelm/courier/internal/httprequest"
+	"github.com/octohelm/courier/pkg/courier"
+	"github.com/octohelm/courier/pkg/courierhttp"
+)
+
+// DefaultSignatureTolerance 为未指定时允许的签名时间戳偏差
+const DefaultSignatureTolerance = 5 * time.Minute
+
+// NewSignatureVerifier 创建校验请求签名的中间 operator，keys 为密钥标识到密钥的映射，
+// tolerance 为允许的时间戳偏差，不大于 0 时使用 DefaultSignatureTolerance。
+func NewSignatureVerifier(keys map[string][]byte, tolerance time.Duration) *SignatureVerifier {
+	if tolerance <= 0 {
+		tolerance = DefaultSignatureTolerance
+	}
+
+	return &SignatureVerifier{
+		keys:      keys,
+		tolerance: tolerance,
+	}
+}
+
+// SignatureVerifier 校验 `Sign` 签名的请求，通过后请求体可被后续 operator 正常读取。
+type SignatureVerifier struct {
+	Authorization      string `name:"Authorization,omitzero" in:"header"`
+	SignatureTimestamp string `name:"X-Signature-Timestamp,omitzero" in:"header"`
+	ContentDigest      string `name:"Content-Digest,omitzero" in:"header"`
+
+	keys        map[string][]byte
+	tolerance   time.Duration
+	maxBodySize int64
+}
+
+// WithMaxBodySize 设置校验签名时读取请求体的上限，见 courierhttp.ReadBody。
+func (v *SignatureVerifier) WithMaxBodySize(n int64) *SignatureVerifier {
+	v.maxBodySize = n
+	return v
+}
+
+func (v *SignatureVerifier) InitFrom(o courier.Operator) {
+	if x, ok := o.(*SignatureVerifier); ok {
+		v.keys = x.keys
+		v.tolerance = x.tolerance
+		v.maxBodySize = x.maxBodySize
+	}
+}
+
+func (SignatureVerifier) ResponseErrors() []error {
+	return []error{
+		&ErrInvalidSignature{},
+		&ErrTimestampOutOfTolerance{},
+		&courierhttp.ErrRequestBodyTooLarge{},
+	}
+}
+
+func (*SignatureVerifier) Output(ctx context.Context) (any, error) {
+	return nil, nil
+}
+
+// PreHandlerMiddleware 在解析请求前读取原始请求体完成校验，并还原请求体。
+func (v *SignatureVerifier) PreHandlerMiddleware(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		body, err := courierhttp.ReadBody(rw, req, v.maxBodySize)
+
+		keyID := ""
+		if err == nil {
+			keyID, err = VerifySignature(v.keys, req, body, time.Now(), v.tolerance)
+		}
+
+		if err != nil {
+			e := courierhttp.WrapError(err)
+			_ = e.(courierhttp.ResponseWriter).WriteResponse(req.Context(), rw, httprequest.From(req))
+			return
+		}
+
+		h.ServeHTTP(rw, req.WithContext(ContextWithKeyID(req.Context(), keyID)))
+	})
+}
+
+type contextKeyID struct{}
+
+// ContextWithKeyID 注入通过签名校验的密钥标识。
+func ContextWithKeyID(ctx context.Context, keyID string) context.Context {
+	return context.WithValue(ctx, contextKeyID{}, keyID)
+}
+
+// KeyIDFromContext 获取通过签名校验的密钥标识。
+func KeyIDFromContext(ctx context.Context) (string, bool) {
+	keyID, ok := ctx.Value(contextKeyID{}).(string)
+	return keyID, ok
+}
@@ -0,0 +1,34 @@
+package courierhttp
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+)
+
+// DefaultMaxBodySize 为 ReadBody 未指定上限时可读取的最大请求体字节数
+const DefaultMaxBodySize int64 = 10 << 20
+
+// ReadBody 读取至多 maxSize 字节的请求体，并还原 req.Body 供后续读取。
+//
+// maxSize 不大于 0 时使用 DefaultMaxBodySize，超出时返回 *ErrRequestBodyTooLarge。
+// 用于在解析请求前需要原始请求体的场景，如签名校验。
+func ReadBody(rw http.ResponseWriter, req *http.Request, maxSize int64) ([]byte, error) {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxBodySize
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(rw, req.Body, maxSize))
+	_ = req.Body.Close()
+	if err != nil {
+		if errors.As(err, new(*http.MaxBytesError)) {
+			return nil, &ErrRequestBodyTooLarge{MaxSize: maxSize}
+		}
+		return nil, err
+	}
+
+	req.Body = io.NopCloser(bytes.NewReader(body))
+
+	return body, nil
+}
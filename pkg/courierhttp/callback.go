@@ -0,0 +1,32 @@
+package courierhttp
+
+// Callback 描述操作完成后会向调用方发起的回调请求。
+type Callback struct {
+	// 回调名
+	Name string
+	// 回调地址的运行时表达式，如 `{$request.body#/callbackUrl}`
+	Expression string
+	// 回调请求的原型，方法、参数与请求体取自其类型，
+	// 可以是 operator 或客户端请求类型，响应取自 `ResponseContent` 或 `ResponseData`
+	Request any
+}
+
+// CallbacksDescriber 用于声明操作的回调请求，输出为 OpenAPI 中操作的 `callbacks`。
+type CallbacksDescriber interface {
+	Callbacks() []Callback
+}
+
+// Webhook 描述服务主动发出的请求，接收方地址由订阅决定。
+type Webhook struct {
+	// webhook 名，通常为事件类型
+	Name string
+	// 请求原型，同 Callback.Request
+	Request any
+}
+
+// WebhooksDescriber 用于声明服务会发出的 webhook，输出为 OpenAPI 顶层的 `webhooks`。
+//
+// 通常由管理订阅的操作实现。
+type WebhooksDescriber interface {
+	Webhooks() []Webhook
+}
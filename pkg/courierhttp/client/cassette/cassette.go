@@ -0,0 +1,120 @@
+package cassette
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-json-experiment/json"
+	"github.com/go-json-experiment/json/jsontext"
+)
+
+// Cassette 为录制的交互列表。
+type Cassette struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Interaction 为一次请求与响应。
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+type Request struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitzero"`
+	Body   Body        `json:"body,omitzero"`
+}
+
+type Response struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header,omitzero"`
+	Body       Body        `json:"body,omitzero"`
+}
+
+// Body 为请求或响应体，非 UTF-8 内容以 base64 保存。
+type Body struct {
+	Data     string `json:"data,omitzero"`
+	Encoding string `json:"encoding,omitzero"`
+}
+
+func bodyOf(data []byte) Body {
+	if utf8.Valid(data) {
+		return Body{Data: string(data)}
+	}
+	return Body{Data: base64.StdEncoding.EncodeToString(data), Encoding: "base64"}
+}
+
+func (b Body) Bytes() []byte {
+	if b.Encoding == "base64" {
+		data, _ := base64.StdEncoding.DecodeString(b.Data)
+		return data
+	}
+	return []byte(b.Data)
+}
+
+func load(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Cassette{}
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *Cassette) save(path string) error {
+	data, err := json.Marshal(c, jsontext.WithIndent("  "), json.Deterministic(true))
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// matchKey 由方法、路径、排序后的查询参数与规范化后的请求体组成
+func matchKey(method string, u *url.URL, contentType string, body []byte) string {
+	query, _ := url.ParseQuery(u.RawQuery)
+
+	return strings.Join([]string{
+		method,
+		u.EscapedPath(),
+		query.Encode(),
+		normalizeBody(contentType, body),
+	}, "\n")
+}
+
+// normalizeBody 使 JSON 与表单请求体的匹配不受字段顺序与空白影响
+func normalizeBody(contentType string, body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	switch {
+	case strings.Contains(contentType, "json"):
+		var v any
+		if err := json.Unmarshal(body, &v); err == nil {
+			if data, err := json.Marshal(v, json.Deterministic(true)); err == nil {
+				return string(data)
+			}
+		}
+	case strings.HasPrefix(contentType, "application/x-www-form-urlencoded"):
+		if values, err := url.ParseQuery(string(body)); err == nil {
+			return values.Encode()
+		}
+	}
+
+	return string(body)
+}
@@ -0,0 +1,123 @@
+package cassette_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/octohelm/x/testing/v2"
+
+	"github.com/octohelm/courier/pkg/courierhttp"
+	"github.com/octohelm/courier/pkg/courierhttp/client"
+	"github.com/octohelm/courier/pkg/courierhttp/client/cassette"
+	"github.com/octohelm/courier/pkg/courierhttp/operatortest"
+)
+
+type CreateOrg struct {
+	courierhttp.MethodPost `path:"/orgs"`
+
+	DryRun bool `name:"dryRun,omitzero" in:"query"`
+	Body   Org  `in:"body"`
+}
+
+type Org struct {
+	Name  string `json:"name"`
+	Owner string `json:"owner"`
+}
+
+func (r *CreateOrg) Output(ctx context.Context) (any, error) {
+	return &r.Body, nil
+}
+
+func TestRecorder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "orgs.json")
+
+	t.Run("录制", func(t *testing.T) {
+		srv := operatortest.Serve(context.Background(), &CreateOrg{})
+		t.Cleanup(srv.Close)
+
+		r, err := cassette.New(path, cassette.WithMode(cassette.ModeRecord), cassette.WithRedactHeaders("X-Api-Key"))
+		Then(t, "创建 Recorder", Expect(err, Equal[error](nil)))
+
+		c := &client.Client{
+			Endpoint:       srv.URL,
+			HttpTransports: []client.HttpTransport{r.HttpTransport()},
+		}
+
+		org := &Org{}
+		_, err = c.Do(context.Background(), &CreateOrg{DryRun: true, Body: Org{Name: "a", Owner: "u"}}, map[string][]string{
+			"Authorization": {"Bearer secret"},
+			"X-Api-Key":     {"secret"},
+		}).Into(org)
+
+		Then(t, "请求真实服务并保存",
+			Expect(err, Equal[error](nil)),
+			Expect(org.Name, Equal("a")),
+			Expect(r.Save(), Equal[error](nil)),
+		)
+
+		data, _ := os.ReadFile(path)
+
+		Then(t, "敏感请求头已脱敏",
+			Expect(strings.Contains(string(data), "secret"), Equal(false)),
+			Expect(strings.Contains(string(data), cassette.Redacted), Equal(true)),
+		)
+	})
+
+	t.Run("回放", func(t *testing.T) {
+		r, err := cassette.New(path, cassette.WithMode(cassette.ModeReplay))
+		Then(t, "加载录制文件", Expect(err, Equal[error](nil)))
+
+		c := &client.Client{
+			HttpTransports: []client.HttpTransport{r.HttpTransport()},
+		}
+
+		// 字段顺序与 host 不同，仍可匹配
+		req, _ := http.NewRequest(http.MethodPost, "http://offline.invalid/orgs?dryRun=true", strings.NewReader(`{ "owner": "u", "name": "a" }`))
+		req.Header.Set("Content-Type", "application/json")
+
+		org := &Org{}
+		_, err = c.Do(context.Background(), req).Into(org)
+
+		Then(t, "无需服务即可返回录制的响应",
+			Expect(err, Equal[error](nil)),
+			Expect(org, Equal(&Org{Name: "a", Owner: "u"})),
+		)
+
+		req, _ = http.NewRequest(http.MethodPost, "http://offline.invalid/orgs", strings.NewReader(`{"name":"b"}`))
+		_, err = c.Do(context.Background(), req).Into(nil)
+
+		Then(t, "未录制的请求返回错误",
+			Expect(err != nil, Equal(true)),
+			Expect(strings.Contains(err.Error(), cassette.ErrNoInteraction.Error()), Equal(true)),
+		)
+	})
+
+	t.Run("回放文件不存在", func(t *testing.T) {
+		_, err := cassette.New(filepath.Join(t.TempDir(), "missing.json"), cassette.WithMode(cassette.ModeReplay))
+
+		Then(t, "提示录制",
+			Expect(errors.Is(err, os.ErrNotExist), Equal(true)),
+			Expect(strings.Contains(err.Error(), cassette.EnvMode), Equal(true)),
+		)
+	})
+}
+
+func TestForTest(t *testing.T) {
+	c := &client.Client{
+		Endpoint:       "https://api.example.com",
+		HttpTransports: []client.HttpTransport{cassette.ForTest(t, "create-org", cassette.WithMode(cassette.ModeReplay))},
+	}
+
+	org := &Org{}
+	_, err := c.Do(context.Background(), &CreateOrg{Body: Org{Name: "x", Owner: "y"}}).Into(org)
+
+	Then(t, "从 testdata 回放",
+		Expect(err, Equal[error](nil)),
+		Expect(org, Equal(&Org{Name: "x", Owner: "y"})),
+	)
+}
@@ -0,0 +1,18 @@
+// Package cassette 为 `client.Client` 提供录制与回放 HTTP 交互的 transport，
+// 便于离线测试对第三方 courier 服务的集成。
+//
+// 交互记录（cassette）以 JSON 保存在 testdata 下，按方法、路径、查询参数与规范化后的请求体匹配，
+// 不比较 host，因此 `operatortest.Server` 的随机端口同样可以回放：
+//
+//	func TestXxx(t *testing.T) {
+//		c := &client.Client{
+//			Endpoint:       "https://api.example.com",
+//			HttpTransports: []client.HttpTransport{cassette.ForTest(t, "list-orgs")},
+//		}
+//	}
+//
+// 模式由环境变量 `COURIER_CASSETTE_MODE` 切换：replay（默认）仅回放，record 发送真实请求并录制，
+// passthrough 直接发送真实请求。录制时 `Authorization` 等敏感请求头会被替换为 `REDACTED`。
+//
+// +gengo:runtimedoc=false
+package cassette
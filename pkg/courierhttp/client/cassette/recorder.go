@@ -0,0 +1,280 @@
+package cassette
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/octohelm/courier/pkg/courierhttp/client"
+)
+
+// Mode 为录制回放模式。
+type Mode string
+
+const (
+	// ModeReplay 仅回放，未匹配的请求返回错误
+	ModeReplay Mode = "replay"
+	// ModeRecord 发送真实请求并录制
+	ModeRecord Mode = "record"
+	// ModePassthrough 直接发送真实请求，不录制也不回放
+	ModePassthrough Mode = "passthrough"
+)
+
+// EnvMode 为指定模式的环境变量。
+const EnvMode = "COURIER_CASSETTE_MODE"
+
+// Redacted 为脱敏后的请求头取值。
+const Redacted = "REDACTED"
+
+var ErrNoInteraction = errors.New("no recorded interaction matched")
+
+type OptionFunc func(r *Recorder)
+
+// WithMode 指定模式，优先于环境变量。
+func WithMode(mode Mode) OptionFunc {
+	return func(r *Recorder) {
+		r.mode = mode
+	}
+}
+
+// WithRedactHeaders 追加录制时需脱敏的请求头与响应头，默认包含 Authorization、Cookie、Set-Cookie。
+func WithRedactHeaders(headers ...string) OptionFunc {
+	return func(r *Recorder) {
+		for _, h := range headers {
+			r.redactHeaders = append(r.redactHeaders, http.CanonicalHeaderKey(h))
+		}
+	}
+}
+
+// New 创建读写 path 的 Recorder，回放模式下 path 需存在。
+func New(path string, opts ...OptionFunc) (*Recorder, error) {
+	r := &Recorder{
+		path:          path,
+		mode:          Mode(os.Getenv(EnvMode)),
+		redactHeaders: []string{"Authorization", "Cookie", "Set-Cookie"},
+		cassette:      &Cassette{},
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	switch r.mode {
+	case "":
+		r.mode = ModeReplay
+	case ModeReplay, ModeRecord, ModePassthrough:
+	default:
+		return nil, fmt.Errorf("invalid cassette mode %q", r.mode)
+	}
+
+	if r.mode == ModeReplay {
+		c, err := load(path)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil, fmt.Errorf("cassette %s not found, run with %s=%s to record: %w", path, EnvMode, ModeRecord, err)
+			}
+			return nil, err
+		}
+		r.cassette = c
+		r.used = make([]bool, len(c.Interactions))
+	}
+
+	return r, nil
+}
+
+// ForTest 创建读写 testdata/cassettes/<name>.json 的 HttpTransport，录制模式下在测试结束时保存。
+func ForTest(t testing.TB, name string, opts ...OptionFunc) client.HttpTransport {
+	t.Helper()
+
+	r, err := New(filepath.Join("testdata", "cassettes", name+".json"), opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		if err := r.Save(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	return r.HttpTransport()
+}
+
+// Recorder 录制或回放 HTTP 交互。
+type Recorder struct {
+	path          string
+	mode          Mode
+	redactHeaders []string
+
+	mu       sync.Mutex
+	cassette *Cassette
+	used     []bool
+}
+
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// Save 在录制模式下将交互写入文件。
+func (r *Recorder) Save() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.cassette.save(r.path)
+}
+
+func (r *Recorder) HttpTransport() client.HttpTransport {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return &transport{r: r, next: next}
+	}
+}
+
+type transport struct {
+	r    *Recorder
+	next http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	switch t.r.mode {
+	case ModePassthrough:
+		return t.next.RoundTrip(req)
+	case ModeRecord:
+		return t.r.record(req, t.next)
+	default:
+		return t.r.replay(req)
+	}
+}
+
+func (r *Recorder) replay(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	key := matchKey(req.Method, req.URL, req.Header.Get("Content-Type"), body)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// 优先使用未回放过的交互，以支持相同请求依次返回不同响应
+	matched := -1
+	for i, interaction := range r.cassette.Interactions {
+		if interaction.matchKey() != key {
+			continue
+		}
+		matched = i
+		if !r.used[i] {
+			break
+		}
+	}
+
+	if matched == -1 {
+		return nil, fmt.Errorf("%w: %s %s", ErrNoInteraction, req.Method, req.URL)
+	}
+
+	r.used[matched] = true
+
+	resp := r.cassette.Interactions[matched].Response
+	data := resp.Body.Bytes()
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", resp.StatusCode, http.StatusText(resp.StatusCode)),
+		StatusCode:    resp.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        resp.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(data)),
+		ContentLength: int64(len(data)),
+		Request:       req,
+	}, nil
+}
+
+func (r *Recorder) record(req *http.Request, next http.RoundTripper) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	interaction := &Interaction{
+		Request: Request{
+			Method: req.Method,
+			URL:    req.URL.String(),
+			Header: r.redact(req.Header),
+			Body:   bodyOf(body),
+		},
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Header:     r.redact(resp.Header),
+			Body:       bodyOf(respBody),
+		},
+	}
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	r.mu.Unlock()
+
+	return resp, nil
+}
+
+func (r *Recorder) redact(header http.Header) http.Header {
+	h := header.Clone()
+	for k := range h {
+		if slices.Contains(r.redactHeaders, http.CanonicalHeaderKey(k)) {
+			h[k] = []string{Redacted}
+		}
+	}
+	return h
+}
+
+func (i *Interaction) matchKey() string {
+	u, err := url.Parse(i.Request.URL)
+	if err != nil {
+		return ""
+	}
+	return matchKey(i.Request.Method, u, i.Request.Header.Get("Content-Type"), i.Request.Body.Bytes())
+}
+
+// readRequestBody 读取请求体并还原，以便继续发送
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	data, err := io.ReadAll(req.Body)
+	_ = req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	req.Body = io.NopCloser(bytes.NewReader(data))
+	req.ContentLength = int64(len(data))
+
+	return data, nil
+}
@@ -1,20 +1,25 @@
 package client
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/url"
 	"path"
 	"reflect"
+	"runtime"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/octohelm/courier/internal/httprequest"
 	"github.com/octohelm/courier/pkg/content"
 	"github.com/octohelm/courier/pkg/courier"
+	"github.com/octohelm/courier/pkg/courierhttp"
 	"github.com/octohelm/courier/pkg/courierhttp/transport"
 	"github.com/octohelm/courier/pkg/statuserror"
 )
@@ -54,12 +59,86 @@ type Client struct {
 	Endpoint string `flag:""`
 	UseH2c   bool   `flag:",omitzero"`
 
+	// 以下设置仅作用于当前客户端，均未设置时使用包级默认 transport
+	TLS         TLS         `flag:",omitzero"`
+	HostAliases []HostAlias `flag:",omitzero"`
+	// 代理地址，为空时读取环境变量 HTTP_PROXY 等
+	Proxy string `flag:",omitzero"`
+	// 建立连接超时，默认 30s
+	DialTimeout time.Duration `flag:",omitzero"`
+	// TCP keep-alive 间隔，默认 30s
+	KeepAlive         time.Duration `flag:",omitzero"`
+	DisableKeepAlives bool          `flag:",omitzero"`
+
 	NewError       func() error
 	HttpTransports []HttpTransport
+	// Interceptors 拦截 Do 调用，可获取请求 operator 以及解码后的结果与错误
+	Interceptors []courier.Interceptor
+	// OnDeprecation 在响应声明 `Deprecation` 头时调用，用于提示调用了已弃用的接口
+	OnDeprecation func(ctx context.Context, req *http.Request, d courierhttp.Deprecation)
 
 	endpoint *url.URL
 	parseErr error
 	once     sync.Once
+
+	roundTripper     http.RoundTripper
+	roundTripperErr  error
+	roundTripperOnce sync.Once
+}
+
+func (c *Client) hasTransportSettings() bool {
+	return !c.TLS.IsZero() || len(c.HostAliases) > 0 || c.Proxy != "" ||
+		c.DialTimeout != 0 || c.KeepAlive != 0 || c.DisableKeepAlives
+}
+
+func (c *Client) newRoundTripper() (http.RoundTripper, error) {
+	tlsConfig, err := c.TLS.Config()
+	if err != nil {
+		return nil, err
+	}
+
+	proxy := http.ProxyFromEnvironment
+	if c.Proxy != "" {
+		u, err := url.Parse(c.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy: %w", err)
+		}
+		proxy = http.ProxyURL(u)
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   cmp.Or(c.DialTimeout, 30*time.Second),
+		KeepAlive: cmp.Or(c.KeepAlive, 30*time.Second),
+	}
+
+	hosts := Hosts{}
+	for _, alias := range c.HostAliases {
+		hosts.AddHostAlias(alias)
+	}
+
+	t := &http.Transport{
+		Proxy: proxy,
+
+		// 客户端 host alias 优先于全局 host alias
+		DialContext: hosts.WrapDialContext(defaultHosts.WrapDialContext(dialer.DialContext)),
+
+		DisableKeepAlives: c.DisableKeepAlives,
+
+		TLSClientConfig: tlsConfig,
+
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+		ResponseHeaderTimeout: 60 * time.Second,
+	}
+
+	if !c.DisableKeepAlives {
+		t.MaxIdleConns = 100 * runtime.NumCPU()
+		t.MaxIdleConnsPerHost = 10 * runtime.NumCPU()
+		t.IdleConnTimeout = 90 * time.Second
+		t.ForceAttemptHTTP2 = true
+	}
+
+	return t, nil
 }
 
 func (c *Client) completeEndpoint(u *url.URL) error {
@@ -91,6 +170,13 @@ func (c *Client) completeEndpoint(u *url.URL) error {
 }
 
 func (c *Client) Do(ctx context.Context, req any, metas ...courier.Metadata) courier.Result {
+	if len(c.Interceptors) > 0 {
+		return courier.ChainInterceptors(c.Interceptors...)(c.do)(ctx, req, metas...)
+	}
+	return c.do(ctx, req, metas...)
+}
+
+func (c *Client) do(ctx context.Context, req any, metas ...courier.Metadata) courier.Result {
 	httpReq, ok := req.(*http.Request)
 	if !ok {
 		r, err := c.newRequest(ctx, req, metas...)
@@ -106,6 +192,21 @@ func (c *Client) Do(ctx context.Context, req any, metas ...courier.Metadata) cou
 	httpClient := HttpClientFromContext(ctx)
 	if httpClient == nil {
 		httpClient = GetReasonableClientContext(ctx)
+
+		if _, ok := RoundTripperCreatorFromContext(ctx); !ok && c.hasTransportSettings() {
+			c.roundTripperOnce.Do(func() {
+				c.roundTripper, c.roundTripperErr = c.newRoundTripper()
+			})
+
+			if c.roundTripperErr != nil {
+				return &result{
+					c:   c,
+					err: statuserror.Wrap(fmt.Errorf("构造客户端 transport 失败: %w", c.roundTripperErr), http.StatusInternalServerError, "InvalidTransport"),
+				}
+			}
+
+			httpClient.Transport = c.roundTripper
+		}
 	}
 
 	if httpClient.Transport == nil {
@@ -133,6 +234,12 @@ func (c *Client) Do(ctx context.Context, req any, metas ...courier.Metadata) cou
 		}
 	}
 
+	if c.OnDeprecation != nil {
+		if d, ok := courierhttp.ParseDeprecation(resp.Header); ok {
+			c.OnDeprecation(ctx, httpReq, d)
+		}
+	}
+
 	return &result{
 		c:        c,
 		Response: resp,
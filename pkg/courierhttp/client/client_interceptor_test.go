@@ -0,0 +1,61 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/octohelm/x/testing/v2"
+
+	"github.com/octohelm/courier/pkg/courier"
+)
+
+func TestClientInterceptors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("X-Token", r.Header.Get("Authorization"))
+		rw.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(srv.Close)
+
+	operationIDs := make([]string, 0)
+
+	c := &Client{
+		Endpoint: srv.URL,
+		Interceptors: []courier.Interceptor{
+			courier.InterceptorFor(func(ctx context.Context, req *testRequest, next courier.Invoker, metas ...courier.Metadata) courier.Result {
+				return next(ctx, req, append(metas, courier.Metadata{"Authorization": {"user-" + req.ID}})...)
+			}),
+			func(next courier.Invoker) courier.Invoker {
+				return func(ctx context.Context, req any, metas ...courier.Metadata) courier.Result {
+					operationIDs = append(operationIDs, fmt.Sprintf("%T", req))
+					return next(ctx, req, metas...)
+				}
+			},
+			func(next courier.Invoker) courier.Invoker {
+				return func(ctx context.Context, req any, metas ...courier.Metadata) courier.Result {
+					r := next(ctx, req, metas...)
+					return courier.WrapResult(r, r.Into)
+				}
+			},
+		},
+	}
+
+	meta, err := c.Do(context.Background(), &testRequest{ID: "1"}).Into(nil)
+
+	Then(t, "拦截器获取到请求 operator",
+		Expect(err, Equal[error](nil)),
+		Expect(meta.Get("X-Token"), Equal("user-1")),
+		Expect(operationIDs, Equal([]string{"*client.testRequest"})),
+	)
+
+	r := c.Do(context.Background(), &testRequest{ID: "2"})
+	_, err = r.Into(nil)
+
+	Then(t, "包装后的 Result 仍可获取状态码与响应头",
+		Expect(err, Equal[error](nil)),
+		Expect(r.(interface{ StatusCode() int }).StatusCode(), Equal(http.StatusNoContent)),
+		Expect(r.(interface{ Meta() courier.Metadata }).Meta().Get("X-Token"), Equal("user-2")),
+	)
+}
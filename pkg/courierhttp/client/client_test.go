@@ -182,6 +182,40 @@ func TestClientRequestAndDo(t0 *testing.T) {
 	)
 }
 
+func TestClientOnDeprecation(t0 *testing.T) {
+	var got courierhttp.Deprecation
+
+	c := &Client{
+		Endpoint: "https://example.com",
+		OnDeprecation: func(ctx context.Context, req *http.Request, d courierhttp.Deprecation) {
+			got = d
+		},
+	}
+
+	ctx := ContextWithHttpClient(context.Background(), &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusNoContent,
+				Header: http.Header{
+					"Deprecation": {"@1767225600"},
+					"Link":        {`</api/v2/users>; rel="successor-version"`},
+				},
+				Body:    io.NopCloser(bytes.NewBuffer(nil)),
+				Request: req,
+			}, nil
+		}),
+	})
+
+	_, err := c.Do(ctx, testRequest{ID: "1"}).Into(nil)
+
+	Then(
+		t0, "响应声明弃用时触发告警回调",
+		Expect(err, Equal[error](nil)),
+		Expect(got.At.Unix(), Equal(int64(1767225600))),
+		Expect(got.Successor, Equal("/api/v2/users")),
+	)
+}
+
 func TestClientResultIntoBranches(t0 *testing.T) {
 	req := mustRequest(http.MethodGet, "http://example.com", nil)
 
@@ -0,0 +1,219 @@
+package discovery
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/octohelm/x/testing/v2"
+
+	"github.com/octohelm/courier/pkg/courierhttp/client"
+)
+
+func newInstances(t *testing.T, n int, failed map[int]bool) []string {
+	addrs := make([]string, n)
+
+	for i := range n {
+		name := string(rune('a' + i))
+
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			rw.Header().Set("X-Instance", name)
+			if failed[i] {
+				rw.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			rw.WriteHeader(http.StatusNoContent)
+		}))
+		t.Cleanup(srv.Close)
+
+		addrs[i] = srv.URL
+	}
+
+	return addrs
+}
+
+func call(c *client.Client, header http.Header) string {
+	req, _ := http.NewRequest(http.MethodGet, "discovery://svc/ping", nil)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+
+	resp := c.Do(context.Background(), req)
+	meta, _ := resp.Into(nil)
+	return meta.Get("X-Instance")
+}
+
+func TestTransport(t *testing.T) {
+	t.Run("轮询", func(t *testing.T) {
+		c := &client.Client{
+			HttpTransports: []client.HttpTransport{
+				New(StaticResolver{"svc": newInstances(t, 3, nil)}),
+			},
+		}
+
+		picked := make([]string, 0, 6)
+		for range 6 {
+			picked = append(picked, call(c, nil))
+		}
+
+		Then(t, "依次选取各实例",
+			Expect(strings.Join(picked, ""), Equal("abcabc")),
+		)
+	})
+
+	t.Run("一致性哈希", func(t *testing.T) {
+		c := &client.Client{
+			HttpTransports: []client.HttpTransport{
+				New(StaticResolver{"svc": newInstances(t, 3, nil)}, WithPolicy(ConsistentHash("X-Tenant"))),
+			},
+		}
+
+		first := call(c, http.Header{"X-Tenant": {"t1"}})
+		same := true
+		for range 5 {
+			same = same && call(c, http.Header{"X-Tenant": {"t1"}}) == first
+		}
+
+		Then(t, "相同租户始终命中同一实例",
+			Expect(first != "", Equal(true)),
+			Expect(same, Equal(true)),
+		)
+	})
+
+	t.Run("连续失败的实例被摘除", func(t *testing.T) {
+		c := &client.Client{
+			HttpTransports: []client.HttpTransport{
+				New(StaticResolver{"svc": newInstances(t, 2, map[int]bool{0: true})}, WithOutlierDetection(2, time.Minute)),
+			},
+		}
+
+		picked := make([]string, 0, 8)
+		for range 8 {
+			picked = append(picked, call(c, nil))
+		}
+
+		Then(t, "失败两次后只请求健康实例",
+			Expect(strings.Join(picked, ""), Equal("ababbbbb")),
+		)
+	})
+
+	t.Run("未知服务", func(t *testing.T) {
+		c := &client.Client{
+			HttpTransports: []client.HttpTransport{
+				New(StaticResolver{}),
+			},
+		}
+
+		req, _ := http.NewRequest(http.MethodGet, "discovery://svc/ping", nil)
+		_, err := c.Do(context.Background(), req).Into(nil)
+
+		Then(t, "返回错误",
+			Expect(err != nil, Equal(true)),
+			Expect(strings.Contains(err.Error(), ErrServiceNotFound.Error()), Equal(true)),
+		)
+	})
+
+	t.Run("以 Endpoint 指定服务", func(t *testing.T) {
+		c := &client.Client{
+			Endpoint: "discovery://svc",
+			HttpTransports: []client.HttpTransport{
+				New(StaticResolver{"svc": newInstances(t, 1, nil)}),
+			},
+		}
+
+		meta, err := c.Do(context.Background(), &struct{}{}).Into(nil)
+
+		Then(t, "请求发送到解析出的实例",
+			Expect(err, Equal[error](nil)),
+			Expect(meta.Get("X-Instance"), Equal("a")),
+		)
+	})
+}
+
+func TestLeastInFlight(t *testing.T) {
+	endpoints := []*Endpoint{{Instance: Instance{Host: "a"}}, {Instance: Instance{Host: "b"}}}
+	endpoints[0].inFlight.Add(2)
+
+	p := LeastInFlight()
+
+	Then(t, "选取处理中请求最少的实例",
+		Expect(p.Pick(nil, endpoints).Host, Equal("b")),
+		Expect(p.Pick(nil, endpoints).Host, Equal("b")),
+	)
+}
+
+type fakeSRVLookuper struct {
+	records []*net.SRV
+	queries [][3]string
+}
+
+func (f *fakeSRVLookuper) LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error) {
+	f.queries = append(f.queries, [3]string{service, proto, name})
+	return name, f.records, nil
+}
+
+func TestDNSSRVResolver(t *testing.T) {
+	lookuper := &fakeSRVLookuper{
+		records: []*net.SRV{
+			{Target: "a.svc.local.", Port: 8080, Priority: 10},
+			{Target: "b.svc.local.", Port: 8080, Priority: 10},
+			{Target: "backup.svc.local.", Port: 8080, Priority: 20},
+		},
+	}
+
+	r := &DNSSRVResolver{Lookuper: lookuper}
+
+	instances, err := r.Resolve(context.Background(), "svc")
+
+	Then(t, "只使用优先级最高的记录",
+		Expect(err, Equal[error](nil)),
+		Expect(instances, Equal([]Instance{
+			{Scheme: "http", Host: "a.svc.local:8080"},
+			{Scheme: "http", Host: "b.svc.local:8080"},
+		})),
+	)
+
+	Then(t, "未设置 Service 时直接查询 name",
+		Expect(lookuper.queries, Equal([][3]string{{"", "", "svc"}})),
+	)
+
+	lookuper.queries = nil
+	_, _ = (&DNSSRVResolver{Lookuper: lookuper, Service: "http"}).Resolve(context.Background(), "svc.local")
+
+	Then(t, "设置 Service 时按 _service._proto.name 查询，proto 默认 tcp",
+		Expect(lookuper.queries, Equal([][3]string{{"http", "tcp", "svc.local"}})),
+	)
+}
+
+func TestFileResolver(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "services.json")
+
+	write := func(content string, modTime time.Time) {
+		_ = os.WriteFile(file, []byte(content), 0o644)
+		_ = os.Chtimes(file, modTime, modTime)
+	}
+
+	now := time.Now()
+	write(`{"svc":["10.0.0.1:80"]}`, now)
+
+	r := &FileResolver{Path: file, Interval: time.Nanosecond}
+
+	before, err := r.Resolve(context.Background(), "svc")
+	Then(t, "加载文件", Expect(err, Equal[error](nil)))
+
+	write(`{"svc":["10.0.0.2:80","10.0.0.3:80"]}`, now.Add(time.Second))
+
+	after, err := r.Resolve(context.Background(), "svc")
+
+	Then(t, "文件变化后重新加载",
+		Expect(err, Equal[error](nil)),
+		Expect(before, Equal([]Instance{{Scheme: "http", Host: "10.0.0.1:80"}})),
+		Expect(len(after), Equal(2)),
+	)
+}
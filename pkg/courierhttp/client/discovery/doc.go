@@ -0,0 +1,16 @@
+// Package discovery 为 `client.Client` 提供服务发现与客户端负载均衡。
+//
+// `New` 返回一个 `client.HttpTransport`，将 `discovery://<service>` 形式的请求地址
+// 经 `Resolver` 解析为实例列表，再按 `Policy` 选取实例改写请求地址：
+//
+//	c := &client.Client{
+//		Endpoint:       "discovery://user-service/api",
+//		HttpTransports: []client.HttpTransport{discovery.New(resolver, discovery.WithPolicy(discovery.LeastInFlight()))},
+//	}
+//
+// 解析器包括静态列表 `StaticResolver`、DNS SRV `DNSSRVResolver` 与定期重载文件的 `FileResolver`；
+// 均衡策略包括 `RoundRobin`、`LeastInFlight` 与按请求头一致性哈希的 `ConsistentHash`。
+// 连续失败的实例会被临时摘除（被动健康检查），摘除到期后自动恢复。
+//
+// +gengo:runtimedoc=false
+package discovery
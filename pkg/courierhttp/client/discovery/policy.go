@@ -0,0 +1,104 @@
+package discovery
+
+import (
+	"hash/fnv"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// Endpoint 为负载均衡中的实例及其运行状态。
+type Endpoint struct {
+	Instance
+
+	inFlight     atomic.Int64
+	failures     atomic.Int32
+	ejectedUntil atomic.Int64
+}
+
+// InFlight 返回正在处理的请求数。
+func (e *Endpoint) InFlight() int64 {
+	return e.inFlight.Load()
+}
+
+func (e *Endpoint) ejected(now time.Time) bool {
+	return now.UnixNano() < e.ejectedUntil.Load()
+}
+
+// Policy 为负载均衡策略，endpoints 非空。
+type Policy interface {
+	Pick(req *http.Request, endpoints []*Endpoint) *Endpoint
+}
+
+// RoundRobin 依次轮询实例。
+func RoundRobin() Policy {
+	return &roundRobin{}
+}
+
+type roundRobin struct {
+	next atomic.Uint64
+}
+
+func (p *roundRobin) Pick(req *http.Request, endpoints []*Endpoint) *Endpoint {
+	return endpoints[(p.next.Add(1)-1)%uint64(len(endpoints))]
+}
+
+// LeastInFlight 选取正在处理请求数最少的实例，相同时轮询。
+func LeastInFlight() Policy {
+	return &leastInFlight{}
+}
+
+type leastInFlight struct {
+	next atomic.Uint64
+}
+
+func (p *leastInFlight) Pick(req *http.Request, endpoints []*Endpoint) *Endpoint {
+	n := uint64(len(endpoints))
+	offset := p.next.Add(1) - 1
+
+	var picked *Endpoint
+
+	for i := range n {
+		e := endpoints[(offset+i)%n]
+		if picked == nil || e.InFlight() < picked.InFlight() {
+			picked = e
+		}
+	}
+
+	return picked
+}
+
+// ConsistentHash 按请求头 key 的取值一致性哈希选取实例，实例增减时只影响部分请求。
+// 请求未携带该请求头时轮询。
+func ConsistentHash(key string) Policy {
+	return &consistentHash{key: key}
+}
+
+type consistentHash struct {
+	key      string
+	fallback roundRobin
+}
+
+func (p *consistentHash) Pick(req *http.Request, endpoints []*Endpoint) *Endpoint {
+	v := req.Header.Get(p.key)
+	if v == "" {
+		return p.fallback.Pick(req, endpoints)
+	}
+
+	// rendezvous hashing
+	var picked *Endpoint
+	var max uint64
+
+	for _, e := range endpoints {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(v))
+		_, _ = h.Write([]byte{0})
+		_, _ = h.Write([]byte(e.Host))
+
+		if score := h.Sum64(); picked == nil || score > max {
+			picked, max = e, score
+		}
+	}
+
+	return picked
+}
@@ -0,0 +1,242 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-json-experiment/json"
+)
+
+var ErrServiceNotFound = errors.New("service not found")
+
+// Instance 表示服务的一个实例。
+type Instance struct {
+	// 协议，默认 http
+	Scheme string
+	// host:port
+	Host string
+}
+
+func (i Instance) String() string {
+	return i.Scheme + "://" + i.Host
+}
+
+// ParseInstance 解析 `http://10.0.0.1:8080` 或 `10.0.0.1:8080` 形式的实例地址。
+func ParseInstance(s string) (Instance, error) {
+	if !strings.Contains(s, "://") {
+		s = "http://" + s
+	}
+
+	u, err := url.Parse(s)
+	if err != nil {
+		return Instance{}, fmt.Errorf("invalid instance %q: %w", s, err)
+	}
+
+	if u.Host == "" {
+		return Instance{}, fmt.Errorf("invalid instance %q: missing host", s)
+	}
+
+	return Instance{Scheme: u.Scheme, Host: u.Host}, nil
+}
+
+// Resolver 将服务名解析为实例列表。
+type Resolver interface {
+	Resolve(ctx context.Context, service string) ([]Instance, error)
+}
+
+// StaticResolver 为服务名到实例地址的静态映射。
+type StaticResolver map[string][]string
+
+func (r StaticResolver) Resolve(ctx context.Context, service string) ([]Instance, error) {
+	addrs, ok := r[service]
+	if !ok || len(addrs) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrServiceNotFound, service)
+	}
+	return parseInstances(addrs)
+}
+
+func parseInstances(addrs []string) ([]Instance, error) {
+	instances := make([]Instance, 0, len(addrs))
+	for _, addr := range addrs {
+		i, err := ParseInstance(addr)
+		if err != nil {
+			return nil, err
+		}
+		instances = append(instances, i)
+	}
+	return instances, nil
+}
+
+// SRVLookuper 用于查询 SRV 记录，`*net.Resolver` 满足该接口。
+type SRVLookuper interface {
+	LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error)
+}
+
+// DNSSRVResolver 通过 DNS SRV 记录解析实例，仅使用优先级最高（数值最小）的一组记录。
+type DNSSRVResolver struct {
+	// 默认 net.DefaultResolver
+	Lookuper SRVLookuper
+	// SRV 服务名，为空时直接查询 name
+	Service string
+	// 默认 tcp，Service 为空时忽略
+	Proto string
+	// 实例协议，默认 http
+	Scheme string
+	// 解析结果缓存时长，为 0 时不缓存
+	TTL time.Duration
+
+	mu    sync.Mutex
+	cache map[string]cachedInstances
+}
+
+type cachedInstances struct {
+	instances []Instance
+	expiresAt time.Time
+}
+
+func (r *DNSSRVResolver) Resolve(ctx context.Context, service string) ([]Instance, error) {
+	if r.TTL > 0 {
+		r.mu.Lock()
+		c, ok := r.cache[service]
+		r.mu.Unlock()
+
+		if ok && time.Now().Before(c.expiresAt) {
+			return c.instances, nil
+		}
+	}
+
+	lookuper := r.Lookuper
+	if lookuper == nil {
+		lookuper = net.DefaultResolver
+	}
+
+	// service 与 proto 均为空时 net.Resolver 才直接查询 name
+	proto := ""
+	if r.Service != "" {
+		proto = r.Proto
+		if proto == "" {
+			proto = "tcp"
+		}
+	}
+
+	scheme := r.Scheme
+	if scheme == "" {
+		scheme = "http"
+	}
+
+	_, records, err := lookuper.LookupSRV(ctx, r.Service, proto, service)
+	if err != nil {
+		return nil, fmt.Errorf("lookup srv of %s failed: %w", service, err)
+	}
+
+	if len(records) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrServiceNotFound, service)
+	}
+
+	instances := make([]Instance, 0, len(records))
+	priority := records[0].Priority
+
+	for _, srv := range records {
+		if srv.Priority < priority {
+			priority = srv.Priority
+		}
+	}
+
+	for _, srv := range records {
+		if srv.Priority != priority {
+			continue
+		}
+		instances = append(instances, Instance{
+			Scheme: scheme,
+			Host:   net.JoinHostPort(strings.TrimSuffix(srv.Target, "."), strconv.Itoa(int(srv.Port))),
+		})
+	}
+
+	if r.TTL > 0 {
+		r.mu.Lock()
+		if r.cache == nil {
+			r.cache = map[string]cachedInstances{}
+		}
+		r.cache[service] = cachedInstances{instances: instances, expiresAt: time.Now().Add(r.TTL)}
+		r.mu.Unlock()
+	}
+
+	return instances, nil
+}
+
+// FileResolver 从 JSON 文件 `{"<service>": ["<addr>", ...]}` 解析实例，
+// 文件修改时间变化后自动重新加载。
+type FileResolver struct {
+	Path string
+	// 检查文件变化的最小间隔，默认 5s
+	Interval time.Duration
+
+	mu        sync.Mutex
+	checkedAt time.Time
+	modTime   time.Time
+	services  StaticResolver
+}
+
+func (r *FileResolver) Resolve(ctx context.Context, service string) ([]Instance, error) {
+	services, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+	return services.Resolve(ctx, service)
+}
+
+func (r *FileResolver) load() (StaticResolver, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	interval := r.Interval
+	if interval == 0 {
+		interval = 5 * time.Second
+	}
+
+	now := time.Now()
+	if r.services != nil && now.Sub(r.checkedAt) < interval {
+		return r.services, nil
+	}
+	r.checkedAt = now
+
+	info, err := os.Stat(r.Path)
+	if err != nil {
+		if r.services != nil {
+			// 文件暂时不可读时沿用上次结果
+			return r.services, nil
+		}
+		return nil, err
+	}
+
+	if r.services != nil && info.ModTime().Equal(r.modTime) {
+		return r.services, nil
+	}
+
+	data, err := os.ReadFile(r.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	services := StaticResolver{}
+	if err := json.Unmarshal(data, &services); err != nil {
+		if r.services != nil {
+			// 文件写入中途读取可能不完整，沿用上次结果
+			return r.services, nil
+		}
+		return nil, fmt.Errorf("invalid service list %s: %w", r.Path, err)
+	}
+
+	r.services = services
+	r.modTime = info.ModTime()
+
+	return services, nil
+}
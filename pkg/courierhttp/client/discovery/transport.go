@@ -0,0 +1,186 @@
+package discovery
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/octohelm/courier/pkg/courierhttp/client"
+)
+
+// Scheme 为需经服务发现解析的请求地址协议，如 `discovery://user-service`。
+const Scheme = "discovery"
+
+type OptionFunc func(t *transport)
+
+// WithPolicy 设置负载均衡策略，默认 RoundRobin。
+func WithPolicy(p Policy) OptionFunc {
+	return func(t *transport) {
+		t.policy = p
+	}
+}
+
+// WithOutlierDetection 设置实例连续失败 maxFailures 次（网络错误或 5xx）后摘除 ejectFor 时长，
+// 默认 5 次、30s，maxFailures 不大于 0 时不摘除。
+func WithOutlierDetection(maxFailures int, ejectFor time.Duration) OptionFunc {
+	return func(t *transport) {
+		t.maxFailures = maxFailures
+		t.ejectFor = ejectFor
+	}
+}
+
+// New 创建经 resolver 解析 `discovery://` 请求的 HttpTransport，其他请求原样发送。
+func New(resolver Resolver, opts ...OptionFunc) client.HttpTransport {
+	shared := newTransport(resolver, opts...)
+
+	return func(rt http.RoundTripper) http.RoundTripper {
+		t := *shared
+		t.next = rt
+		return &t
+	}
+}
+
+func newTransport(resolver Resolver, opts ...OptionFunc) *transport {
+	t := &transport{
+		resolver:    resolver,
+		policy:      RoundRobin(),
+		maxFailures: 5,
+		ejectFor:    30 * time.Second,
+		now:         time.Now,
+		services:    &sync.Map{},
+	}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	return t
+}
+
+type transport struct {
+	next        http.RoundTripper
+	resolver    Resolver
+	policy      Policy
+	maxFailures int
+	ejectFor    time.Duration
+	now         func() time.Time
+	services    *sync.Map
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Scheme != Scheme {
+		return t.next.RoundTrip(req)
+	}
+
+	ctx := req.Context()
+	name := req.URL.Hostname()
+
+	instances, err := t.resolver.Resolve(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(instances) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrServiceNotFound, name)
+	}
+
+	s, _ := t.services.LoadOrStore(name, &service{})
+
+	e := t.policy.Pick(req, t.available(s.(*service).sync(instances)))
+
+	r := req.Clone(ctx)
+	r.URL.Scheme = e.Scheme
+	r.URL.Host = e.Host
+	r.Host = ""
+
+	e.inFlight.Add(1)
+
+	resp, err := t.next.RoundTrip(r)
+	if err != nil {
+		e.inFlight.Add(-1)
+		t.report(e, false)
+		return nil, err
+	}
+
+	t.report(e, resp.StatusCode < http.StatusInternalServerError)
+
+	resp.Body = &inFlightBody{ReadCloser: resp.Body, e: e}
+
+	return resp, nil
+}
+
+// available 过滤被摘除的实例，全部被摘除时返回全部实例
+func (t *transport) available(endpoints []*Endpoint) []*Endpoint {
+	now := t.now()
+
+	available := make([]*Endpoint, 0, len(endpoints))
+	for _, e := range endpoints {
+		if !e.ejected(now) {
+			available = append(available, e)
+		}
+	}
+
+	if len(available) == 0 {
+		return endpoints
+	}
+
+	return available
+}
+
+func (t *transport) report(e *Endpoint, ok bool) {
+	if ok {
+		e.failures.Store(0)
+		return
+	}
+
+	if t.maxFailures <= 0 {
+		return
+	}
+
+	if int(e.failures.Add(1)) >= t.maxFailures {
+		e.failures.Store(0)
+		e.ejectedUntil.Store(t.now().Add(t.ejectFor).UnixNano())
+	}
+}
+
+type service struct {
+	mu        sync.Mutex
+	endpoints map[Instance]*Endpoint
+}
+
+// sync 按最新解析结果更新实例列表，保留已有实例的运行状态
+func (s *service) sync(instances []Instance) []*Endpoint {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	endpoints := make(map[Instance]*Endpoint, len(instances))
+	list := make([]*Endpoint, 0, len(instances))
+
+	for _, i := range instances {
+		e, ok := s.endpoints[i]
+		if !ok {
+			e = &Endpoint{Instance: i}
+		}
+		endpoints[i] = e
+		list = append(list, e)
+	}
+
+	s.endpoints = endpoints
+
+	return list
+}
+
+type inFlightBody struct {
+	io.ReadCloser
+	e    *Endpoint
+	once sync.Once
+}
+
+func (b *inFlightBody) Close() error {
+	b.once.Do(func() {
+		b.e.inFlight.Add(-1)
+	})
+	return b.ReadCloser.Close()
+}
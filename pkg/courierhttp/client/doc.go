@@ -3,4 +3,7 @@
 // `Client` 负责把 operator 请求编码成 HTTP 请求，发送后再按 courier 约定解码
 // 成成功结果或 `statuserror`。包内还提供 HTTP transport 链、默认连接策略、
 // host alias 与 context 注入等辅助能力。
+//
+// `Client` 的 `TLS`、`HostAliases`、`Proxy` 等字段只作用于当前客户端，
+// 与 `Endpoint` 一样可通过 `flag` 标签从环境变量或配置加载；均未设置时使用包级默认 transport。
 package client
@@ -0,0 +1,49 @@
+package httpcache
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CacheControl 为解析后的 `Cache-Control` 指令。
+type CacheControl map[string]string
+
+func ParseCacheControl(header http.Header) CacheControl {
+	cc := CacheControl{}
+
+	for _, v := range header.Values("Cache-Control") {
+		for directive := range strings.SplitSeq(v, ",") {
+			directive = strings.TrimSpace(directive)
+			if directive == "" {
+				continue
+			}
+
+			name, value, _ := strings.Cut(directive, "=")
+			cc[strings.ToLower(strings.TrimSpace(name))] = strings.Trim(strings.TrimSpace(value), `"`)
+		}
+	}
+
+	return cc
+}
+
+func (cc CacheControl) Has(name string) bool {
+	_, ok := cc[name]
+	return ok
+}
+
+// Duration 返回以秒为单位的指令值。
+func (cc CacheControl) Duration(name string) (time.Duration, bool) {
+	v, ok := cc[name]
+	if !ok {
+		return 0, false
+	}
+
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || n < 0 {
+		return 0, false
+	}
+
+	return time.Duration(n) * time.Second, true
+}
@@ -0,0 +1,16 @@
+// Package httpcache 为 `client.Client` 提供遵循 RFC 9111 的私有 HTTP 缓存。
+//
+// `New` 返回一个 `client.HttpTransport`，对 GET/HEAD 请求：
+//
+//   - 按 `Cache-Control: max-age`、`Expires` 或 `Last-Modified` 启发式计算新鲜度；
+//   - 按 `Vary` 匹配请求头；
+//   - 携带 `Authorization` 的请求仅在响应声明 `public`、`s-maxage` 或 `must-revalidate` 时缓存，
+//     鉴权 HttpTransport 需位于缓存内层，缓存才能看到 `Authorization`；
+//   - 过期后以 `If-None-Match`/`If-Modified-Since` 重新验证，304 时复用缓存；
+//   - 在 `stale-while-revalidate` 窗口内先返回过期响应，并在后台重新验证。
+//
+// 非安全方法成功后会使同一地址的缓存失效。缓存存取通过 `Store` 抽象，
+// `NewMemStore` 提供 LRU 内存实现，`NewDiskStore` 提供本地目录实现。
+//
+// +gengo:runtimedoc=false
+package httpcache
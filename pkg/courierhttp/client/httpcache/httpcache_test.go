@@ -0,0 +1,296 @@
+package httpcache
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/octohelm/x/testing/v2"
+)
+
+type clock struct {
+	t time.Time
+}
+
+func (c *clock) now() time.Time {
+	return c.t
+}
+
+func (c *clock) add(d time.Duration) {
+	c.t = c.t.Add(d)
+}
+
+func newTestClient(store Store, c *clock, opts ...OptionFunc) *http.Client {
+	t := newTransport(store, opts...)
+	t.now = c.now
+	t.next = http.DefaultTransport
+	return &http.Client{Transport: t}
+}
+
+func get(t *testing.T, c *http.Client, url string, header http.Header) (*http.Response, string) {
+	t.Helper()
+
+	req, _ := http.NewRequest(http.MethodGet, url, nil)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	data, _ := io.ReadAll(resp.Body)
+	return resp, string(data)
+}
+
+func TestTransport(t *testing.T) {
+	t.Run("新鲜期内命中缓存", func(t *testing.T) {
+		hits := atomic.Int32{}
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			hits.Add(1)
+			rw.Header().Set("Cache-Control", "max-age=60")
+			_, _ = io.WriteString(rw, "hello")
+		}))
+		t.Cleanup(srv.Close)
+
+		c := &clock{t: time.Now()}
+		hc := newTestClient(NewMemStore(10), c)
+
+		_, body := get(t, hc, srv.URL, nil)
+		c.add(30 * time.Second)
+		resp, cached := get(t, hc, srv.URL, nil)
+
+		Then(t, "第二次请求未到达服务端",
+			Expect(body, Equal("hello")),
+			Expect(cached, Equal("hello")),
+			Expect(hits.Load(), Equal(int32(1))),
+			Expect(resp.Header.Get("Age"), Equal("30")),
+		)
+
+		c.add(time.Minute)
+		get(t, hc, srv.URL, nil)
+
+		Then(t, "过期后重新请求",
+			Expect(hits.Load(), Equal(int32(2))),
+		)
+
+		get(t, hc, srv.URL, http.Header{"Cache-Control": {"no-cache"}})
+
+		Then(t, "请求 no-cache 强制回源",
+			Expect(hits.Load(), Equal(int32(3))),
+		)
+	})
+
+	t.Run("过期后以 ETag 重新验证", func(t *testing.T) {
+		c := &clock{t: time.Now()}
+
+		full := atomic.Int32{}
+		notModified := atomic.Int32{}
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			rw.Header().Set("Date", c.now().UTC().Format(http.TimeFormat))
+			rw.Header().Set("Cache-Control", "max-age=10")
+			rw.Header().Set("ETag", `"v1"`)
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				notModified.Add(1)
+				rw.WriteHeader(http.StatusNotModified)
+				return
+			}
+			full.Add(1)
+			_, _ = io.WriteString(rw, "v1")
+		}))
+		t.Cleanup(srv.Close)
+
+		hc := newTestClient(NewMemStore(10), c)
+
+		get(t, hc, srv.URL, nil)
+		c.add(20 * time.Second)
+		resp, body := get(t, hc, srv.URL, nil)
+
+		Then(t, "304 时返回缓存内容",
+			Expect(resp.StatusCode, Equal(http.StatusOK)),
+			Expect(body, Equal("v1")),
+			Expect(full.Load(), Equal(int32(1))),
+			Expect(notModified.Load(), Equal(int32(1))),
+		)
+
+		get(t, hc, srv.URL, nil)
+
+		Then(t, "重新验证后恢复新鲜",
+			Expect(notModified.Load(), Equal(int32(1))),
+		)
+	})
+
+	t.Run("按 Vary 区分缓存", func(t *testing.T) {
+		hits := atomic.Int32{}
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			hits.Add(1)
+			rw.Header().Set("Cache-Control", "max-age=60")
+			rw.Header().Set("Vary", "Accept-Language")
+			_, _ = io.WriteString(rw, r.Header.Get("Accept-Language"))
+		}))
+		t.Cleanup(srv.Close)
+
+		c := &clock{t: time.Now()}
+		hc := newTestClient(NewMemStore(10), c)
+
+		_, zh := get(t, hc, srv.URL, http.Header{"Accept-Language": {"zh"}})
+		_, en := get(t, hc, srv.URL, http.Header{"Accept-Language": {"en"}})
+		_, again := get(t, hc, srv.URL, http.Header{"Accept-Language": {"en"}})
+
+		Then(t, "不同语言分别回源",
+			Expect(zh, Equal("zh")),
+			Expect(en, Equal("en")),
+			Expect(again, Equal("en")),
+			Expect(hits.Load(), Equal(int32(2))),
+		)
+	})
+
+	t.Run("携带 Authorization 的请求不共享缓存", func(t *testing.T) {
+		hits := atomic.Int32{}
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			hits.Add(1)
+			rw.Header().Set("Cache-Control", r.URL.Query().Get("cc"))
+			_, _ = io.WriteString(rw, r.Header.Get("Authorization"))
+		}))
+		t.Cleanup(srv.Close)
+
+		c := &clock{t: time.Now()}
+		hc := newTestClient(NewMemStore(10), c)
+
+		_, a := get(t, hc, srv.URL+"?cc=max-age=60", http.Header{"Authorization": {"Bearer a"}})
+		_, b := get(t, hc, srv.URL+"?cc=max-age=60", http.Header{"Authorization": {"Bearer b"}})
+
+		Then(t, "不同凭证分别回源，不会取得他人的响应",
+			Expect(a, Equal("Bearer a")),
+			Expect(b, Equal("Bearer b")),
+			Expect(hits.Load(), Equal(int32(2))),
+		)
+
+		get(t, hc, srv.URL+"?cc=public,max-age=60", http.Header{"Authorization": {"Bearer a"}})
+		_, shared := get(t, hc, srv.URL+"?cc=public,max-age=60", http.Header{"Authorization": {"Bearer b"}})
+
+		Then(t, "响应声明 public 时可共享",
+			Expect(shared, Equal("Bearer a")),
+			Expect(hits.Load(), Equal(int32(3))),
+		)
+	})
+
+	t.Run("stale-while-revalidate 窗口内返回过期内容并后台更新", func(t *testing.T) {
+		version := atomic.Int32{}
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			rw.Header().Set("Cache-Control", "max-age=10, stale-while-revalidate=60")
+			if version.Add(1) == 1 {
+				_, _ = io.WriteString(rw, "v1")
+				return
+			}
+			_, _ = io.WriteString(rw, "v2")
+		}))
+		t.Cleanup(srv.Close)
+
+		c := &clock{t: time.Now()}
+		store := NewMemStore(10)
+		hc := newTestClient(store, c)
+
+		get(t, hc, srv.URL, nil)
+		c.add(20 * time.Second)
+		_, stale := get(t, hc, srv.URL, nil)
+
+		Then(t, "先返回过期内容",
+			Expect(stale, Equal("v1")),
+		)
+
+		Then(t, "后台更新缓存",
+			ExpectMust(func() error {
+				for range 100 {
+					if e, ok := store.Get(t.Context(), "GET "+srv.URL); ok && string(e.Body) == "v2" {
+						return nil
+					}
+					time.Sleep(10 * time.Millisecond)
+				}
+				return io.ErrUnexpectedEOF
+			}),
+		)
+	})
+
+	t.Run("非安全方法使缓存失效", func(t *testing.T) {
+		hits := atomic.Int32{}
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodGet {
+				hits.Add(1)
+			}
+			rw.Header().Set("Cache-Control", "max-age=60")
+			_, _ = io.WriteString(rw, "ok")
+		}))
+		t.Cleanup(srv.Close)
+
+		c := &clock{t: time.Now()}
+		hc := newTestClient(NewDiskStore(t.TempDir()), c)
+
+		get(t, hc, srv.URL, nil)
+		get(t, hc, srv.URL, nil)
+
+		Then(t, "磁盘缓存命中",
+			Expect(hits.Load(), Equal(int32(1))),
+		)
+
+		req, _ := http.NewRequest(http.MethodPut, srv.URL, nil)
+		resp, err := hc.Do(req)
+		Then(t, "PUT 成功", Expect(err, Equal[error](nil)))
+		_ = resp.Body.Close()
+
+		get(t, hc, srv.URL, nil)
+
+		Then(t, "PUT 后重新回源",
+			Expect(hits.Load(), Equal(int32(2))),
+		)
+	})
+
+	t.Run("no-store 与超大响应不缓存", func(t *testing.T) {
+		hits := atomic.Int32{}
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			hits.Add(1)
+			if r.URL.Path == "/no-store" {
+				rw.Header().Set("Cache-Control", "no-store")
+			} else {
+				rw.Header().Set("Cache-Control", "max-age=60")
+			}
+			_, _ = io.WriteString(rw, "0123456789")
+		}))
+		t.Cleanup(srv.Close)
+
+		c := &clock{t: time.Now()}
+		hc := newTestClient(NewMemStore(10), c, WithMaxBodySize(4))
+
+		get(t, hc, srv.URL+"/no-store", nil)
+		get(t, hc, srv.URL+"/no-store", nil)
+		_, large := get(t, hc, srv.URL+"/large", nil)
+		get(t, hc, srv.URL+"/large", nil)
+
+		Then(t, "每次都回源且响应完整",
+			Expect(large, Equal("0123456789")),
+			Expect(hits.Load(), Equal(int32(4))),
+		)
+	})
+}
+
+func TestMemStore(t *testing.T) {
+	s := NewMemStore(2)
+
+	s.Set(t.Context(), "a", &Entry{})
+	s.Set(t.Context(), "b", &Entry{})
+	_, _ = s.Get(t.Context(), "a")
+	s.Set(t.Context(), "c", &Entry{})
+
+	_, hasA := s.Get(t.Context(), "a")
+	_, hasB := s.Get(t.Context(), "b")
+
+	Then(t, "淘汰最久未使用的条目",
+		Expect(hasA, Equal(true)),
+		Expect(hasB, Equal(false)),
+	)
+}
@@ -0,0 +1,177 @@
+package httpcache
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/go-json-experiment/json"
+)
+
+// Entry 为缓存的响应。
+type Entry struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	// 参与 `Vary` 匹配的请求头取值
+	VaryHeader http.Header
+	// 发出请求的时间
+	RequestTime time.Time
+	// 收到响应的时间
+	ResponseTime time.Time
+}
+
+func (e *Entry) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode)),
+		StatusCode:    e.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.Header.Clone(),
+		Body:          nopCloser{bytes.NewReader(e.Body)},
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+type nopCloser struct {
+	*bytes.Reader
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
+
+// Store 表示缓存存储。
+type Store interface {
+	Get(ctx context.Context, key string) (*Entry, bool)
+	Set(ctx context.Context, key string, e *Entry)
+	Delete(ctx context.Context, key string)
+}
+
+// NewMemStore 创建最多保留 capacity 个响应的 LRU 内存缓存。
+func NewMemStore(capacity int) Store {
+	return &memStore{
+		capacity: capacity,
+		entries:  map[string]*list.Element{},
+		ll:       list.New(),
+	}
+}
+
+type memStore struct {
+	capacity int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	ll      *list.List
+}
+
+type memItem struct {
+	key   string
+	entry *Entry
+}
+
+func (s *memStore) Get(ctx context.Context, key string) (*Entry, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if el, ok := s.entries[key]; ok {
+		s.ll.MoveToFront(el)
+		return el.Value.(*memItem).entry, true
+	}
+	return nil, false
+}
+
+func (s *memStore) Set(ctx context.Context, key string, e *Entry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if el, ok := s.entries[key]; ok {
+		el.Value.(*memItem).entry = e
+		s.ll.MoveToFront(el)
+		return
+	}
+
+	s.entries[key] = s.ll.PushFront(&memItem{key: key, entry: e})
+
+	if s.capacity > 0 && s.ll.Len() > s.capacity {
+		last := s.ll.Back()
+		s.ll.Remove(last)
+		delete(s.entries, last.Value.(*memItem).key)
+	}
+}
+
+func (s *memStore) Delete(ctx context.Context, key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if el, ok := s.entries[key]; ok {
+		s.ll.Remove(el)
+		delete(s.entries, key)
+	}
+}
+
+// NewDiskStore 创建以本地目录 dir 保存响应的缓存，每个响应一个文件。
+func NewDiskStore(dir string) Store {
+	return &diskStore{dir: dir}
+}
+
+type diskStore struct {
+	dir string
+}
+
+func (s *diskStore) Get(ctx context.Context, key string) (*Entry, bool) {
+	data, err := os.ReadFile(s.path(key))
+	if err != nil {
+		return nil, false
+	}
+
+	e := &Entry{}
+	if err := json.Unmarshal(data, e); err != nil {
+		return nil, false
+	}
+	return e, true
+}
+
+func (s *diskStore) Set(ctx context.Context, key string, e *Entry) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return
+	}
+
+	// 先写临时文件再替换，避免并发读取到不完整的内容
+	f, err := os.CreateTemp(s.dir, ".tmp-*")
+	if err != nil {
+		return
+	}
+	_, err = f.Write(data)
+	_ = f.Close()
+	if err == nil {
+		err = os.Rename(f.Name(), s.path(key))
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+	}
+}
+
+func (s *diskStore) Delete(ctx context.Context, key string) {
+	_ = os.Remove(s.path(key))
+}
+
+func (s *diskStore) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:]))
+}
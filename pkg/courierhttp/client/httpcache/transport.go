@@ -0,0 +1,362 @@
+package httpcache
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/octohelm/courier/pkg/courierhttp/client"
+)
+
+type OptionFunc func(t *transport)
+
+// WithMaxBodySize 限制可缓存响应体的大小，默认 10 MiB，超出时响应照常返回但不缓存。
+func WithMaxBodySize(n int64) OptionFunc {
+	return func(t *transport) {
+		t.maxBodySize = n
+	}
+}
+
+// New 创建使用 store 的缓存 HttpTransport。
+func New(store Store, opts ...OptionFunc) client.HttpTransport {
+	shared := newTransport(store, opts...)
+
+	return func(rt http.RoundTripper) http.RoundTripper {
+		t := *shared
+		t.next = rt
+		return &t
+	}
+}
+
+func newTransport(store Store, opts ...OptionFunc) *transport {
+	t := &transport{
+		store:        store,
+		maxBodySize:  10 << 20,
+		now:          time.Now,
+		revalidating: &sync.Map{},
+	}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	return t
+}
+
+type transport struct {
+	next         http.RoundTripper
+	store        Store
+	maxBodySize  int64
+	now          func() time.Time
+	revalidating *sync.Map
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		resp, err := t.next.RoundTrip(req)
+		if err == nil && resp.StatusCode < http.StatusBadRequest {
+			// 非安全方法成功后，同一地址的缓存失效
+			t.store.Delete(ctx, keyOf(http.MethodGet, req))
+			t.store.Delete(ctx, keyOf(http.MethodHead, req))
+		}
+		return resp, err
+	}
+
+	reqCC := ParseCacheControl(req.Header)
+
+	if reqCC.Has("no-store") {
+		return t.next.RoundTrip(req)
+	}
+
+	key := keyOf(req.Method, req)
+
+	entry, ok := t.store.Get(ctx, key)
+	if ok && !varyMatches(entry, req) {
+		entry, ok = nil, false
+	}
+
+	if !ok {
+		if reqCC.Has("only-if-cached") {
+			return &http.Response{
+				Status:     "504 Gateway Timeout",
+				StatusCode: http.StatusGatewayTimeout,
+				Proto:      "HTTP/1.1",
+				ProtoMajor: 1,
+				ProtoMinor: 1,
+				Header:     http.Header{},
+				Body:       http.NoBody,
+				Request:    req,
+			}, nil
+		}
+		return t.fetch(req, key, nil)
+	}
+
+	if !reqCC.Has("no-cache") {
+		now := t.now()
+		age := currentAge(entry, now)
+		lifetime := freshnessLifetime(entry)
+
+		if maxAge, ok := reqCC.Duration("max-age"); ok && maxAge < lifetime {
+			lifetime = maxAge
+		}
+
+		if age < lifetime {
+			return withAge(entry.response(req), age), nil
+		}
+
+		respCC := ParseCacheControl(entry.Header)
+
+		if swr, ok := respCC.Duration("stale-while-revalidate"); ok && !respCC.Has("must-revalidate") && age < lifetime+swr {
+			t.revalidate(req, key, entry)
+			return withAge(entry.response(req), age), nil
+		}
+	}
+
+	return t.fetch(req, key, entry)
+}
+
+// revalidate 在后台重新验证，同一个 key 同时只会有一个请求
+func (t *transport) revalidate(req *http.Request, key string, entry *Entry) {
+	if _, loaded := t.revalidating.LoadOrStore(key, true); loaded {
+		return
+	}
+
+	r := req.Clone(context.WithoutCancel(req.Context()))
+
+	go func() {
+		defer t.revalidating.Delete(key)
+
+		resp, err := t.fetch(r, key, entry)
+		if err == nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+	}()
+}
+
+func (t *transport) fetch(req *http.Request, key string, entry *Entry) (*http.Response, error) {
+	out := req
+
+	if entry != nil {
+		out = req.Clone(req.Context())
+		if etag := entry.Header.Get("ETag"); etag != "" {
+			out.Header.Set("If-None-Match", etag)
+		}
+		if lastModified := entry.Header.Get("Last-Modified"); lastModified != "" {
+			out.Header.Set("If-Modified-Since", lastModified)
+		}
+	}
+
+	requestTime := t.now()
+
+	resp, err := t.next.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+
+	responseTime := t.now()
+
+	if entry != nil && resp.StatusCode == http.StatusNotModified {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+
+		updated := *entry
+		updated.Header = entry.Header.Clone()
+		for k, values := range resp.Header {
+			switch http.CanonicalHeaderKey(k) {
+			case "Content-Length", "Content-Encoding", "Transfer-Encoding":
+				continue
+			}
+			updated.Header[k] = values
+		}
+		updated.RequestTime = requestTime
+		updated.ResponseTime = responseTime
+
+		t.store.Set(req.Context(), key, &updated)
+
+		return withAge(updated.response(req), currentAge(&updated, responseTime)), nil
+	}
+
+	if !cacheable(req, resp) {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, t.maxBodySize+1))
+	if err != nil {
+		_ = resp.Body.Close()
+		return nil, err
+	}
+
+	if int64(len(body)) > t.maxBodySize {
+		resp.Body = &readCloser{Reader: io.MultiReader(bytes.NewReader(body), resp.Body), Closer: resp.Body}
+		return resp, nil
+	}
+
+	_ = resp.Body.Close()
+
+	stored := &Entry{
+		StatusCode:   resp.StatusCode,
+		Header:       resp.Header.Clone(),
+		Body:         body,
+		VaryHeader:   varyHeaderOf(resp.Header, req),
+		RequestTime:  requestTime,
+		ResponseTime: responseTime,
+	}
+
+	t.store.Set(req.Context(), key, stored)
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	return resp, nil
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+func keyOf(method string, req *http.Request) string {
+	return method + " " + req.URL.String()
+}
+
+var cacheableStatusCodes = []int{
+	http.StatusOK,
+	http.StatusNonAuthoritativeInfo,
+	http.StatusNoContent,
+	http.StatusMultipleChoices,
+	http.StatusMovedPermanently,
+	http.StatusPermanentRedirect,
+	http.StatusNotFound,
+	http.StatusMethodNotAllowed,
+	http.StatusGone,
+	http.StatusRequestURITooLong,
+	http.StatusNotImplemented,
+}
+
+func cacheable(req *http.Request, resp *http.Response) bool {
+	if !slices.Contains(cacheableStatusCodes, resp.StatusCode) {
+		return false
+	}
+
+	if ParseCacheControl(req.Header).Has("no-store") {
+		return false
+	}
+
+	cc := ParseCacheControl(resp.Header)
+	if cc.Has("no-store") {
+		return false
+	}
+
+	if slices.Contains(varyFields(resp.Header), "*") {
+		return false
+	}
+
+	// 同一个 Client 可能以不同凭证发起请求，按 RFC 9111 §3.5，
+	// 携带 Authorization 的请求仅在响应显式允许共享时缓存
+	if req.Header.Get("Authorization") != "" && !(cc.Has("public") || cc.Has("s-maxage") || cc.Has("must-revalidate")) {
+		return false
+	}
+
+	return cc.Has("max-age") || cc.Has("public") || cc.Has("no-cache") ||
+		resp.Header.Get("Expires") != "" ||
+		resp.Header.Get("ETag") != "" ||
+		resp.Header.Get("Last-Modified") != ""
+}
+
+func freshnessLifetime(e *Entry) time.Duration {
+	cc := ParseCacheControl(e.Header)
+
+	if cc.Has("no-cache") {
+		return 0
+	}
+
+	if maxAge, ok := cc.Duration("max-age"); ok {
+		return maxAge
+	}
+
+	date := dateOf(e)
+
+	if expires := e.Header.Get("Expires"); expires != "" {
+		t, err := http.ParseTime(expires)
+		if err != nil {
+			return 0
+		}
+		return t.Sub(date)
+	}
+
+	// 启发式新鲜度取 Last-Modified 距今的 10%
+	if lastModified := e.Header.Get("Last-Modified"); lastModified != "" {
+		if t, err := http.ParseTime(lastModified); err == nil && t.Before(date) {
+			return date.Sub(t) / 10
+		}
+	}
+
+	return 0
+}
+
+func currentAge(e *Entry, now time.Time) time.Duration {
+	apparentAge := max(0, e.ResponseTime.Sub(dateOf(e)))
+
+	ageValue := time.Duration(0)
+	if v, err := strconv.ParseInt(e.Header.Get("Age"), 10, 64); err == nil && v > 0 {
+		ageValue = time.Duration(v) * time.Second
+	}
+
+	correctedAgeValue := ageValue + e.ResponseTime.Sub(e.RequestTime)
+
+	return max(apparentAge, correctedAgeValue) + now.Sub(e.ResponseTime)
+}
+
+func dateOf(e *Entry) time.Time {
+	if t, err := http.ParseTime(e.Header.Get("Date")); err == nil {
+		return t
+	}
+	return e.ResponseTime
+}
+
+func withAge(resp *http.Response, age time.Duration) *http.Response {
+	resp.Header.Set("Age", strconv.FormatInt(int64(age/time.Second), 10))
+	return resp
+}
+
+func varyFields(header http.Header) []string {
+	var fields []string
+	for _, v := range header.Values("Vary") {
+		for f := range strings.SplitSeq(v, ",") {
+			if f = strings.TrimSpace(f); f != "" {
+				fields = append(fields, http.CanonicalHeaderKey(f))
+			}
+		}
+	}
+	return fields
+}
+
+func varyHeaderOf(respHeader http.Header, req *http.Request) http.Header {
+	fields := varyFields(respHeader)
+	if len(fields) == 0 {
+		return nil
+	}
+
+	h := http.Header{}
+	for _, f := range fields {
+		h[f] = req.Header.Values(f)
+	}
+	return h
+}
+
+func varyMatches(e *Entry, req *http.Request) bool {
+	for _, f := range varyFields(e.Header) {
+		if strings.Join(e.VaryHeader.Values(f), ",") != strings.Join(req.Header.Values(f), ",") {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,273 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/octohelm/courier/pkg/content"
+	"github.com/octohelm/courier/pkg/courierhttp/tus"
+)
+
+// ResumableUploader 基于 tus 1.0 协议分块上传，请求失败后从服务端已接收的偏移量续传。
+type ResumableUploader struct {
+	Client *Client
+	// 上传集合地址，相对于 Client.Endpoint，如 `/api/uploads`
+	Path string
+	// 单次 PATCH 的最大字节数，默认 8 MiB
+	ChunkSize int64
+	// 连续失败的最大重试次数，默认 5
+	MaxRetries int
+	// 首次重试的等待时间，之后逐次翻倍，默认 1s
+	RetryInterval time.Duration
+}
+
+// Upload 创建上传并写入 r 中 size 字节的数据，返回上传地址；size 小于 0 时读取 r 直至 EOF。
+//
+// 上传进度通过 content.ContextWithUploadProgress 注入的回调上报。
+func (u *ResumableUploader) Upload(ctx context.Context, r io.ReadSeeker, size int64, metadata tus.Metadata) (string, error) {
+	location, err := u.Create(ctx, size, metadata)
+	if err != nil {
+		return "", err
+	}
+
+	if err := u.Resume(ctx, location, r, size); err != nil {
+		return location, err
+	}
+
+	return location, nil
+}
+
+// Create 创建上传，返回上传地址；size 小于 0 时延迟声明长度。
+func (u *ResumableUploader) Create(ctx context.Context, size int64, metadata tus.Metadata) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.Path, nil)
+	if err != nil {
+		return "", err
+	}
+
+	if err := u.Client.completeEndpoint(req.URL); err != nil {
+		return "", err
+	}
+
+	req.Header.Set(tus.HeaderTusResumable, tus.Version)
+
+	if size < 0 {
+		req.Header.Set(tus.HeaderUploadDeferLength, "1")
+	} else {
+		req.Header.Set(tus.HeaderUploadLength, strconv.FormatInt(size, 10))
+	}
+
+	if len(metadata) > 0 {
+		req.Header.Set(tus.HeaderUploadMetadata, metadata.String())
+	}
+
+	meta, err := u.Client.Do(ctx, req).Into(nil)
+	if err != nil {
+		return "", err
+	}
+
+	location, err := req.URL.Parse(meta.Get("Location"))
+	if err != nil {
+		return "", err
+	}
+
+	return location.String(), nil
+}
+
+// Resume 从服务端记录的偏移量继续上传 r 中共 size 字节的数据。
+//
+// size 小于 0 时读取 r 直至 EOF，并在最后一次 PATCH 中声明总长度。
+func (u *ResumableUploader) Resume(ctx context.Context, location string, r io.ReadSeeker, size int64) error {
+	target, err := url.Parse(location)
+	if err != nil {
+		return err
+	}
+
+	if err := u.Client.completeEndpoint(target); err != nil {
+		return err
+	}
+
+	progress, _ := content.UploadProgressFromContext(ctx)
+
+	offset := int64(-1)
+	retries := 0
+
+	for {
+		if offset < 0 {
+			var length int64
+			offset, length, err = u.offset(ctx, target)
+			// 延迟声明的长度可能已在中断前的 PATCH 中声明
+			if err == nil && size < 0 && length >= 0 {
+				size = length
+			}
+		}
+
+		if err == nil {
+			if progress != nil {
+				progress(content.UploadProgress{Written: offset, Total: size})
+			}
+
+			if size >= 0 && offset >= size {
+				return nil
+			}
+
+			var next, length int64
+			next, length, err = u.patch(ctx, target, r, offset, size)
+			if err == nil {
+				offset, size = next, length
+				retries = 0
+				continue
+			}
+		}
+
+		offset = -1
+
+		if !u.retryable(ctx, err) || retries >= u.maxRetries() {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(u.retryInterval() << retries):
+		}
+
+		retries++
+	}
+}
+
+// offset 查询服务端已接收的字节数与已声明的总长度，未声明时长度为 -1
+func (u *ResumableUploader) offset(ctx context.Context, target *url.URL) (int64, int64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, target.String(), nil)
+	if err != nil {
+		return -1, -1, err
+	}
+
+	req.Header.Set(tus.HeaderTusResumable, tus.Version)
+
+	meta, err := u.Client.Do(ctx, req).Into(nil)
+	if err != nil {
+		return -1, -1, err
+	}
+
+	offset, err := parseOffset(meta.Get(tus.HeaderUploadOffset))
+	if err != nil {
+		return -1, -1, err
+	}
+
+	length := int64(-1)
+	if v := meta.Get(tus.HeaderUploadLength); v != "" {
+		length, err = strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return -1, -1, fmt.Errorf("invalid %s %q: %w", tus.HeaderUploadLength, v, err)
+		}
+	}
+
+	return offset, length, nil
+}
+
+// patch 上传一个分块，返回新的偏移量与总长度
+func (u *ResumableUploader) patch(ctx context.Context, target *url.URL, r io.ReadSeeker, offset int64, size int64) (int64, int64, error) {
+	if _, err := r.Seek(offset, io.SeekStart); err != nil {
+		return -1, -1, err
+	}
+
+	var body io.Reader
+	var n int64
+
+	deferred := size < 0
+
+	if !deferred {
+		n = min(u.chunkSize(), size-offset)
+		body = io.LimitReader(r, n)
+	} else {
+		// 多读一个字节以判断是否为最后一个分块
+		buf := make([]byte, u.chunkSize()+1)
+		read, err := io.ReadFull(r, buf)
+		switch {
+		case err == nil:
+			read--
+		case errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF):
+			size = offset + int64(read)
+		default:
+			return -1, -1, err
+		}
+		n = int64(read)
+		body = bytes.NewReader(buf[:read])
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, target.String(), io.NopCloser(body))
+	if err != nil {
+		return -1, -1, err
+	}
+
+	req.ContentLength = n
+	req.Header.Set(tus.HeaderTusResumable, tus.Version)
+	req.Header.Set(tus.HeaderUploadOffset, strconv.FormatInt(offset, 10))
+	req.Header.Set("Content-Type", tus.ContentTypeOffsetOctetStream)
+
+	if deferred && offset+n == size {
+		req.Header.Set(tus.HeaderUploadLength, strconv.FormatInt(size, 10))
+	}
+
+	meta, err := u.Client.Do(ctx, req).Into(nil)
+	if err != nil {
+		return -1, -1, err
+	}
+
+	offset, err = parseOffset(meta.Get(tus.HeaderUploadOffset))
+	return offset, size, err
+}
+
+func (u *ResumableUploader) retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	var statusErr interface{ StatusCode() int }
+	if errors.As(err, &statusErr) {
+		switch code := statusErr.StatusCode(); code {
+		case http.StatusRequestTimeout, http.StatusConflict, http.StatusLocked, http.StatusTooManyRequests:
+			return true
+		default:
+			return code >= http.StatusInternalServerError
+		}
+	}
+
+	return true
+}
+
+func (u *ResumableUploader) chunkSize() int64 {
+	if u.ChunkSize > 0 {
+		return u.ChunkSize
+	}
+	return 8 << 20
+}
+
+func (u *ResumableUploader) maxRetries() int {
+	if u.MaxRetries > 0 {
+		return u.MaxRetries
+	}
+	return 5
+}
+
+func (u *ResumableUploader) retryInterval() time.Duration {
+	if u.RetryInterval > 0 {
+		return u.RetryInterval
+	}
+	return time.Second
+}
+
+func parseOffset(s string) (int64, error) {
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return -1, fmt.Errorf("invalid %s %q: %w", tus.HeaderUploadOffset, s, err)
+	}
+	return n, nil
+}
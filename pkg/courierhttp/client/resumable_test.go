@@ -0,0 +1,139 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/octohelm/x/testing/v2"
+
+	"github.com/octohelm/courier/pkg/content"
+	"github.com/octohelm/courier/pkg/courierhttp"
+	"github.com/octohelm/courier/pkg/courierhttp/handler/httprouter"
+	"github.com/octohelm/courier/pkg/courierhttp/tus"
+)
+
+func TestResumableUploader(t *testing.T) {
+	store := tus.NewFSStore(t.TempDir())
+
+	h, err := httprouter.New(courierhttp.GroupRouter("/").With(
+		tus.NewRouter("/uploads", store),
+	), "test")
+	Then(t, "构建 httprouter handler 成功", Expect(err, Equal[error](nil)))
+
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+
+	patches := atomic.Int32{}
+
+	uploader := &ResumableUploader{
+		Client: &Client{
+			Endpoint: srv.URL,
+			HttpTransports: []HttpTransport{
+				HttpTransportFunc(func(req *http.Request, next RoundTrip) (*http.Response, error) {
+					// 模拟第二个分块发送时网络中断
+					if req.Method == http.MethodPatch && patches.Add(1) == 2 {
+						return nil, errors.New("connection reset")
+					}
+					return next(req)
+				}),
+			},
+		},
+		Path:          "/uploads",
+		ChunkSize:     4,
+		RetryInterval: time.Millisecond,
+	}
+
+	data := "0123456789abc"
+	written := int64(0)
+
+	ctx := content.ContextWithUploadProgress(context.Background(), func(p content.UploadProgress) {
+		written = p.Written
+	})
+
+	location, err := uploader.Upload(ctx, strings.NewReader(data), int64(len(data)), tus.Metadata{"filename": "a.txt"})
+
+	Then(t, "网络中断后续传完成",
+		Expect(err, Equal[error](nil)),
+		Expect(strings.HasPrefix(location, srv.URL+"/uploads/"), Equal(true)),
+		Expect(written, Equal(int64(len(data)))),
+		Expect(patches.Load(), Equal(int32(5))),
+	)
+
+	Then(t, "服务端收到完整数据",
+		ExpectMust(func() error {
+			f, err := store.Open(ctx, location[strings.LastIndex(location, "/")+1:])
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+
+			received, err := io.ReadAll(f)
+			if err != nil {
+				return err
+			}
+			if string(received) != data {
+				return errors.New("unexpected upload data")
+			}
+			return nil
+		}),
+	)
+
+	Then(t, "上传不存在时不重试",
+		Expect(uploader.Resume(ctx, srv.URL+"/uploads/00000000000000000000000000000000", strings.NewReader(data), int64(len(data))) != nil, Equal(true)),
+	)
+
+	t.Run("延迟声明长度", func(t *testing.T) {
+		upload := func(data string) (string, error) {
+			location, err := uploader.Upload(ctx, strings.NewReader(data), -1, nil)
+			if err != nil {
+				return "", err
+			}
+
+			id := location[strings.LastIndex(location, "/")+1:]
+
+			u, err := store.Get(ctx, id)
+			if err != nil {
+				return "", err
+			}
+			if u.Length != int64(len(data)) || u.Offset != u.Length {
+				return "", errors.New("upload length is not declared")
+			}
+
+			f, err := store.Open(ctx, id)
+			if err != nil {
+				return "", err
+			}
+			defer f.Close()
+
+			received, err := io.ReadAll(f)
+			return string(received), err
+		}
+
+		Then(t, "读取至 EOF 并在最后一个分块声明长度",
+			ExpectMust(func() error {
+				received, err := upload(data)
+				if err == nil && received != data {
+					return errors.New("unexpected upload data")
+				}
+				return err
+			}),
+		)
+
+		Then(t, "长度恰为分块整数倍时同样完成",
+			ExpectMust(func() error {
+				received, err := upload("01234567")
+				if err == nil && received != "01234567" {
+					return errors.New("unexpected upload data")
+				}
+				return err
+			}),
+		)
+	})
+}
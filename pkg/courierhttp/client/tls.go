@@ -0,0 +1,125 @@
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// TLS 为客户端 TLS 配置，未设置的字段沿用 SetDefaultTLSClientConfig 的配置。
+type TLS struct {
+	// CA 证书文件（PEM），用于校验服务端证书
+	CAFile string `flag:",omitzero"`
+	// 客户端证书文件（PEM），文件变化后自动重新加载
+	CertFile string `flag:",omitzero"`
+	// 客户端私钥文件（PEM）
+	KeyFile string `flag:",omitzero"`
+	// 校验服务端证书时使用的服务名
+	ServerName         string `flag:",omitzero"`
+	InsecureSkipVerify bool   `flag:",omitzero"`
+}
+
+func (x TLS) IsZero() bool {
+	return x == TLS{}
+}
+
+// Config 构造 *tls.Config。
+func (x TLS) Config() (*tls.Config, error) {
+	cfg := &tls.Config{}
+	if defaultTlsConfig != nil {
+		cfg = defaultTlsConfig.Clone()
+	}
+
+	if x.CAFile != "" {
+		data, err := os.ReadFile(x.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read ca file failed: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no certificates found in %s", x.CAFile)
+		}
+		cfg.RootCAs = pool
+	}
+
+	if x.ServerName != "" {
+		cfg.ServerName = x.ServerName
+	}
+
+	if x.InsecureSkipVerify {
+		cfg.InsecureSkipVerify = true
+	}
+
+	if x.CertFile != "" || x.KeyFile != "" {
+		if x.CertFile == "" || x.KeyFile == "" {
+			return nil, errors.New("both cert file and key file are required")
+		}
+
+		r := &certReloader{certFile: x.CertFile, keyFile: x.KeyFile}
+		if _, err := r.load(); err != nil {
+			return nil, err
+		}
+		cfg.GetClientCertificate = r.GetClientCertificate
+	}
+
+	return cfg, nil
+}
+
+type certReloader struct {
+	certFile string
+	keyFile  string
+
+	mu      sync.Mutex
+	cert    *tls.Certificate
+	modTime time.Time
+}
+
+func (r *certReloader) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	cert, err := r.load()
+	if err != nil {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		if r.cert != nil {
+			// 证书更新过程中可能读到不完整的文件，沿用上次的证书
+			return r.cert, nil
+		}
+		return nil, err
+	}
+	return cert, nil
+}
+
+func (r *certReloader) load() (*tls.Certificate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	modTime := time.Time{}
+	for _, f := range []string{r.certFile, r.keyFile} {
+		info, err := os.Stat(f)
+		if err != nil {
+			return nil, fmt.Errorf("stat %s failed: %w", f, err)
+		}
+		if info.ModTime().After(modTime) {
+			modTime = info.ModTime()
+		}
+	}
+
+	if r.cert != nil && modTime.Equal(r.modTime) {
+		return r.cert, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("load client certificate failed: %w", err)
+	}
+
+	r.cert = &cert
+	r.modTime = modTime
+
+	return r.cert, nil
+}
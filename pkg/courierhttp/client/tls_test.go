@@ -0,0 +1,137 @@
+package client
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/octohelm/x/testing/v2"
+)
+
+func writeClientCert(t *testing.T, dir string, cn string, modTime time.Time) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tpl, tpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certFile := filepath.Join(dir, "client.crt")
+	keyFile := filepath.Join(dir, "client.key")
+
+	_ = os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600)
+	_ = os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0o600)
+	_ = os.Chtimes(certFile, modTime, modTime)
+	_ = os.Chtimes(keyFile, modTime, modTime)
+
+	return certFile, keyFile
+}
+
+func TestClientTransportSettings(t *testing.T) {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("X-Client", r.TLS.PeerCertificates[0].Subject.CommonName)
+		rw.WriteHeader(http.StatusNoContent)
+	}))
+	srv.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+
+	dir := t.TempDir()
+
+	caFile := filepath.Join(dir, "ca.crt")
+	_ = os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}), 0o600)
+
+	now := time.Now()
+	certFile, keyFile := writeClientCert(t, dir, "client-v1", now)
+
+	_, port, _ := net.SplitHostPort(srv.Listener.Addr().String())
+
+	c := &Client{
+		HostAliases: []HostAlias{
+			{IP: net.ParseIP("127.0.0.1"), Hostnames: []string{"api.internal"}},
+		},
+		TLS: TLS{
+			CAFile:     caFile,
+			CertFile:   certFile,
+			KeyFile:    keyFile,
+			ServerName: "example.com",
+		},
+		DisableKeepAlives: true,
+	}
+
+	call := func() (string, error) {
+		req, _ := http.NewRequest(http.MethodGet, "https://api.internal:"+port+"/", nil)
+		meta, err := c.Do(context.Background(), req).Into(nil)
+		if err != nil {
+			return "", err
+		}
+		return meta.Get("X-Client"), nil
+	}
+
+	first, err := call()
+
+	Then(t, "使用客户端自身的 host alias、CA 与客户端证书",
+		Expect(err, Equal[error](nil)),
+		Expect(first, Equal("client-v1")),
+	)
+
+	writeClientCert(t, dir, "client-v2", now.Add(time.Second))
+
+	second, err := call()
+
+	Then(t, "客户端证书更新后自动重新加载",
+		Expect(err, Equal[error](nil)),
+		Expect(second, Equal("client-v2")),
+	)
+
+	Then(t, "未配置的客户端不受影响",
+		ExpectMust(func() error {
+			req, _ := http.NewRequest(http.MethodGet, "https://api.internal:"+port+"/", nil)
+			_, err := (&Client{DialTimeout: time.Second}).Do(context.Background(), req).Into(nil)
+			if err == nil {
+				return errClient("expected dial or tls error")
+			}
+			return nil
+		}),
+	)
+
+	Then(t, "无效配置返回错误",
+		ExpectMust(func() error {
+			req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+			_, err := (&Client{TLS: TLS{CertFile: certFile}}).Do(context.Background(), req).Into(nil)
+			if err == nil {
+				return errClient("expected invalid transport error")
+			}
+			return nil
+		}),
+	)
+}
@@ -11,11 +11,14 @@ import (
 	"net/url"
 	"reflect"
 	"testing"
+	"time"
 
 	. "github.com/octohelm/x/testing/v2"
 
 	"github.com/octohelm/courier/internal/httprequest"
 	"github.com/octohelm/courier/pkg/courier"
+	"github.com/octohelm/courier/pkg/i18n"
+	"github.com/octohelm/courier/pkg/statuserror"
 )
 
 type routeStub struct{}
@@ -307,3 +310,190 @@ func TestWrapErrorAndErrorType(t0 *testing.T) {
 		}),
 	)
 }
+
+func TestAcceptProblemDetails(t *testing.T) {
+	for accept, accepted := range map[string]bool{
+		"application/problem+json":                         true,
+		"application/problem+json;q=0.01":                  true,
+		"application/problem+json;q=0.05":                  true,
+		"application/problem+json;q=1":                     true,
+		"application/problem+json;q=0":                     false,
+		"application/problem+json;q=0.000":                 false,
+		"application/problem+json;q=bad":                   false,
+		"application/json, text/plain;q=.5":                false,
+		"application/json, application/problem+json;q=0.1": false,
+		"application/json;q=0.5, application/problem+json": true,
+		"application/problem+json, application/json":       true,
+		"application/json, application/problem+json":       true,
+		"*/*, application/problem+json":                    true,
+		"*/*, application/problem+json;q=0.5":              false,
+	} {
+		Then(t, accept, Expect(acceptProblemDetails([]string{accept}), Equal(accepted)))
+	}
+}
+
+func TestErrorFormat(t0 *testing.T) {
+	conflict := func() error {
+		return WrapError(statuserror.Wrap(errors.New("conflict"), http.StatusConflict, "CONFLICT"))
+	}
+
+	Then(
+		t0, "错误响应可按 Accept 或路由声明渲染为 problem details",
+		ExpectMust(func() error {
+			req, _ := http.NewRequest(http.MethodPost, "http://example.com/orgs", nil)
+			req.Header.Set("Accept", "application/json, application/problem+json")
+			rec := httptest.NewRecorder()
+
+			if err := conflict().(ResponseWriter).WriteResponse(context.Background(), rec, httprequest.From(req)); err != nil {
+				return err
+			}
+			if rec.Code != http.StatusConflict {
+				return fmt.Errorf("unexpected status %d", rec.Code)
+			}
+			if rec.Header().Get("Content-Type") != statuserror.MediaTypeProblemJSON {
+				return fmt.Errorf("unexpected content-type %s", rec.Header().Get("Content-Type"))
+			}
+			if !bytes.Contains(rec.Body.Bytes(), []byte(`"instance":"/orgs"`)) {
+				return fmt.Errorf("unexpected body %s", rec.Body.String())
+			}
+			return nil
+		}),
+		ExpectMust(func() error {
+			req, _ := http.NewRequest(http.MethodPost, "http://example.com/orgs", nil)
+			req.Header.Set("Accept", "application/problem+json;q=0")
+			rec := httptest.NewRecorder()
+
+			if err := conflict().(ResponseWriter).WriteResponse(context.Background(), rec, httprequest.From(req)); err != nil {
+				return err
+			}
+			if rec.Header().Get("Content-Type") == statuserror.MediaTypeProblemJSON {
+				return errors.New("problem details should not be negotiated with q=0")
+			}
+			return nil
+		}),
+		ExpectMust(func() error {
+			req, _ := http.NewRequest(http.MethodPost, "http://example.com/orgs", nil)
+			ctx := ContextWithErrorFormat(context.Background(), ErrorFormatProblemDetails)
+			rec := httptest.NewRecorder()
+
+			if err := conflict().(ResponseWriter).WriteResponse(ctx, rec, httprequest.From(req)); err != nil {
+				return err
+			}
+			if rec.Header().Get("Content-Type") != statuserror.MediaTypeProblemJSON {
+				return fmt.Errorf("unexpected content-type %s", rec.Header().Get("Content-Type"))
+			}
+			return nil
+		}),
+		Expect(UseErrorFormat(ErrorFormatProblemDetails).(ErrorFormatDescriber).ErrorFormat(), Equal(ErrorFormatProblemDetails)),
+		Expect(ErrorFormatFromContext(context.Background()), Equal(ErrorFormatDefault)),
+	)
+}
+
+func TestLocalizedError(t0 *testing.T) {
+	c := i18n.NewCatalog("en")
+	c.Add("en", i18n.Messages{"CONFLICT": "conflict"})
+	c.Add("zh", i18n.Messages{"CONFLICT": "冲突"})
+
+	ctx := i18n.CatalogInjectContext(context.Background(), c)
+
+	write := func(acceptLanguage string) (string, error) {
+		req, _ := http.NewRequest(http.MethodPost, "http://example.com/orgs", nil)
+		req.Header.Set("Accept-Language", acceptLanguage)
+		rec := httptest.NewRecorder()
+
+		err := WrapError(statuserror.Wrap(errors.New("boom"), http.StatusConflict, "CONFLICT")).(ResponseWriter).WriteResponse(ctx, rec, httprequest.From(req))
+		return rec.Body.String(), err
+	}
+
+	Then(
+		t0, "错误信息按 Accept-Language 本地化",
+		ExpectMust(func() error {
+			body, err := write("zh-CN,zh;q=0.9,en;q=0.8")
+			if err != nil {
+				return err
+			}
+			if !bytes.Contains([]byte(body), []byte(`"msg":"冲突"`)) {
+				return fmt.Errorf("unexpected body %s", body)
+			}
+			return nil
+		}),
+		ExpectMust(func() error {
+			body, err := write("fr")
+			if err != nil {
+				return err
+			}
+			if !bytes.Contains([]byte(body), []byte(`"msg":"conflict"`)) {
+				return fmt.Errorf("unexpected body %s", body)
+			}
+			return nil
+		}),
+	)
+}
+
+type deprecatedUsageRecorderStub struct {
+	infos []OperationInfo
+}
+
+func (r *deprecatedUsageRecorderStub) RecordDeprecatedUsage(ctx context.Context, info OperationInfo, d Deprecation) {
+	r.infos = append(r.infos, info)
+}
+
+func TestDeprecation(t0 *testing.T) {
+	d := Deprecation{
+		At:        time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		Sunset:    time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC),
+		Successor: "/api/v2/orgs",
+	}
+
+	header := http.Header{}
+	d.WriteHeader(header)
+
+	parsed, ok := ParseDeprecation(header)
+
+	recorder := &deprecatedUsageRecorderStub{}
+	ctx := OperationInfoInjectContext(ContextWithDeprecatedUsageRecorder(context.Background(), recorder), &OperationInfo{ID: "ListOrg"})
+	RecordDeprecatedUsage(ctx, d)
+
+	Then(
+		t0, "弃用信息可写入并解析响应头",
+		Expect(header.Get("Deprecation"), Equal("@1767225600")),
+		Expect(header.Get("Sunset"), Equal("Fri, 01 Jan 2027 00:00:00 GMT")),
+		Expect(header.Get("Link"), Equal(`</api/v2/orgs>; rel="successor-version"`)),
+		Expect(ok, Equal(true)),
+		Expect(parsed, Equal(d)),
+		Expect(recorder.infos, Equal([]OperationInfo{{ID: "ListOrg"}})),
+	)
+
+	Then(
+		t0, "未指定弃用时间时输出 true",
+		ExpectMust(func() error {
+			h := http.Header{}
+			Deprecation{}.WriteHeader(h)
+			if h.Get("Deprecation") != "true" {
+				return fmt.Errorf("unexpected deprecation header %q", h.Get("Deprecation"))
+			}
+			if _, ok := ParseDeprecation(http.Header{}); ok {
+				return errors.New("should not parse deprecation without header")
+			}
+			return nil
+		}),
+	)
+}
+
+func TestReadBody(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString("0123456789"))
+	body, err := ReadBody(httptest.NewRecorder(), req, 16)
+	restored, _ := io.ReadAll(req.Body)
+
+	Then(t, "未超出上限时返回请求体并还原",
+		Expect(err, Equal[error](nil)),
+		Expect(string(body), Equal("0123456789")),
+		Expect(string(restored), Equal("0123456789")),
+	)
+
+	_, err = ReadBody(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString("0123456789")), 8)
+
+	Then(t, "超出上限时返回 ErrRequestBodyTooLarge",
+		Expect(errors.As(err, new(*ErrRequestBodyTooLarge)), Equal(true)),
+	)
+}
@@ -0,0 +1,115 @@
+package courierhttp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/octohelm/x/logr"
+)
+
+// RelSuccessorVersion 为 `Link` 头中指向后继版本的关系类型。
+const RelSuccessorVersion = "successor-version"
+
+// Deprecation 描述弃用信息，对应 RFC 9745 `Deprecation` 与 RFC 8594 `Sunset` 响应头。
+type Deprecation struct {
+	// 弃用时间，非零表示已弃用
+	At time.Time
+	// 计划下线时间
+	Sunset time.Time
+	// 后继版本地址，输出为 `Link: <successor>; rel="successor-version"`
+	Successor string
+}
+
+func (d Deprecation) IsZero() bool {
+	return d.At.IsZero() && d.Sunset.IsZero() && d.Successor == ""
+}
+
+// WriteHeader 将弃用信息写入响应头；未指定弃用时间时 `Deprecation` 输出为 `true`。
+func (d Deprecation) WriteHeader(header http.Header) {
+	if !d.At.IsZero() {
+		header.Set("Deprecation", "@"+strconv.FormatInt(d.At.Unix(), 10))
+	} else {
+		header.Set("Deprecation", "true")
+	}
+	if !d.Sunset.IsZero() {
+		header.Set("Sunset", d.Sunset.UTC().Format(http.TimeFormat))
+	}
+	if d.Successor != "" {
+		header.Add("Link", Link{Target: d.Successor, Rel: RelSuccessorVersion}.String())
+	}
+}
+
+// ParseDeprecation 从响应头中解析弃用信息，未声明 `Deprecation` 时返回 false。
+func ParseDeprecation(header http.Header) (Deprecation, bool) {
+	d := Deprecation{}
+
+	v := strings.TrimSpace(header.Get("Deprecation"))
+	if v == "" {
+		return d, false
+	}
+
+	if unix, ok := strings.CutPrefix(v, "@"); ok {
+		if i, err := strconv.ParseInt(unix, 10, 64); err == nil {
+			d.At = time.Unix(i, 0).UTC()
+		}
+	}
+
+	if sunset := header.Get("Sunset"); sunset != "" {
+		if t, err := http.ParseTime(sunset); err == nil {
+			d.Sunset = t
+		}
+	}
+
+	for _, l := range ParseLinks(header.Values("Link")...) {
+		if l.Rel == RelSuccessorVersion {
+			d.Successor = l.Target
+			break
+		}
+	}
+
+	return d, true
+}
+
+// DeprecationDescriber 用于描述操作的弃用信息，可由 operatorgen 根据文档标记生成。
+type DeprecationDescriber interface {
+	Deprecation() Deprecation
+}
+
+// DeprecatedUsageRecorder 记录已弃用操作的调用，可对接指标统计。
+type DeprecatedUsageRecorder interface {
+	RecordDeprecatedUsage(ctx context.Context, info OperationInfo, d Deprecation)
+}
+
+type contextDeprecatedUsageRecorder struct{}
+
+// ContextWithDeprecatedUsageRecorder 注入已弃用操作调用的记录器。
+func ContextWithDeprecatedUsageRecorder(ctx context.Context, r DeprecatedUsageRecorder) context.Context {
+	return context.WithValue(ctx, contextDeprecatedUsageRecorder{}, r)
+}
+
+// DeprecatedUsageRecorderFromContext 获取已弃用操作调用的记录器。
+func DeprecatedUsageRecorderFromContext(ctx context.Context) (DeprecatedUsageRecorder, bool) {
+	r, ok := ctx.Value(contextDeprecatedUsageRecorder{}).(DeprecatedUsageRecorder)
+	return r, ok
+}
+
+// RecordDeprecatedUsage 记录一次已弃用操作的调用，未注入记录器时输出告警日志。
+func RecordDeprecatedUsage(ctx context.Context, d Deprecation) {
+	info := OperationInfo{}
+	if opInfo, ok := OperationInfoFromContext(ctx); ok {
+		info = *opInfo
+	}
+
+	if r, ok := DeprecatedUsageRecorderFromContext(ctx); ok {
+		r.RecordDeprecatedUsage(ctx, info, d)
+		return
+	}
+
+	logr.FromContext(ctx).WithValues("operation", info.ID, "route", info.Route).Warn(
+		fmt.Errorf("deprecated operation %s called", info.ID),
+	)
+}
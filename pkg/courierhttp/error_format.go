@@ -0,0 +1,91 @@
+package courierhttp
+
+import (
+	"context"
+	"mime"
+	"strconv"
+	"strings"
+
+	"github.com/octohelm/courier/pkg/courier"
+	"github.com/octohelm/courier/pkg/statuserror"
+)
+
+// ErrorFormat 表示错误响应的渲染格式。
+type ErrorFormat string
+
+const (
+	// ErrorFormatDefault 渲染为 `statuserror.ErrorResponse`。
+	ErrorFormatDefault ErrorFormat = ""
+	// ErrorFormatProblemDetails 渲染为 RFC 9457 `application/problem+json`。
+	ErrorFormatProblemDetails ErrorFormat = "problem"
+)
+
+// ErrorFormatDescriber 用于描述路由的错误响应格式。
+type ErrorFormatDescriber interface {
+	ErrorFormat() ErrorFormat
+}
+
+// UseErrorFormat 创建声明错误响应格式的操作符，作用于其所在路由及子路由。
+//
+// 未声明时，请求 `Accept` 对 `application/problem+json` 的偏好不低于 `application/json` 时也会启用 problem details。
+func UseErrorFormat(format ErrorFormat) courier.Operator {
+	return &metaOperator{errorFormat: format}
+}
+
+type contextErrorFormat struct{}
+
+// ContextWithErrorFormat 将错误响应格式存储到上下文中。
+func ContextWithErrorFormat(ctx context.Context, format ErrorFormat) context.Context {
+	return context.WithValue(ctx, contextErrorFormat{}, format)
+}
+
+// ErrorFormatFromContext 从上下文中获取错误响应格式。
+func ErrorFormatFromContext(ctx context.Context) ErrorFormat {
+	if f, ok := ctx.Value(contextErrorFormat{}).(ErrorFormat); ok {
+		return f
+	}
+	return ErrorFormatDefault
+}
+
+func resolveErrorFormat(ctx context.Context, req RequestInfo) ErrorFormat {
+	if f := ErrorFormatFromContext(ctx); f != ErrorFormatDefault {
+		return f
+	}
+	if acceptProblemDetails(req.Header().Values("Accept")) {
+		return ErrorFormatProblemDetails
+	}
+	return ErrorFormatDefault
+}
+
+// acceptProblemDetails 判断 Accept 是否优先接受 problem details。
+//
+// problem+json 的 q 须大于 0，且不低于 application/json 与通配类型的 q，即严格优先或并列最优。
+func acceptProblemDetails(accepts []string) bool {
+	problemQ, otherQ := -1.0, -1.0
+
+	for _, accept := range accepts {
+		for mediaRange := range strings.SplitSeq(accept, ",") {
+			mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(mediaRange))
+			if err != nil {
+				continue
+			}
+
+			q := 1.0
+			if v, ok := params["q"]; ok {
+				if q, err = strconv.ParseFloat(v, 64); err != nil {
+					continue
+				}
+			}
+
+			switch mediaType {
+			case statuserror.MediaTypeProblemJSON:
+				problemQ = max(problemQ, q)
+			case "application/json", "application/*", "*/*":
+				otherQ = max(otherQ, q)
+			}
+		}
+	}
+
+	// q 为 0 表示不可接受
+	return problemQ > 0 && problemQ >= otherQ
+}
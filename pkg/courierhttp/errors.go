@@ -2,6 +2,8 @@ package courierhttp
 
 import (
 	"fmt"
+
+	"github.com/octohelm/courier/pkg/statuserror"
 )
 
 // ErrContextCanceled 表示上下文取消错误。
@@ -17,3 +19,14 @@ func (e *ErrContextCanceled) StatusCode() int {
 func (e *ErrContextCanceled) Error() string {
 	return fmt.Sprintf("context canceled: %s", e.Reason)
 }
+
+// ErrRequestBodyTooLarge 表示请求体超出 ReadBody 的上限。
+type ErrRequestBodyTooLarge struct {
+	statuserror.RequestEntityTooLarge
+
+	MaxSize int64
+}
+
+func (e *ErrRequestBodyTooLarge) Error() string {
+	return fmt.Sprintf("request body exceeds the limit of %d bytes", e.MaxSize)
+}
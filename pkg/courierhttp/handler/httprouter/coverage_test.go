@@ -46,6 +46,9 @@ func (h stubRouteHandler) PathSegments() request.Segments        { return h.path
 func (h stubRouteHandler) Summary() string                       { return "stub summary" }
 func (h stubRouteHandler) Description() string                   { return h.description }
 func (h stubRouteHandler) Deprecated() bool                      { return false }
+func (h stubRouteHandler) ErrorFormat() courierhttp.ErrorFormat  { return "" }
+func (h stubRouteHandler) Version() courierhttp.VersionInfo      { return courierhttp.VersionInfo{} }
+func (h stubRouteHandler) Versioning() courierhttp.Versioning    { return courierhttp.Versioning{} }
 func (h stubRouteHandler) Operators() []*courier.OperatorFactory { return h.operators }
 
 type stubOp struct {
@@ -58,6 +58,7 @@ func (m *mux) buildHandler(w io.Writer) (http.Handler, error) {
 	}()
 
 	m.w = tw
+	m.operations.routes = nil
 
 	return m.routeGroup().handler(m), nil
 }
@@ -84,6 +85,21 @@ func (m *mux) addHandler(r *http.ServeMux, hh RouteHandler, contextInjects ...co
 		return
 	}
 
+	pattern := method + " " + toHttpRouterPathPrefix(hh.PathSegments())
+
+	if vh, ok := hh.(*versionedRouteHandler); ok {
+		r.Handle(pattern, vh.dispatch(func(h RouteHandler) http.Handler {
+			return m.routeHandler(h, contextInjects...)
+		}))
+		return
+	}
+
+	r.Handle(pattern, m.routeHandler(hh, contextInjects...))
+}
+
+func (m *mux) routeHandler(hh RouteHandler, contextInjects ...contextInject) http.Handler {
+	method := hh.Method()
+
 	info := &courierhttp.OperationInfo{
 		Server: m.server,
 		Route:  hh.Path(),
@@ -103,27 +119,43 @@ func (m *mux) addHandler(r *http.ServeMux, hh RouteHandler, contextInjects ...co
 		return courierhttp.OperationInfoProviderInjectContext(ctx, m.operations)
 	})
 
-	pathSegments := hh.PathSegments()
-
 	colorFmt := colorFmtForMethod(method)
 
 	_, _ = colorFmt.Fprint(m.w, "%s", method)
-	_, _ = colorFmt.Fprint(m.w, "\t%s", pathSegments)
+	_, _ = colorFmt.Fprint(m.w, "\t%s", hh.PathSegments())
+	if v := hh.Version(); v.Version != "" {
+		_, _ = colorFmt.Fprint(m.w, " @v%s", v.Version)
+		if hh.Deprecated() {
+			_, _ = colorFmt.Fprint(m.w, " (deprecated)")
+		}
+	}
 	_, _ = fmt.Fprintf(m.w, "\t%s", hh.Summary())
 
 	p := colorFormatter(ansiterm.Gray)
+	route := RouteInfo{
+		Method:      method,
+		Path:        hh.Path(),
+		OperationID: hh.OperationID(),
+		Summary:     hh.Summary(),
+		Version:     hh.Version().Version,
+		Deprecated:  hh.Deprecated(),
+	}
+
 	_, _ = p.Fprint(m.w, "\t{{ ")
 	for i, o := range hh.Operators() {
 		if i > 0 {
 			_, _ = p.Fprint(m.w, " | ")
 		}
 		_, _ = p.Fprint(m.w, "%s", o.String())
+		route.Operators = append(route.Operators, o.String())
 	}
 	_, _ = p.Fprint(m.w, " }}\n")
 
+	m.operations.routes = append(m.operations.routes, route)
+
 	serverInfo := info.UserAgent()
 
-	r.HandleFunc(method+" "+toHttpRouterPathPrefix(pathSegments), func(rw http.ResponseWriter, req *http.Request) {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
 		ctx := req.Context()
 		for _, inject := range ctxInjects {
 			ctx = inject(ctx)
@@ -132,8 +164,6 @@ func (m *mux) addHandler(r *http.ServeMux, hh RouteHandler, contextInjects ...co
 		rw.Header().Set("Server", serverInfo)
 		hh.ServeHTTP(rw, req.WithContext(ctx))
 	})
-
-	return
 }
 
 func colorFmtForMethod(method string) colorFormatter {
@@ -23,8 +23,21 @@ func (e *ErrOpenAPIForbidden) Error() string {
 	return fmt.Sprintf("openapi is forbidden")
 }
 
+type ErrOpenAPIVersionNotFound struct {
+	statuserror.NotFound
+
+	Version string
+}
+
+func (e *ErrOpenAPIVersionNotFound) Error() string {
+	return fmt.Sprintf("openapi of version %q not found", e.Version)
+}
+
 type OpenAPI struct {
 	courierhttp.MethodGet
+
+	// 指定 API 版本，为空时返回包含全部版本的文档
+	Version string `name:"version,omitzero" in:"query"`
 }
 
 func (o *OpenAPI) Output(ctx context.Context) (any, error) {
@@ -32,6 +45,22 @@ func (o *OpenAPI) Output(ctx context.Context) (any, error) {
 		return nil, &ErrOpenAPIForbidden{}
 	}
 
+	if o.Version != "" {
+		if x, ok := courierhttp.OperationInfoProviderFromContext(ctx); ok {
+			if p, ok := x.(interface {
+				OpenAPIOfVersion(version string) (*openapi.OpenAPI, bool)
+			}); ok {
+				if oas, ok := p.OpenAPIOfVersion(o.Version); ok {
+					return &openapi.Payload{
+						OpenAPI: *oas,
+					}, nil
+				}
+			}
+		}
+
+		return nil, &ErrOpenAPIVersionNotFound{Version: o.Version}
+	}
+
 	if x, ok := courierhttp.OperationInfoProviderFromContext(ctx); ok {
 		if o, ok := x.(interface{ OpenAPI() *openapi.OpenAPI }); ok {
 			return &openapi.Payload{
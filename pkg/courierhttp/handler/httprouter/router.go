@@ -58,7 +58,7 @@ func newMux(cr courier.Router, service string, routeMiddlewares ...handler.Middl
 		handlers = append(handlers, rh...)
 	}
 
-	sort.Slice(handlers, func(i, j int) bool {
+	sort.SliceStable(handlers, func(i, j int) bool {
 		return handlers[i].Path() < handlers[j].Path()
 	})
 
@@ -68,6 +68,25 @@ func newMux(cr courier.Router, service string, routeMiddlewares ...handler.Middl
 		},
 	}
 
+	for _, h := range handlers {
+		if v := h.Version().Version; v != "" {
+			if _, ok := m.operations.versions[v]; ok {
+				continue
+			}
+
+			versioned := openapi.FromRouter(cr, openapi.WithVersion(v))
+			versioned.Title = service
+			versioned.Version = v
+
+			if m.operations.versions == nil {
+				m.operations.versions = map[string]*openapispec.OpenAPI{}
+			}
+			m.operations.versions[v] = versioned
+		}
+	}
+
+	handlers = mergeVersionedRouteHandlers(handlers)
+
 	nameVersion := strings.Split(service, "@")
 
 	m.server.Name = nameVersion[0]
@@ -115,8 +134,21 @@ var methodOverride = func(n http.Handler) http.Handler {
 	})
 }
 
+// RouteInfo 描述一条最终暴露的 HTTP 路由，与 RouteSnapshot 中的每一行对应。
+type RouteInfo struct {
+	Method      string   `json:"method"`
+	Path        string   `json:"path"`
+	OperationID string   `json:"operationID"`
+	Summary     string   `json:"summary,omitzero"`
+	Version     string   `json:"version,omitzero"`
+	Deprecated  bool     `json:"deprecated,omitzero"`
+	Operators   []string `json:"operators"`
+}
+
 type operations struct {
-	oas *openapispec.OpenAPI
+	oas      *openapispec.OpenAPI
+	versions map[string]*openapispec.OpenAPI
+	routes   []RouteInfo
 
 	infos map[string]courierhttp.OperationInfo
 }
@@ -133,6 +165,17 @@ func (o *operations) OpenAPI() *openapispec.OpenAPI {
 	return o.oas
 }
 
+// OpenAPIOfVersion 返回指定 API 版本的 OpenAPI 文档。
+func (o *operations) OpenAPIOfVersion(version string) (*openapispec.OpenAPI, bool) {
+	oas, ok := o.versions[version]
+	return oas, ok
+}
+
+// Routes 返回已注册的路由表。
+func (o *operations) Routes() []RouteInfo {
+	return o.routes
+}
+
 func (o *operations) add(info *courierhttp.OperationInfo) {
 	if o.infos == nil {
 		o.infos = make(map[string]courierhttp.OperationInfo)
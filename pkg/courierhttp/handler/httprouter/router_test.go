@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"os"
 	"regexp"
@@ -202,6 +203,32 @@ Server: test (testRouterCreateOrg)
 	})
 }
 
+func TestErrorFormat(t *testing.T) {
+	r := courierhttp.GroupRouter("/").With(
+		courier.NewRouter(courierhttp.UseErrorFormat(courierhttp.ErrorFormatProblemDetails), &testRouterCreateOrg{}),
+	)
+
+	h, err := httprouter.New(r, "test")
+	Then(t, "构建 httprouter handler 成功", Expect(err, Equal[error](nil)))
+
+	type AddOrg struct {
+		courierhttp.MethodPost `path:"/api/example/v0/orgs"`
+		TestOrgInfo            `in:"body"`
+	}
+
+	Then(t, "声明 problem 格式的路由会返回 problem details", Expect(h, Be(testingutil.ShouldReturnWhenRequest(&AddOrg{
+		TestOrgInfo: TestOrgInfo{
+			Name: "xxxxxxx",
+		},
+	}, `
+HTTP/0.0 400 Bad Request
+Content-Type: application/problem+json
+Server: test (testRouterCreateOrg)
+
+{"title":"Bad Request","status":400,"detail":"string value length should be less or equal than 5, but got 7","instance":"/api/example/v0/orgs","code":"INVALID_PARAMETER","errors":[{"code":"INVALID_PARAMETER","message":"string value length should be less or equal than 5, but got 7","location":"body","pointer":"/name","source":"test"}]}
+`))))
+}
+
 func TestRouteSnapshot(t *testing.T) {
 	r := courierhttp.GroupRouter("/").With(
 		courier.NewRouter(&testRouterCookie{}),
@@ -260,3 +287,293 @@ func TestRouteSnapshotMatchesStartupOutput(t *testing.T) {
 	startupOutput := strings.TrimSpace(bytes.NewBuffer(data).String())
 	Then(t, "启动输出与 RouteSnapshot 结果一致", Expect(startupOutput, Equal(snapshot)))
 }
+
+type testVersionListOrgV1 struct {
+	courierhttp.MethodGet `path:"/orgs"`
+}
+
+func (*testVersionListOrgV1) Output(context.Context) (any, error) {
+	return []string{"v1"}, nil
+}
+
+type testVersionListOrgV2 struct {
+	courierhttp.MethodGet `path:"/orgs"`
+}
+
+func (*testVersionListOrgV2) Output(context.Context) (any, error) {
+	return []string{"v2"}, nil
+}
+
+func TestVersioning(t *testing.T) {
+	deprecatedAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	versions := func() []courier.Router {
+		return []courier.Router{
+			courier.NewRouter(courierhttp.Version("1", courierhttp.VersionDeprecated(deprecatedAt, sunset)), &testVersionListOrgV1{}),
+			courier.NewRouter(courierhttp.Version("2"), &testVersionListOrgV2{}),
+		}
+	}
+
+	serve := func(h http.Handler, path string, header http.Header) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		for k, vs := range header {
+			req.Header[k] = vs
+		}
+		rw := httptest.NewRecorder()
+		h.ServeHTTP(rw, req)
+		return rw
+	}
+
+	t.Run("按路径前缀区分版本", func(t *testing.T) {
+		r := courierhttp.GroupRouter("/api").With(versions()...)
+
+		h, err := httprouter.New(r, "test")
+		Then(t, "构建 httprouter handler 成功", Expect(err, Equal[error](nil)))
+
+		v1 := serve(h, "/api/v1/orgs", nil)
+		Then(t, "已弃用版本会返回 Deprecation 与 Sunset 头",
+			Expect(strings.TrimSpace(v1.Body.String()), Equal(`["v1"]`)),
+			Expect(v1.Header().Get("Deprecation"), Equal("@1767225600")),
+			Expect(v1.Header().Get("Sunset"), Equal("Fri, 01 Jan 2027 00:00:00 GMT")),
+		)
+
+		v2 := serve(h, "/api/v2/orgs", nil)
+		Then(t, "未弃用版本不返回 Deprecation 头",
+			Expect(strings.TrimSpace(v2.Body.String()), Equal(`["v2"]`)),
+			Expect(v2.Header().Get("Deprecation"), Equal("")),
+		)
+
+		Then(t, "未带版本前缀的路径不存在", Expect(serve(h, "/api/orgs", nil).Code, Equal(http.StatusNotFound)))
+
+		snapshot, err := httprouter.RouteSnapshot(r, "test")
+		Then(t, "路由快照会标注版本与弃用状态",
+			Expect(err, Equal[error](nil)),
+			Expect(strings.Contains(snapshot, "/api/v1/orgs @v1 (deprecated)"), Equal(true)),
+			Expect(strings.Contains(snapshot, "/api/v2/orgs @v2"), Equal(true)),
+		)
+
+		v2Doc := serve(h, "/api?version=2", nil)
+		Then(t, "可按版本获取 OpenAPI 文档",
+			Expect(v2Doc.Code, Equal(http.StatusOK)),
+			Expect(strings.Contains(v2Doc.Body.String(), `"/api/v2/orgs"`), Equal(true)),
+			Expect(strings.Contains(v2Doc.Body.String(), `"/api/v1/orgs"`), Equal(false)),
+			Expect(strings.Contains(v2Doc.Body.String(), `"version":"2"`), Equal(true)),
+		)
+
+		Then(t, "未知版本的 OpenAPI 文档不存在", Expect(serve(h, "/api?version=9", nil).Code, Equal(http.StatusNotFound)))
+	})
+
+	t.Run("按请求头或 Accept 参数区分版本", func(t *testing.T) {
+		r := courierhttp.GroupRouter("/api").With(
+			courier.NewRouter(courierhttp.UseVersioning(courierhttp.Versioning{
+				Accept: true,
+				Header: "Api-Version",
+			})).With(versions()...),
+		)
+
+		h, err := httprouter.New(r, "test")
+		Then(t, "构建 httprouter handler 成功", Expect(err, Equal[error](nil)))
+
+		Then(t, "按请求头选择版本",
+			Expect(strings.TrimSpace(serve(h, "/api/orgs", http.Header{"Api-Version": {"1"}}).Body.String()), Equal(`["v1"]`)),
+		)
+
+		byAccept := serve(h, "/api/orgs", http.Header{"Accept": {"application/vnd.example+json;version=1"}})
+		Then(t, "按 Accept 参数选择版本",
+			Expect(strings.TrimSpace(byAccept.Body.String()), Equal(`["v1"]`)),
+			Expect(byAccept.Header().Get("Deprecation"), Equal("@1767225600")),
+		)
+
+		Then(t, "未声明版本时使用最高版本",
+			Expect(strings.TrimSpace(serve(h, "/api/orgs", nil).Body.String()), Equal(`["v2"]`)),
+		)
+
+		Then(t, "未知版本返回 404",
+			Expect(serve(h, "/api/orgs", http.Header{"Api-Version": {"9"}}).Code, Equal(http.StatusNotFound)),
+		)
+	})
+
+	t.Run("仅有版本路由时各版本只注册一次", func(t *testing.T) {
+		r := courierhttp.GroupRouter("/api").With(
+			courier.NewRouter(courierhttp.UseVersioning(courierhttp.Versioning{
+				Header: "Api-Version",
+			})).With(versions()...),
+		)
+
+		var routes []httprouter.RouteInfo
+
+		h, err := httprouter.New(r, "test", func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				if p, ok := courierhttp.OperationInfoProviderFromContext(req.Context()); ok {
+					routes = p.(interface{ Routes() []httprouter.RouteInfo }).Routes()
+				}
+				next.ServeHTTP(rw, req)
+			})
+		})
+		Then(t, "构建 httprouter handler 成功", Expect(err, Equal[error](nil)))
+
+		_ = serve(h, "/api/orgs", nil)
+
+		versionsOf := func(path string) (versions []string) {
+			for _, route := range routes {
+				if route.Path == path {
+					versions = append(versions, route.Version)
+				}
+			}
+			return versions
+		}
+
+		snapshot, err := httprouter.RouteSnapshot(r, "test")
+		Then(t, "路由表与快照中每个版本仅出现一次",
+			Expect(versionsOf("/api/orgs"), Equal([]string{"2", "1"})),
+			Expect(err, Equal[error](nil)),
+			Expect(strings.Count(snapshot, "/api/orgs @v2"), Equal(1)),
+			Expect(strings.Count(snapshot, "/api/orgs @v1 (deprecated)"), Equal(1)),
+		)
+	})
+}
+
+type testDeprecatedListOrg struct {
+	courierhttp.MethodGet `path:"/api/example/v0/legacy-orgs"`
+}
+
+func (*testDeprecatedListOrg) Deprecation() courierhttp.Deprecation {
+	return courierhttp.Deprecation{
+		At:        time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		Successor: "/api/example/v0/orgs",
+	}
+}
+
+func (*testDeprecatedListOrg) Output(context.Context) (any, error) {
+	return nil, nil
+}
+
+type testDeprecatedGetOrg struct {
+	courierhttp.MethodGet `path:"/api/example/v0/old-orgs,deprecated"`
+}
+
+func (*testDeprecatedGetOrg) Output(context.Context) (any, error) {
+	return nil, nil
+}
+
+type testDeprecatedUsageRecorder struct {
+	ids []string
+}
+
+func (r *testDeprecatedUsageRecorder) RecordDeprecatedUsage(ctx context.Context, info courierhttp.OperationInfo, d courierhttp.Deprecation) {
+	r.ids = append(r.ids, info.ID)
+}
+
+func TestDeprecation(t *testing.T) {
+	r := courierhttp.GroupRouter("/").With(
+		courier.NewRouter(&testDeprecatedListOrg{}),
+		courier.NewRouter(&testDeprecatedGetOrg{}),
+	)
+
+	h, err := httprouter.New(r, "test")
+	Then(t, "构建 httprouter handler 成功", Expect(err, Equal[error](nil)))
+
+	recorder := &testDeprecatedUsageRecorder{}
+
+	serve := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req = req.WithContext(courierhttp.ContextWithDeprecatedUsageRecorder(req.Context(), recorder))
+		rw := httptest.NewRecorder()
+		h.ServeHTTP(rw, req)
+		return rw
+	}
+
+	legacy := serve("/api/example/v0/legacy-orgs")
+	Then(t, "操作声明的弃用信息会输出到响应头",
+		Expect(legacy.Header().Get("Deprecation"), Equal("@1767225600")),
+		Expect(legacy.Header().Get("Link"), Equal(`</api/example/v0/orgs>; rel="successor-version"`)),
+	)
+
+	old := serve("/api/example/v0/old-orgs")
+	Then(t, "路径标记为 deprecated 的操作同样输出 Deprecation",
+		Expect(old.Header().Get("Deprecation"), Equal("true")),
+		Expect(recorder.ids, Equal([]string{"testDeprecatedListOrg", "testDeprecatedGetOrg"})),
+	)
+}
+
+type testPanicOrg struct {
+	courierhttp.MethodGet `path:"/api/example/v0/panic"`
+}
+
+func (*testPanicOrg) Output(context.Context) (any, error) {
+	panic("boom")
+}
+
+type testPanicAfterWriteOrg struct {
+	courierhttp.MethodGet `path:"/api/example/v0/panic-after-write"`
+}
+
+func (*testPanicAfterWriteOrg) Output(context.Context) (any, error) {
+	return testPanicAfterWrite{}, nil
+}
+
+type testPanicAfterWrite struct{}
+
+func (testPanicAfterWrite) Upgrade(rw http.ResponseWriter, req *http.Request) error {
+	_, _ = rw.Write([]byte("partial"))
+	panic("boom")
+}
+
+type testCrashReporter struct {
+	values []any
+}
+
+func (r *testCrashReporter) ReportCrash(ctx context.Context, info courierhttp.OperationInfo, err *courierhttp.ErrPanic) {
+	r.values = append(r.values, info.ID, err.Value)
+}
+
+func TestPanicRecovery(t *testing.T) {
+	h, err := httprouter.New(courierhttp.GroupRouter("/").With(courier.NewRouter(&testPanicOrg{})), "test")
+	Then(t, "构建 httprouter handler 成功", Expect(err, Equal[error](nil)))
+
+	serve := func(ctx context.Context) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/example/v0/panic", nil).WithContext(ctx)
+		rw := httptest.NewRecorder()
+		h.ServeHTTP(rw, req)
+		return rw
+	}
+
+	reporter := &testCrashReporter{}
+
+	rw := serve(courierhttp.ContextWithCrashReporter(context.Background(), reporter))
+	Then(t, "panic 转换为 500 错误响应并上报",
+		Expect(rw.Code, Equal(http.StatusInternalServerError)),
+		Expect(strings.TrimSpace(rw.Body.String()), Equal(`{"code":500,"msg":"internal server error","errors":[{"code":"courierhttp.ErrPanic","message":"internal server error","source":"test"}]}`)),
+		Expect(reporter.values, Equal([]any{"testPanicOrg", "boom"})),
+	)
+
+	debugRw := serve(courierhttp.ContextWithDebugMode(context.Background(), true))
+	Then(t, "调试模式下响应包含 panic 信息与调用栈",
+		Expect(debugRw.Code, Equal(http.StatusInternalServerError)),
+		Expect(strings.Contains(debugRw.Body.String(), `"message":"panic: boom"`), Equal(true)),
+		Expect(strings.Contains(debugRw.Body.String(), `"description":"goroutine `), Equal(true)),
+	)
+	partial, err := httprouter.New(courierhttp.GroupRouter("/").With(courier.NewRouter(&testPanicAfterWriteOrg{})), "test")
+	Then(t, "构建 httprouter handler 成功", Expect(err, Equal[error](nil)))
+
+	partialReporter := &testCrashReporter{}
+	partialRw := httptest.NewRecorder()
+
+	aborted := func() (v any) {
+		defer func() {
+			v = recover()
+		}()
+
+		req := httptest.NewRequest(http.MethodGet, "/api/example/v0/panic-after-write", nil)
+		partial.ServeHTTP(partialRw, req.WithContext(courierhttp.ContextWithCrashReporter(req.Context(), partialReporter)))
+		return nil
+	}()
+
+	Then(t, "已输出部分响应后 panic 时上报并中断连接，不再追加错误响应",
+		Expect(aborted, Equal[any](http.ErrAbortHandler)),
+		Expect(partialRw.Code, Equal(http.StatusOK)),
+		Expect(partialRw.Body.String(), Equal("partial")),
+		Expect(partialReporter.values, Equal([]any{"testPanicAfterWriteOrg", "boom"})),
+	)
+}
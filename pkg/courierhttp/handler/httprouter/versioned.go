@@ -0,0 +1,154 @@
+package httprouter
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/octohelm/courier/internal/httprequest"
+	"github.com/octohelm/courier/pkg/courierhttp"
+	"github.com/octohelm/courier/pkg/statuserror"
+)
+
+type ErrUnsupportedVersion struct {
+	statuserror.NotFound
+
+	Version string
+}
+
+func (e *ErrUnsupportedVersion) Error() string {
+	return fmt.Sprintf("unsupported api version %q", e.Version)
+}
+
+// versionedRouteHandler 合并同一方法与路径下的多个版本，按请求头或 Accept 参数分发。
+//
+// 未声明版本的请求交由未标注版本的路由处理，不存在时交由最高版本处理。
+type versionedRouteHandler struct {
+	RouteHandler
+
+	versions []RouteHandler
+
+	once    sync.Once
+	handler http.Handler
+}
+
+func (vh *versionedRouteHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	vh.once.Do(func() {
+		vh.handler = vh.dispatch(func(h RouteHandler) http.Handler { return h })
+	})
+	vh.handler.ServeHTTP(rw, req)
+}
+
+func (vh *versionedRouteHandler) dispatch(handlerFor func(h RouteHandler) http.Handler) http.Handler {
+	handlers := make(map[string]http.Handler, len(vh.versions))
+	for _, h := range vh.versions {
+		handlers[h.Version().Version] = handlerFor(h)
+	}
+
+	// 无未标注版本的路由时，最高版本已在 handlers 中，复用以免重复注册
+	fallback, ok := handlers[vh.RouteHandler.Version().Version]
+	if !ok || vh.RouteHandler.Version().Version == "" {
+		fallback = handlerFor(vh.RouteHandler)
+	}
+	versioning := vh.versioning()
+
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if v := versioning.RequestedVersion(req); v != "" {
+			h, ok := handlers[v]
+			if !ok {
+				err := courierhttp.WrapError(&ErrUnsupportedVersion{Version: v})
+				_ = err.(courierhttp.ResponseWriter).WriteResponse(req.Context(), rw, httprequest.From(req))
+				return
+			}
+			h.ServeHTTP(rw, req)
+			return
+		}
+
+		fallback.ServeHTTP(rw, req)
+	})
+}
+
+func (vh *versionedRouteHandler) versioning() courierhttp.Versioning {
+	for _, h := range vh.versions {
+		if v := h.Versioning(); !v.IsZero() {
+			return v
+		}
+	}
+	return courierhttp.Versioning{}
+}
+
+func mergeVersionedRouteHandlers(handlers []RouteHandler) []RouteHandler {
+	grouped := map[string][]RouteHandler{}
+	for _, h := range handlers {
+		key := h.Method() + " " + h.Path()
+		grouped[key] = append(grouped[key], h)
+	}
+
+	merged := make([]RouteHandler, 0, len(handlers))
+
+	for _, h := range handlers {
+		key := h.Method() + " " + h.Path()
+
+		hs, ok := grouped[key]
+		if !ok {
+			continue
+		}
+		delete(grouped, key)
+
+		if len(hs) < 2 || !slices.ContainsFunc(hs, func(h RouteHandler) bool { return h.Version().Version != "" }) {
+			merged = append(merged, hs...)
+			continue
+		}
+
+		vh := &versionedRouteHandler{}
+
+		for _, h := range hs {
+			if h.Version().Version == "" {
+				vh.RouteHandler = h
+				continue
+			}
+			vh.versions = append(vh.versions, h)
+		}
+
+		slices.SortStableFunc(vh.versions, func(a, b RouteHandler) int {
+			return compareVersion(b.Version().Version, a.Version().Version)
+		})
+
+		if vh.RouteHandler == nil {
+			vh.RouteHandler = vh.versions[0]
+		}
+
+		merged = append(merged, vh)
+	}
+
+	return merged
+}
+
+func compareVersion(a, b string) int {
+	pa := strings.Split(strings.TrimPrefix(a, "v"), ".")
+	pb := strings.Split(strings.TrimPrefix(b, "v"), ".")
+
+	for i := 0; i < len(pa) && i < len(pb); i++ {
+		na, errA := strconv.Atoi(pa[i])
+		nb, errB := strconv.Atoi(pb[i])
+
+		if errA != nil || errB != nil {
+			if c := strings.Compare(pa[i], pb[i]); c != 0 {
+				return c
+			}
+			continue
+		}
+
+		if na != nb {
+			if na < nb {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	return len(pa) - len(pb)
+}
@@ -0,0 +1,12 @@
+// Package idempotency 为变更类 operator 提供基于 `Idempotency-Key` 的服务端幂等支持。
+//
+// `IdempotencyKey` 作为中间 operator 挂在路由链上，按 `Idempotency-Key` + 操作 ID
+// 定位记录，并用请求指纹识别键的误用：
+// 重复请求重放首次响应，并发中的重复请求返回 409，
+// 同一个键携带不同请求内容时返回 422。
+// 计算指纹时读取的请求体受 `WithMaxBodySize` 限制，超出时返回 413。
+//
+// 响应的存取通过 `Store` 抽象，`NewMemStore` 提供带 TTL 的内存实现。
+//
+// +gengo:runtimedoc=false
+package idempotency
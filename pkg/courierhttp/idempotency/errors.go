@@ -0,0 +1,31 @@
+package idempotency
+
+import (
+	"fmt"
+
+	"github.com/octohelm/courier/pkg/statuserror"
+)
+
+// ErrIdempotencyKeyInFlight 表示相同幂等键的请求仍在处理中。
+type ErrIdempotencyKeyInFlight struct {
+	statuserror.Conflict
+
+	// 幂等键
+	Key string
+}
+
+func (e *ErrIdempotencyKeyInFlight) Error() string {
+	return fmt.Sprintf("%s: 相同幂等键的请求正在处理中", e.Key)
+}
+
+// ErrIdempotencyKeyReused 表示幂等键被用于不同的请求内容。
+type ErrIdempotencyKeyReused struct {
+	statuserror.UnprocessableEntity
+
+	// 幂等键
+	Key string
+}
+
+func (e *ErrIdempotencyKeyReused) Error() string {
+	return fmt.Sprintf("%s: 幂等键已被用于不同的请求", e.Key)
+}
@@ -0,0 +1,194 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"maps"
+	"net/http"
+	"strconv"
+
+	"github.com/octohelm/courier/internal/httprequest"
+	"github.com/octohelm/courier/pkg/courierhttp"
+)
+
+const (
+	// HeaderIdempotencyKey 为请求携带幂等键的请求头。
+	HeaderIdempotencyKey = "Idempotency-Key"
+	// HeaderIdempotentReplayed 标记响应来自幂等重放。
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+)
+
+// New 创建使用 store 存储响应的幂等中间 operator。
+func New(store Store) *IdempotencyKey {
+	return &IdempotencyKey{store: store}
+}
+
+// IdempotencyKey 为其后的变更类 operator 提供幂等重放。
+//
+// 未携带 `Idempotency-Key` 的请求直接透传；
+// 5xx 响应或 panic 不会被记录，key 会被释放以便客户端重试。
+type IdempotencyKey struct {
+	// 幂等键，相同键与相同请求内容的重复请求将重放首次响应
+	Key string `name:"Idempotency-Key,omitzero" in:"header"`
+
+	store       Store
+	maxBodySize int64
+}
+
+// WithMaxBodySize 设置计算请求指纹时读取请求体的上限，见 courierhttp.ReadBody。
+func (o *IdempotencyKey) WithMaxBodySize(n int64) *IdempotencyKey {
+	o.maxBodySize = n
+	return o
+}
+
+func (IdempotencyKey) ResponseErrors() []error {
+	return []error{
+		&ErrIdempotencyKeyInFlight{},
+		&ErrIdempotencyKeyReused{},
+		&courierhttp.ErrRequestBodyTooLarge{},
+	}
+}
+
+func (o *IdempotencyKey) Output(ctx context.Context) (any, error) {
+	return nil, nil
+}
+
+func (o *IdempotencyKey) PreHandlerMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		idempotencyKey := req.Header.Get(HeaderIdempotencyKey)
+		if idempotencyKey == "" || o.store == nil {
+			next.ServeHTTP(rw, req)
+			return
+		}
+
+		ctx := req.Context()
+
+		key := idempotencyKey
+		if info, ok := courierhttp.OperationInfoFromContext(ctx); ok {
+			key = info.ID + ":" + idempotencyKey
+		}
+
+		fingerprint, err := fingerprintOf(rw, req, o.maxBodySize)
+		if err != nil {
+			writeErr(ctx, rw, req, err)
+			return
+		}
+
+		resp, err := o.store.Acquire(ctx, key, fingerprint)
+		if err != nil {
+			writeErr(ctx, rw, req, withKey(err, idempotencyKey))
+			return
+		}
+
+		if resp != nil {
+			replay(rw, resp)
+			return
+		}
+
+		completed := false
+		defer func() {
+			if !completed {
+				_ = o.store.Release(ctx, key)
+			}
+		}()
+
+		rec := &recorder{ResponseWriter: rw, statusCode: http.StatusOK}
+
+		next.ServeHTTP(rec, req)
+
+		if rec.statusCode >= http.StatusInternalServerError {
+			return
+		}
+
+		if !rec.wroteHeader {
+			rec.header = rw.Header().Clone()
+		}
+
+		if err := o.store.Save(ctx, key, &Response{
+			Fingerprint: fingerprint,
+			StatusCode:  rec.statusCode,
+			Header:      rec.header,
+			Body:        rec.body.Bytes(),
+		}); err == nil {
+			completed = true
+		}
+	})
+}
+
+func withKey(err error, key string) error {
+	switch x := err.(type) {
+	case *ErrIdempotencyKeyInFlight:
+		return &ErrIdempotencyKeyInFlight{Key: key}
+	case *ErrIdempotencyKeyReused:
+		return &ErrIdempotencyKeyReused{Key: key}
+	default:
+		return x
+	}
+}
+
+func fingerprintOf(rw http.ResponseWriter, req *http.Request, maxBodySize int64) (string, error) {
+	h := sha256.New()
+
+	_, _ = io.WriteString(h, req.Method)
+	_, _ = io.WriteString(h, " ")
+	_, _ = io.WriteString(h, req.URL.RequestURI())
+	_, _ = io.WriteString(h, "\n")
+
+	if req.Body != nil && req.Body != http.NoBody {
+		data, err := courierhttp.ReadBody(rw, req, maxBodySize)
+		if err != nil {
+			return "", err
+		}
+
+		_, _ = h.Write(data)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func replay(rw http.ResponseWriter, resp *Response) {
+	header := rw.Header()
+	maps.Copy(header, resp.Header)
+	header.Set(HeaderIdempotentReplayed, "true")
+	header.Set("Content-Length", strconv.Itoa(len(resp.Body)))
+
+	rw.WriteHeader(resp.StatusCode)
+	_, _ = rw.Write(resp.Body)
+}
+
+func writeErr(ctx context.Context, rw http.ResponseWriter, req *http.Request, err error) {
+	_ = courierhttp.WrapError(err).(courierhttp.ResponseWriter).WriteResponse(ctx, rw, httprequest.From(req))
+}
+
+type recorder struct {
+	http.ResponseWriter
+
+	wroteHeader bool
+	statusCode  int
+	header      http.Header
+	body        bytes.Buffer
+}
+
+func (r *recorder) WriteHeader(statusCode int) {
+	if !r.wroteHeader {
+		r.wroteHeader = true
+		r.statusCode = statusCode
+		r.header = r.ResponseWriter.Header().Clone()
+	}
+	r.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (r *recorder) Write(p []byte) (int, error) {
+	if !r.wroteHeader {
+		r.WriteHeader(http.StatusOK)
+	}
+	r.body.Write(p)
+	return r.ResponseWriter.Write(p)
+}
+
+func (r *recorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
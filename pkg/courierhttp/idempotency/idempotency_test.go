@@ -0,0 +1,213 @@
+package idempotency_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/octohelm/x/testing/v2"
+
+	"github.com/octohelm/courier/pkg/courier"
+	"github.com/octohelm/courier/pkg/courierhttp"
+	"github.com/octohelm/courier/pkg/courierhttp/handler/httprouter"
+	"github.com/octohelm/courier/pkg/courierhttp/idempotency"
+)
+
+var created atomic.Int64
+
+type createOrgRequest struct {
+	Name string `json:"name"`
+}
+
+type createOrg struct {
+	courierhttp.MethodPost `path:"/orgs"`
+
+	Body createOrgRequest `in:"body"`
+}
+
+func (req *createOrg) Output(ctx context.Context) (any, error) {
+	return map[string]any{
+		"id":   created.Add(1),
+		"name": req.Body.Name,
+	}, nil
+}
+
+type limitedCreateOrg struct {
+	courierhttp.MethodPost `path:"/limited-orgs"`
+
+	Body createOrgRequest `in:"body"`
+}
+
+func (req *limitedCreateOrg) Output(ctx context.Context) (any, error) {
+	return map[string]any{
+		"name": req.Body.Name,
+	}, nil
+}
+
+var (
+	entered = make(chan struct{})
+	release = make(chan struct{})
+)
+
+type slowCreateOrg struct {
+	courierhttp.MethodPost `path:"/slow-orgs"`
+}
+
+func (req *slowCreateOrg) Output(ctx context.Context) (any, error) {
+	close(entered)
+	<-release
+	return nil, nil
+}
+
+func TestIdempotencyKey(t *testing.T) {
+	store := idempotency.NewMemStore(time.Minute)
+
+	r := courierhttp.GroupRouter("/").With(
+		courier.NewRouter(idempotency.New(store), &createOrg{}),
+		courier.NewRouter(idempotency.New(store), &slowCreateOrg{}),
+		courier.NewRouter(idempotency.New(store).WithMaxBodySize(16), &limitedCreateOrg{}),
+	)
+
+	h, err := httprouter.New(r, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	do := func(path string, key string, body string) (*http.Response, string) {
+		req := httptest.NewRequest(http.MethodPost, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		if key != "" {
+			req.Header.Set(idempotency.HeaderIdempotencyKey, key)
+		}
+		rw := httptest.NewRecorder()
+		h.ServeHTTP(rw, req)
+		resp := rw.Result()
+		data, _ := io.ReadAll(resp.Body)
+		return resp, string(data)
+	}
+
+	Then(
+		t, "相同幂等键的重复请求会重放首次响应",
+		ExpectMust(func() error {
+			resp1, body1 := do("/orgs", "key-1", `{"name":"a"}`)
+			resp2, body2 := do("/orgs", "key-1", `{"name":"a"}`)
+
+			if resp1.StatusCode != http.StatusCreated || resp2.StatusCode != http.StatusCreated {
+				return fmt.Errorf("unexpected status %d, %d", resp1.StatusCode, resp2.StatusCode)
+			}
+			if body1 != body2 {
+				return fmt.Errorf("unexpected replayed body %s != %s", body2, body1)
+			}
+			if resp2.Header.Get(idempotency.HeaderIdempotentReplayed) != "true" {
+				return fmt.Errorf("missing replayed header")
+			}
+			if resp1.Header.Get(idempotency.HeaderIdempotentReplayed) != "" {
+				return fmt.Errorf("unexpected replayed header on first response")
+			}
+			return nil
+		}),
+		ExpectMust(func() error {
+			_, body1 := do("/orgs", "", `{"name":"a"}`)
+			_, body2 := do("/orgs", "", `{"name":"a"}`)
+			if body1 == body2 {
+				return fmt.Errorf("request without key should not be replayed")
+			}
+			return nil
+		}),
+	)
+
+	Then(
+		t, "相同幂等键携带不同请求内容会返回 422",
+		ExpectMust(func() error {
+			_, _ = do("/orgs", "key-2", `{"name":"a"}`)
+			resp, body := do("/orgs", "key-2", `{"name":"b"}`)
+			if resp.StatusCode != http.StatusUnprocessableEntity {
+				return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, body)
+			}
+			return nil
+		}),
+	)
+
+	Then(
+		t, "计算指纹时请求体超出上限会返回 413",
+		ExpectMust(func() error {
+			resp, body := do("/limited-orgs", "key-4", `{"name":"a-very-long-org-name"}`)
+			if resp.StatusCode != http.StatusRequestEntityTooLarge {
+				return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, body)
+			}
+
+			resp, body = do("/limited-orgs", "key-5", `{"name":"a"}`)
+			if resp.StatusCode != http.StatusCreated {
+				return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, body)
+			}
+			return nil
+		}),
+	)
+
+	Then(
+		t, "处理中的重复请求会返回 409",
+		ExpectMust(func() error {
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				_, _ = do("/slow-orgs", "key-3", ``)
+			}()
+
+			<-entered
+
+			resp, body := do("/slow-orgs", "key-3", ``)
+			close(release)
+			if resp.StatusCode != http.StatusConflict {
+				return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, body)
+			}
+
+			<-done
+
+			resp, _ = do("/slow-orgs", "key-3", ``)
+			if resp.StatusCode != http.StatusNoContent || resp.Header.Get(idempotency.HeaderIdempotentReplayed) != "true" {
+				return fmt.Errorf("unexpected status %d after completed", resp.StatusCode)
+			}
+			return nil
+		}),
+	)
+}
+
+func TestMemStore(t *testing.T) {
+	ctx := context.Background()
+
+	Then(
+		t, "内存存储按 TTL 过期并支持释放",
+		ExpectMust(func() error {
+			s := idempotency.NewMemStore(10 * time.Millisecond)
+
+			if resp, err := s.Acquire(ctx, "k", "f"); err != nil || resp != nil {
+				return fmt.Errorf("unexpected acquire result %v, %v", resp, err)
+			}
+			if err := s.Release(ctx, "k"); err != nil {
+				return err
+			}
+			if _, err := s.Acquire(ctx, "k", "f2"); err != nil {
+				return fmt.Errorf("released key should be acquirable: %w", err)
+			}
+			if err := s.Save(ctx, "k", &idempotency.Response{Fingerprint: "f2", StatusCode: http.StatusOK}); err != nil {
+				return err
+			}
+			if resp, err := s.Acquire(ctx, "k", "f2"); err != nil || resp == nil {
+				return fmt.Errorf("expect saved response, got %v, %v", resp, err)
+			}
+
+			time.Sleep(20 * time.Millisecond)
+
+			if resp, err := s.Acquire(ctx, "k", "f3"); err != nil || resp != nil {
+				return fmt.Errorf("expired key should be acquirable, got %v, %v", resp, err)
+			}
+			return nil
+		}),
+	)
+}
@@ -0,0 +1,30 @@
+package idempotency
+
+import (
+	"context"
+	"net/http"
+)
+
+// Store 表示幂等响应的存储。
+//
+// 实现需保证 `Acquire` 对同一个 key 的原子性。
+type Store interface {
+	// Acquire 以 fingerprint 占用 key。
+	// key 未被占用时占用成功并返回 nil；
+	// key 已有完成的响应时返回该响应；
+	// key 仍在处理中时返回 *ErrIdempotencyKeyInFlight；
+	// fingerprint 不一致时返回 *ErrIdempotencyKeyReused。
+	Acquire(ctx context.Context, key string, fingerprint string) (*Response, error)
+	// Save 保存 key 对应的完成响应。
+	Save(ctx context.Context, key string, resp *Response) error
+	// Release 释放未完成的 key，便于后续请求重试。
+	Release(ctx context.Context, key string) error
+}
+
+// Response 表示被记录下来的响应。
+type Response struct {
+	Fingerprint string
+	StatusCode  int
+	Header      http.Header
+	Body        []byte
+}
@@ -0,0 +1,94 @@
+package idempotency
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// NewMemStore 创建内存幂等存储，记录在 ttl 后过期。
+func NewMemStore(ttl time.Duration) Store {
+	return &memStore{
+		ttl:     ttl,
+		now:     time.Now,
+		records: map[string]*memRecord{},
+	}
+}
+
+type memStore struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	records map[string]*memRecord
+	nextGC  time.Time
+}
+
+type memRecord struct {
+	fingerprint string
+	resp        *Response
+	expiresAt   time.Time
+}
+
+func (s *memStore) Acquire(ctx context.Context, key string, fingerprint string) (*Response, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+
+	s.gc(now)
+
+	if r, ok := s.records[key]; ok && now.Before(r.expiresAt) {
+		if r.fingerprint != fingerprint {
+			return nil, &ErrIdempotencyKeyReused{Key: key}
+		}
+		if r.resp == nil {
+			return nil, &ErrIdempotencyKeyInFlight{Key: key}
+		}
+		return r.resp, nil
+	}
+
+	s.records[key] = &memRecord{
+		fingerprint: fingerprint,
+		expiresAt:   now.Add(s.ttl),
+	}
+
+	return nil, nil
+}
+
+func (s *memStore) Save(ctx context.Context, key string, resp *Response) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.records[key] = &memRecord{
+		fingerprint: resp.Fingerprint,
+		resp:        resp,
+		expiresAt:   s.now().Add(s.ttl),
+	}
+
+	return nil
+}
+
+func (s *memStore) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r, ok := s.records[key]; ok && r.resp == nil {
+		delete(s.records, key)
+	}
+
+	return nil
+}
+
+func (s *memStore) gc(now time.Time) {
+	if now.Before(s.nextGC) {
+		return
+	}
+	s.nextGC = now.Add(s.ttl)
+
+	for key, r := range s.records {
+		if !now.Before(r.expiresAt) {
+			delete(s.records, key)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package courierhttp
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// LinksDescriber 用于根据当前请求 URL 描述 RFC 8288 `Link` 响应头。
+type LinksDescriber interface {
+	Links(u *url.URL) []Link
+}
+
+// Link 表示 `Link` 头中的一条链接。
+type Link struct {
+	Target string
+	Rel    string
+}
+
+func (l Link) String() string {
+	return "<" + l.Target + ">; rel=" + strconv.Quote(l.Rel)
+}
+
+// ParseLinks 解析 `Link` 头，忽略无法识别的条目。
+func ParseLinks(values ...string) []Link {
+	links := make([]Link, 0)
+
+	for _, value := range values {
+		for part := range strings.SplitSeq(value, ",") {
+			part = strings.TrimSpace(part)
+			if !strings.HasPrefix(part, "<") {
+				continue
+			}
+
+			end := strings.Index(part, ">")
+			if end < 0 {
+				continue
+			}
+
+			l := Link{Target: part[1:end]}
+
+			for param := range strings.SplitSeq(part[end+1:], ";") {
+				k, v, ok := strings.Cut(strings.TrimSpace(param), "=")
+				if !ok || !strings.EqualFold(k, "rel") {
+					continue
+				}
+				if unquoted, err := strconv.Unquote(v); err == nil {
+					v = unquoted
+				}
+				l.Rel = v
+			}
+
+			links = append(links, l)
+		}
+	}
+
+	return links
+}
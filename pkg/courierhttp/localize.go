@@ -0,0 +1,16 @@
+package courierhttp
+
+import (
+	"context"
+
+	"github.com/octohelm/courier/pkg/i18n"
+	"github.com/octohelm/courier/pkg/statuserror"
+)
+
+func localizerFor(ctx context.Context, req RequestInfo) statuserror.Localizer {
+	c := i18n.CatalogFromContext(ctx)
+	if len(c.Languages()) == 0 {
+		return nil
+	}
+	return c.Localizer(i18n.ParseAcceptLanguage(req.Header().Values("Accept-Language")...)...)
+}
@@ -0,0 +1,13 @@
+// Package mock 根据 OpenAPI 文档提供 mock 服务。
+//
+// 文档中的每个 operation 都会注册为路由：请求参数与请求体按声明的 schema 校验，
+// 响应优先使用声明的 example，否则按 schema 生成符合 enum、format、边界与 pattern 约束的数据。
+// 可通过请求头切换场景：
+//
+//	X-Mock-Status: 404     // 返回声明的 404 响应
+//	X-Mock-Example: empty  // 使用具名 example
+//	X-Mock-Seed: 1         // 指定生成数据的随机种子
+//	X-Mock-Delay: 300ms    // 延迟响应
+//
+// +gengo:runtimedoc=false
+package mock
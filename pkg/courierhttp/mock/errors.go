@@ -0,0 +1,56 @@
+package mock
+
+import (
+	"fmt"
+
+	"github.com/octohelm/courier/pkg/statuserror"
+)
+
+type ErrOperationNotFound struct {
+	statuserror.NotFound
+
+	Method string
+	Path   string
+}
+
+func (e *ErrOperationNotFound) Error() string {
+	return fmt.Sprintf("no operation declared for %s %s", e.Method, e.Path)
+}
+
+type ErrMissingRequestBody struct {
+	statuserror.BadRequest
+}
+
+func (e *ErrMissingRequestBody) Error() string {
+	return "missing request body"
+}
+
+type ErrInvalidRequestBody struct {
+	statuserror.BadRequest
+
+	Reason string
+}
+
+func (e *ErrInvalidRequestBody) Error() string {
+	return fmt.Sprintf("invalid request body: %s", e.Reason)
+}
+
+type ErrUnsupportedMediaType struct {
+	statuserror.UnsupportedMediaType
+
+	ContentType string
+}
+
+func (e *ErrUnsupportedMediaType) Error() string {
+	return fmt.Sprintf("content type %s is not declared", e.ContentType)
+}
+
+type ErrScenarioNotDeclared struct {
+	statuserror.BadRequest
+
+	Scenario string
+}
+
+func (e *ErrScenarioNotDeclared) Error() string {
+	return fmt.Sprintf("scenario %s is not declared", e.Scenario)
+}
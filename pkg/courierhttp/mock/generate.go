@@ -0,0 +1,323 @@
+package mock
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"regexp/syntax"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/octohelm/courier/pkg/openapi/jsonschema"
+)
+
+// maxDepth 限制递归 schema 的展开层数，超出后仅生成必填字段；
+// 必填字段自身循环引用时，在两倍层数处截断
+const maxDepth = 4
+
+type generator struct {
+	r       *rand.Rand
+	schemas map[string]jsonschema.Schema
+}
+
+func (g *generator) generate(s jsonschema.Schema, depth int) any {
+	if s == nil {
+		return nil
+	}
+
+	if m := s.GetMetadata(); m != nil {
+		if len(m.Examples) > 0 {
+			return m.Examples[0]
+		}
+		if m.Default != nil {
+			return m.Default
+		}
+	}
+
+	switch x := s.(type) {
+	case *jsonschema.RefType:
+		target, ok := g.schemas[x.RefName()]
+		if !ok || depth > 2*maxDepth {
+			return nil
+		}
+		return g.generate(target, depth+1)
+	case *jsonschema.ObjectType:
+		return g.object(x, depth)
+	case *jsonschema.ArrayType:
+		n := 1
+		if x.MinItems != nil {
+			n = max(n, int(*x.MinItems))
+		}
+		if x.MaxItems != nil {
+			n = min(n, int(*x.MaxItems))
+		}
+		if depth > maxDepth {
+			n = 0
+			if x.MinItems != nil {
+				n = int(*x.MinItems)
+			}
+		}
+		items := make([]any, 0, n)
+		for range n {
+			items = append(items, g.generate(x.Items, depth+1))
+		}
+		return items
+	case *jsonschema.StringType:
+		return g.string(x)
+	case *jsonschema.NumberType:
+		return g.number(x)
+	case *jsonschema.BooleanType:
+		return g.r.IntN(2) == 1
+	case *jsonschema.NullType:
+		return nil
+	case *jsonschema.EnumType:
+		if len(x.Enum) == 0 {
+			return nil
+		}
+		return x.Enum[g.r.IntN(len(x.Enum))]
+	case *jsonschema.UnionType:
+		return g.union(x, depth)
+	case *jsonschema.IntersectionType:
+		merged := map[string]any{}
+		for _, sub := range x.AllOf {
+			v := g.generate(sub, depth)
+			obj, ok := v.(map[string]any)
+			if !ok {
+				return v
+			}
+			for key, value := range obj {
+				merged[key] = value
+			}
+		}
+		return merged
+	}
+
+	return nil
+}
+
+func (g *generator) object(x *jsonschema.ObjectType, depth int) any {
+	obj := map[string]any{}
+
+	for key, prop := range x.Properties.KeyValues() {
+		// 超出展开层数后仅生成必填字段
+		if depth > maxDepth && !slices.Contains(x.Required, key) {
+			continue
+		}
+		obj[key] = g.generate(prop, depth+1)
+	}
+
+	if x.Properties.Len() == 0 && x.AdditionalProperties != nil && depth <= maxDepth {
+		key := "key"
+		if x.PropertyNames != nil {
+			if k, ok := g.generate(x.PropertyNames, depth+1).(string); ok && k != "" {
+				key = k
+			}
+		}
+		obj[key] = g.generate(x.AdditionalProperties, depth+1)
+	}
+
+	return obj
+}
+
+func (g *generator) union(x *jsonschema.UnionType, depth int) any {
+	if len(x.OneOf) == 0 {
+		return nil
+	}
+
+	if d := x.Discriminator; d != nil && d.PropertyName != "" {
+		i := g.r.IntN(len(x.OneOf))
+		v := g.generate(x.OneOf[i], depth)
+
+		if obj, ok := v.(map[string]any); ok {
+			if r, ok := x.OneOf[i].(jsonschema.Refer); ok {
+				tag := r.RefName()
+				for value, mapped := range d.Mapping {
+					if mr, ok := mapped.(jsonschema.Refer); ok && mr.RefName() == tag {
+						tag = value
+					}
+				}
+				if _, ok := obj[d.PropertyName]; !ok {
+					obj[d.PropertyName] = tag
+				}
+			}
+		}
+
+		return v
+	}
+
+	// 可空类型优先生成非空值
+	for _, sub := range x.OneOf {
+		if _, ok := sub.(*jsonschema.NullType); !ok {
+			return g.generate(sub, depth)
+		}
+	}
+
+	return nil
+}
+
+func (g *generator) string(x *jsonschema.StringType) any {
+	switch x.Format {
+	case "date-time":
+		return time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC).Add(time.Duration(g.r.IntN(1<<20)) * time.Second).Format(time.RFC3339)
+	case "date":
+		return time.Date(2006, 1, 2, 0, 0, 0, 0, time.UTC).AddDate(0, 0, g.r.IntN(365)).Format(time.DateOnly)
+	case "time":
+		return fmt.Sprintf("%02d:%02d:%02d", g.r.IntN(24), g.r.IntN(60), g.r.IntN(60))
+	case "duration":
+		return fmt.Sprintf("%ds", g.r.IntN(3600))
+	case "uuid":
+		b := make([]byte, 16)
+		for i := range b {
+			b[i] = byte(g.r.IntN(256))
+		}
+		b[6] = b[6]&0x0f | 0x40
+		b[8] = b[8]&0x3f | 0x80
+		return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+	case "email":
+		return g.word(6) + "@example.com"
+	case "uri", "url", "uri-reference":
+		return "https://example.com/" + g.word(6)
+	case "hostname":
+		return g.word(6) + ".example.com"
+	case "ipv4":
+		return fmt.Sprintf("192.0.2.%d", g.r.IntN(255))
+	case "ipv6":
+		return fmt.Sprintf("2001:db8::%x", g.r.IntN(0xffff))
+	case "binary", "bytes":
+		return ""
+	}
+
+	if x.Pattern != "" {
+		if re, err := syntax.Parse(x.Pattern, syntax.Perl); err == nil {
+			b := &strings.Builder{}
+			g.regexp(b, re.Simplify())
+			return b.String()
+		}
+	}
+
+	minLength, maxLength := 0, 8
+	if x.MinLength != nil {
+		minLength = int(*x.MinLength)
+		maxLength = max(maxLength, minLength)
+	}
+	if x.MaxLength != nil {
+		maxLength = min(maxLength, int(*x.MaxLength))
+	}
+
+	return g.word(max(minLength, maxLength))
+}
+
+const letters = "abcdefghijklmnopqrstuvwxyz"
+
+func (g *generator) word(n int) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = letters[g.r.IntN(len(letters))]
+	}
+	return string(b)
+}
+
+// regexp 按正则语法树生成一个匹配的字符串，重复次数上限为 4
+func (g *generator) regexp(b *strings.Builder, re *syntax.Regexp) {
+	switch re.Op {
+	case syntax.OpLiteral:
+		b.WriteString(string(re.Rune))
+	case syntax.OpCharClass:
+		if len(re.Rune) >= 2 {
+			i := g.r.IntN(len(re.Rune)/2) * 2
+			lo, hi := re.Rune[i], re.Rune[i+1]
+			// 尽量生成可见的 ASCII 字符
+			if hi > 0x7e && lo <= 0x7e {
+				hi = 0x7e
+			}
+			if lo < 0x20 && hi >= 0x20 {
+				lo = 0x20
+			}
+			b.WriteRune(lo + rune(g.r.IntN(int(hi-lo)+1)))
+		}
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		b.WriteByte(letters[g.r.IntN(len(letters))])
+	case syntax.OpCapture:
+		g.regexp(b, re.Sub[0])
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			g.regexp(b, sub)
+		}
+	case syntax.OpAlternate:
+		g.regexp(b, re.Sub[g.r.IntN(len(re.Sub))])
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		lo, hi := 0, 4
+		switch re.Op {
+		case syntax.OpPlus:
+			lo = 1
+		case syntax.OpQuest:
+			hi = 1
+		case syntax.OpRepeat:
+			lo = re.Min
+			hi = re.Max
+			if hi < 0 {
+				hi = lo + 4
+			}
+		}
+		n := lo
+		if hi > lo {
+			n += g.r.IntN(min(hi, lo+4) - lo + 1)
+		}
+		for range n {
+			g.regexp(b, re.Sub[0])
+		}
+	}
+}
+
+func (g *generator) number(x *jsonschema.NumberType) any {
+	lo, hi := 0.0, 100.0
+
+	step := 0.01
+	if x.Type == "integer" {
+		step = 1
+	}
+
+	if x.Minimum != nil {
+		lo = *x.Minimum
+	}
+	if x.ExclusiveMinimum != nil {
+		lo = *x.ExclusiveMinimum + step
+	}
+	if x.Maximum != nil {
+		hi = *x.Maximum
+	}
+	if x.ExclusiveMaximum != nil {
+		hi = *x.ExclusiveMaximum - step
+	}
+
+	hasMin := x.Minimum != nil || x.ExclusiveMinimum != nil
+	hasMax := x.Maximum != nil || x.ExclusiveMaximum != nil
+
+	// 仅声明一侧边界时，在其附近取值
+	switch {
+	case hasMin && !hasMax:
+		hi = lo + 100
+	case hasMax && !hasMin:
+		lo = hi - 100
+	}
+	if hi < lo {
+		hi = lo
+	}
+
+	v := lo + g.r.Float64()*(hi-lo)
+
+	if m := x.MultipleOf; m != nil && *m > 0 {
+		v = math.Ceil(lo / *m) * *m
+	}
+
+	if x.Type == "integer" {
+		i := int64(math.Ceil(v))
+		if float64(i) > hi {
+			i = int64(math.Floor(hi))
+		}
+		return i
+	}
+
+	return math.Round(v*100) / 100
+}
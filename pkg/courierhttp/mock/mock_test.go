@@ -0,0 +1,226 @@
+package mock_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/go-json-experiment/json"
+	"github.com/go-json-experiment/json/jsontext"
+	. "github.com/octohelm/x/testing/v2"
+
+	"github.com/octohelm/courier/pkg/courierhttp/mock"
+	"github.com/octohelm/courier/pkg/openapi"
+	"github.com/octohelm/courier/pkg/openapi/jsonschema/compiler"
+)
+
+const doc = `{
+  "openapi": "3.1.0",
+  "info": {"title": "demo"},
+  "paths": {
+    "/orgs": {
+      "get": {
+        "operationId": "ListOrg",
+        "parameters": [
+          {"name": "size", "in": "query", "schema": {"type": "integer", "minimum": 1, "maximum": 50}},
+          {"name": "type", "in": "query", "schema": {"type": "array", "items": {"enum": ["team", "company"]}}, "explode": false}
+        ],
+        "responses": {
+          "200": {
+            "description": "",
+            "headers": {"X-Total": {"schema": {"type": "integer", "minimum": 0, "maximum": 10}}},
+            "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/Org"}, "minItems": 2}}}
+          }
+        }
+      },
+      "post": {
+        "operationId": "CreateOrg",
+        "requestBody": {
+          "required": true,
+          "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Org"}}}
+        },
+        "responses": {
+          "201": {"description": ""},
+          "409": {"description": "", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/StatusError"}}}}
+        }
+      }
+    },
+    "/orgs/{orgName}": {
+      "get": {
+        "operationId": "GetOrg",
+        "parameters": [
+          {"name": "orgName", "in": "path", "required": true, "schema": {"type": "string", "pattern": "^[a-z]+$"}}
+        ],
+        "responses": {
+          "200": {
+            "description": "",
+            "content": {
+              "application/json": {
+                "schema": {"$ref": "#/components/schemas/Org"},
+                "examples": {
+                  "demo": {"value": {"name": "demo", "type": "team", "id": "6ba7b810-9dad-41d1-80b4-00c04fd430c8", "size": 1}}
+                }
+              }
+            }
+          },
+          "404": {"description": "", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/StatusError"}}}}
+        }
+      }
+    }
+  },
+  "components": {
+    "schemas": {
+      "Org": {
+        "type": "object",
+        "properties": {
+          "id": {"type": "string", "format": "uuid"},
+          "name": {"type": "string", "pattern": "^[a-z]{3,8}$"},
+          "email": {"type": "string", "format": "email"},
+          "createdAt": {"type": "string", "format": "date-time"},
+          "size": {"type": "integer", "minimum": 1, "maximum": 100},
+          "type": {"enum": ["team", "company"]},
+          "parent": {"$ref": "#/components/schemas/Org"}
+        },
+        "required": ["name", "type"]
+      },
+      "StatusError": {
+        "type": "object",
+        "properties": {
+          "code": {"type": "integer"},
+          "msg": {"type": "string", "examples": ["not found"]}
+        },
+        "required": ["code", "msg"]
+      }
+    }
+  }
+}`
+
+func TestServer(t *testing.T) {
+	p := &openapi.Payload{}
+	if err := p.UnmarshalJSON([]byte(doc)); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := mock.New(&p.OpenAPI)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := compiler.New(compiler.WithSchemas(p.Schemas))
+
+	do := func(method string, path string, body string, headers ...string) *httptest.ResponseRecorder {
+		var r io.Reader
+		if body != "" {
+			r = strings.NewReader(body)
+		}
+		req := httptest.NewRequest(method, path, r)
+		if body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		for i := 0; i+1 < len(headers); i += 2 {
+			req.Header.Set(headers[i], headers[i+1])
+		}
+		rw := httptest.NewRecorder()
+		s.ServeHTTP(rw, req)
+		return rw
+	}
+
+	validate := func(ref string, body []byte) error {
+		v, err := c.Compile(p.RefSchema(ref))
+		if err != nil {
+			return err
+		}
+		return v.Validate(jsontext.Value(body))
+	}
+
+	t.Run("generated response", func(t *testing.T) {
+		rw := do(http.MethodGet, "/orgs", "")
+
+		var orgs []map[string]any
+		if err := json.Unmarshal(rw.Body.Bytes(), &orgs); err != nil {
+			t.Fatal(err)
+		}
+
+		Then(t, "生成的响应符合 schema",
+			Expect(rw.Code, Equal(http.StatusOK)),
+			Expect(rw.Header().Get("Content-Type"), Equal("application/json")),
+			Expect(len(orgs), Equal(2)),
+			ExpectMust(func() error {
+				for _, org := range orgs {
+					data, _ := json.Marshal(org)
+					if err := validate("Org", data); err != nil {
+						return err
+					}
+				}
+				return nil
+			}),
+		)
+
+		Then(t, "声明的响应头同样生成",
+			Expect(regexp.MustCompile(`^(10|\d)$`).MatchString(rw.Header().Get("X-Total")), Equal(true)),
+		)
+
+		Then(t, "相同请求得到相同响应",
+			Expect(do(http.MethodGet, "/orgs", "").Body.String(), Equal(rw.Body.String())),
+		)
+
+		Then(t, "可通过种子切换生成数据",
+			Expect(do(http.MethodGet, "/orgs", "", mock.HeaderMockSeed, "1").Body.String() != rw.Body.String(), Equal(true)),
+		)
+	})
+
+	t.Run("examples", func(t *testing.T) {
+		Then(t, "优先使用声明的 example",
+			Expect(do(http.MethodGet, "/orgs/demo", "").Body.String(), Equal(
+				`{"id":"6ba7b810-9dad-41d1-80b4-00c04fd430c8","name":"demo","size":1,"type":"team"}`,
+			)),
+		)
+
+		Then(t, "未声明的具名 example 返回 400",
+			Expect(do(http.MethodGet, "/orgs/demo", "", mock.HeaderMockExample, "unknown").Code, Equal(http.StatusBadRequest)),
+		)
+	})
+
+	t.Run("scenario", func(t *testing.T) {
+		rw := do(http.MethodGet, "/orgs/demo", "", mock.HeaderMockStatus, "404")
+
+		Then(t, "按声明的状态码返回响应",
+			Expect(rw.Code, Equal(http.StatusNotFound)),
+			ExpectMust(func() error { return validate("StatusError", rw.Body.Bytes()) }),
+			Expect(strings.Contains(rw.Body.String(), `"msg":"not found"`), Equal(true)),
+		)
+
+		Then(t, "未声明的状态码返回 400",
+			Expect(do(http.MethodGet, "/orgs/demo", "", mock.HeaderMockStatus, "500").Code, Equal(http.StatusBadRequest)),
+		)
+
+		Then(t, "无响应内容时仅返回状态码",
+			Expect(do(http.MethodPost, "/orgs", `{"name":"demo","type":"team"}`).Code, Equal(http.StatusCreated)),
+		)
+	})
+
+	t.Run("request validation", func(t *testing.T) {
+		Then(t, "参数不符合 schema 时返回 400",
+			Expect(do(http.MethodGet, "/orgs?size=0", "").Code, Equal(http.StatusBadRequest)),
+			Expect(do(http.MethodGet, "/orgs?type=team,unknown", "").Code, Equal(http.StatusBadRequest)),
+			Expect(do(http.MethodGet, "/orgs/Demo", "").Code, Equal(http.StatusBadRequest)),
+		)
+
+		Then(t, "符合 schema 的参数校验通过",
+			Expect(do(http.MethodGet, "/orgs?size=10&type=team,company", "").Code, Equal(http.StatusOK)),
+		)
+
+		Then(t, "请求体按 schema 校验",
+			Expect(do(http.MethodPost, "/orgs", "").Code, Equal(http.StatusBadRequest)),
+			Expect(do(http.MethodPost, "/orgs", `{"name":"demo"}`).Code, Equal(http.StatusBadRequest)),
+			Expect(do(http.MethodPost, "/orgs", `{"name":"demo","type":"team"`).Code, Equal(http.StatusBadRequest)),
+		)
+
+		Then(t, "未声明的 operation 返回 404",
+			Expect(do(http.MethodDelete, "/orgs", "").Code, Equal(http.StatusNotFound)),
+		)
+	})
+}
@@ -0,0 +1,541 @@
+package mock
+
+import (
+	"cmp"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"maps"
+	"math/rand/v2"
+	"mime"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-json-experiment/json"
+	"github.com/go-json-experiment/json/jsontext"
+
+	"github.com/octohelm/courier/internal/httprequest"
+	"github.com/octohelm/courier/pkg/courierhttp"
+	"github.com/octohelm/courier/pkg/openapi"
+	"github.com/octohelm/courier/pkg/openapi/jsonschema"
+	"github.com/octohelm/courier/pkg/openapi/jsonschema/compiler"
+	"github.com/octohelm/courier/pkg/validator"
+	validatorerrors "github.com/octohelm/courier/pkg/validator/errors"
+)
+
+const (
+	// HeaderMockStatus 指定响应状态码，需在 operation 中声明，或由 `default` 响应承接。
+	HeaderMockStatus = "X-Mock-Status"
+	// HeaderMockExample 指定使用响应内容中具名的 example。
+	HeaderMockExample = "X-Mock-Example"
+	// HeaderMockSeed 指定生成数据的随机种子，默认由请求方法与路径决定，相同请求得到相同响应。
+	HeaderMockSeed = "X-Mock-Seed"
+	// HeaderMockDelay 指定响应前的等待时间，如 `300ms`。
+	HeaderMockDelay = "X-Mock-Delay"
+)
+
+type OptionFunc func(s *Server)
+
+// DisableRequestValidation 关闭请求参数与请求体的校验。
+func DisableRequestValidation() OptionFunc {
+	return func(s *Server) {
+		s.skipRequestValidation = true
+	}
+}
+
+// WithSeed 设置生成数据的基础随机种子。
+func WithSeed(seed uint64) OptionFunc {
+	return func(s *Server) {
+		s.seed = seed
+	}
+}
+
+// New 根据 OpenAPI 文档创建 mock 服务，文档中的每个 operation 都会注册为路由。
+func New(doc *openapi.OpenAPI, fns ...OptionFunc) (*Server, error) {
+	s := &Server{
+		doc:      doc,
+		compiler: compiler.New(compiler.WithSchemas(doc.Schemas)),
+		mux:      http.NewServeMux(),
+	}
+
+	for _, fn := range fns {
+		fn(s)
+	}
+
+	for path, item := range doc.Paths.KeyValues() {
+		for method, op := range item.KeyValues() {
+			if err := s.register(strings.ToUpper(method), path, op); err != nil {
+				return nil, fmt.Errorf("register %s %s failed: %w", strings.ToUpper(method), path, err)
+			}
+		}
+	}
+
+	return s, nil
+}
+
+// Server 以 OpenAPI 文档中声明的响应模拟服务。
+type Server struct {
+	doc      *openapi.OpenAPI
+	compiler *compiler.Compiler
+	mux      *http.ServeMux
+
+	seed                  uint64
+	skipRequestValidation bool
+}
+
+func (s *Server) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if _, pattern := s.mux.Handler(req); pattern == "" {
+		writeErr(rw, req, &ErrOperationNotFound{Method: req.Method, Path: req.URL.Path})
+		return
+	}
+	s.mux.ServeHTTP(rw, req)
+}
+
+var rePathParam = regexp.MustCompile(`\{([^}]+)\}`)
+
+func (s *Server) register(method string, path string, op *openapi.OperationObject) (err error) {
+	o := &operation{
+		server:     s,
+		op:         op,
+		wildcards:  map[string]string{},
+		parameters: map[*openapi.ParameterObject]validator.Validator{},
+		bodies:     map[string]validator.Validator{},
+	}
+
+	// OpenAPI 路径参数名不一定是合法的 ServeMux 通配符名
+	pattern := rePathParam.ReplaceAllStringFunc(path, func(str string) string {
+		wildcard := fmt.Sprintf("p%d", len(o.wildcards))
+		o.wildcards[str[1:len(str)-1]] = wildcard
+		return "{" + wildcard + "}"
+	})
+
+	if strings.HasSuffix(pattern, "/") {
+		pattern += "{$}"
+	}
+
+	for _, p := range op.Parameters {
+		v, err := s.compiler.Compile(p.Schema)
+		if err != nil {
+			return fmt.Errorf("compile parameter %s failed: %w", p.Name, err)
+		}
+		o.parameters[p] = v
+	}
+
+	if op.RequestBody != nil {
+		for contentType, mt := range op.RequestBody.Content {
+			var v validator.Validator
+			if mt != nil && mt.Schema != nil && isJSONMediaType(contentType) {
+				v, err = s.compiler.Compile(mt.Schema)
+				if err != nil {
+					return fmt.Errorf("compile request body %s failed: %w", contentType, err)
+				}
+			}
+			o.bodies[contentType] = v
+		}
+	}
+
+	defer func() {
+		if e := recover(); e != nil {
+			err = fmt.Errorf("%v", e)
+		}
+	}()
+
+	s.mux.Handle(method+" "+pattern, o)
+
+	return nil
+}
+
+type operation struct {
+	server     *Server
+	op         *openapi.OperationObject
+	wildcards  map[string]string
+	parameters map[*openapi.ParameterObject]validator.Validator
+	bodies     map[string]validator.Validator
+}
+
+func (o *operation) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if d, err := time.ParseDuration(req.Header.Get(HeaderMockDelay)); err == nil && d > 0 {
+		select {
+		case <-time.After(d):
+		case <-req.Context().Done():
+			return
+		}
+	}
+
+	if !o.server.skipRequestValidation {
+		if err := o.validateRequest(req); err != nil {
+			writeErr(rw, req, err)
+			return
+		}
+	}
+
+	statusCode, resp, err := o.response(req.Header.Get(HeaderMockStatus))
+	if err != nil {
+		writeErr(rw, req, err)
+		return
+	}
+
+	g := &generator{
+		r:       rand.New(rand.NewPCG(o.server.seedOf(req), 0)),
+		schemas: o.server.doc.Schemas,
+	}
+
+	for name, h := range resp.Headers {
+		if h == nil || h.Schema == nil {
+			continue
+		}
+		switch v := g.generate(h.Schema, 0).(type) {
+		case nil:
+		case string:
+			rw.Header().Set(name, v)
+		default:
+			rw.Header().Set(name, fmt.Sprint(v))
+		}
+	}
+
+	if len(resp.Content) == 0 {
+		rw.WriteHeader(statusCode)
+		return
+	}
+
+	contentType := preferredContentType(resp)
+	mt := resp.Content[contentType]
+
+	body, err := o.body(g, mt, req.Header.Get(HeaderMockExample))
+	if err != nil {
+		writeErr(rw, req, err)
+		return
+	}
+
+	if contentType == "*/*" {
+		contentType = "application/octet-stream"
+	}
+
+	rw.Header().Set("Content-Type", contentType)
+	rw.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	rw.WriteHeader(statusCode)
+	_, _ = rw.Write(body)
+}
+
+// response 按场景选择响应，未指定时优先选择 2xx 响应
+func (o *operation) response(scenario string) (int, *openapi.ResponseObject, error) {
+	responses := o.op.Responses
+
+	if scenario != "" {
+		statusCode, err := strconv.Atoi(scenario)
+		if err != nil || statusCode < 100 || statusCode > 599 {
+			return 0, nil, &ErrScenarioNotDeclared{Scenario: scenario}
+		}
+
+		for _, key := range []string{scenario, scenario[0:1] + "XX", "default"} {
+			if resp, ok := responses[key]; ok && resp != nil {
+				return statusCode, resp, nil
+			}
+		}
+
+		return 0, nil, &ErrScenarioNotDeclared{Scenario: scenario}
+	}
+
+	keys := slices.Sorted(maps.Keys(responses))
+
+	for _, key := range keys {
+		if strings.HasPrefix(key, "2") && responses[key] != nil {
+			statusCode, err := strconv.Atoi(key)
+			if err != nil {
+				statusCode = http.StatusOK
+			}
+			return statusCode, responses[key], nil
+		}
+	}
+
+	if resp, ok := responses["default"]; ok && resp != nil {
+		return http.StatusOK, resp, nil
+	}
+
+	for _, key := range keys {
+		if statusCode, err := strconv.Atoi(key); err == nil && responses[key] != nil {
+			return statusCode, responses[key], nil
+		}
+	}
+
+	return http.StatusNoContent, &openapi.ResponseObject{}, nil
+}
+
+func (o *operation) body(g *generator, mt *openapi.MediaTypeObject, exampleName string) ([]byte, error) {
+	var value any
+
+	switch {
+	case exampleName != "":
+		v, ok := namedExample(mt, exampleName)
+		if !ok {
+			return nil, &ErrScenarioNotDeclared{Scenario: "example " + exampleName}
+		}
+		value = v
+	default:
+		if v, ok := example(mt); ok {
+			value = v
+		} else if mt != nil {
+			value = g.generate(mt.Schema, 0)
+		}
+	}
+
+	if s, ok := value.(string); ok && (mt == nil || mt.Schema == nil || isStringSchema(mt.Schema)) {
+		return []byte(s), nil
+	}
+
+	return json.Marshal(value, json.Deterministic(true))
+}
+
+func (o *operation) validateRequest(req *http.Request) error {
+	var errs []error
+
+	for _, p := range o.op.Parameters {
+		values, ok := o.parameterValues(req, p)
+		if !ok {
+			if p.Required != nil && *p.Required {
+				errs = append(errs, validatorerrors.WrapLocation(
+					validatorerrors.PrefixJSONPointer(&validatorerrors.ErrMissingRequired{}, jsontext.Pointer("").AppendToken(p.Name)),
+					string(p.In),
+				))
+			}
+			continue
+		}
+
+		raw := o.server.parameterValue(p, values)
+
+		if err := o.parameters[p].Validate(raw); err != nil {
+			errs = append(errs, validatorerrors.WrapLocation(
+				validatorerrors.PrefixJSONPointer(err, jsontext.Pointer("").AppendToken(p.Name)),
+				string(p.In),
+			))
+		}
+	}
+
+	if err := o.validateBody(req); err != nil {
+		errs = append(errs, err)
+	}
+
+	return validatorerrors.Join(errs...)
+}
+
+func (o *operation) validateBody(req *http.Request) error {
+	rb := o.op.RequestBody
+	if rb == nil {
+		return nil
+	}
+
+	var body []byte
+	if req.Body != nil {
+		data, err := io.ReadAll(req.Body)
+		if err != nil {
+			return err
+		}
+		body = data
+	}
+
+	if len(body) == 0 {
+		if rb.Required {
+			return &ErrMissingRequestBody{}
+		}
+		return nil
+	}
+
+	contentType := req.Header.Get("Content-Type")
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+
+	v, ok := o.bodies[mediaType]
+	if !ok {
+		if _, ok := o.bodies["*/*"]; !ok {
+			return &ErrUnsupportedMediaType{ContentType: contentType}
+		}
+	}
+
+	if v == nil {
+		return nil
+	}
+
+	if err := v.Validate(body); err != nil {
+		if _, ok := err.(interface{ JSONPointer() jsontext.Pointer }); ok || isErrSet(err) {
+			return validatorerrors.WrapLocation(err, "body")
+		}
+		return &ErrInvalidRequestBody{Reason: err.Error()}
+	}
+
+	return nil
+}
+
+func isErrSet(err error) bool {
+	_, ok := err.(interface{ Unwrap() []error })
+	return ok
+}
+
+func (o *operation) parameterValues(req *http.Request, p *openapi.ParameterObject) ([]string, bool) {
+	var values []string
+
+	switch p.In {
+	case openapi.InPath:
+		if wildcard, ok := o.wildcards[p.Name]; ok {
+			if v := req.PathValue(wildcard); v != "" {
+				values = []string{v}
+			}
+		}
+	case openapi.InQuery:
+		values = req.URL.Query()[p.Name]
+	case openapi.InHeader:
+		values = req.Header.Values(p.Name)
+	case openapi.InCookie:
+		if c, err := req.Cookie(p.Name); err == nil {
+			values = []string{c.Value}
+		}
+	}
+
+	return values, len(values) > 0
+}
+
+// parameterValue 按参数 schema 将字符串值转换为 JSON 数据以便校验
+func (s *Server) parameterValue(p *openapi.ParameterObject, values []string) jsontext.Value {
+	schema := s.resolve(p.Schema)
+
+	if arr, ok := schema.(*jsonschema.ArrayType); ok {
+		if len(values) == 1 && p.Explode != nil && !*p.Explode {
+			values = strings.Split(values[0], ",")
+		}
+
+		items := make([]jsontext.Value, len(values))
+		for i, v := range values {
+			items[i] = scalarValue(s.resolve(arr.Items), v)
+		}
+
+		raw, _ := json.Marshal(items)
+		return raw
+	}
+
+	return scalarValue(schema, values[0])
+}
+
+func scalarValue(schema jsonschema.Schema, v string) jsontext.Value {
+	switch x := schema.(type) {
+	case *jsonschema.NumberType:
+		if _, err := strconv.ParseFloat(v, 64); err == nil {
+			return jsontext.Value(v)
+		}
+	case *jsonschema.BooleanType:
+		if v == "true" || v == "false" {
+			return jsontext.Value(v)
+		}
+	case *jsonschema.EnumType:
+		for _, e := range x.Enum {
+			if _, ok := e.(string); !ok && fmt.Sprint(e) == v {
+				return jsontext.Value(v)
+			}
+		}
+	}
+
+	raw, _ := jsontext.AppendQuote(nil, v)
+	return raw
+}
+
+// resolve 解析引用并跳过可空类型中的 null，用于判断参数的数据类型
+func (s *Server) resolve(schema jsonschema.Schema) jsonschema.Schema {
+	for range maxDepth {
+		switch x := schema.(type) {
+		case *jsonschema.RefType:
+			schema = s.doc.Schemas[x.RefName()]
+			continue
+		case *jsonschema.UnionType:
+			for _, sub := range x.OneOf {
+				if _, ok := sub.(*jsonschema.NullType); !ok {
+					schema = sub
+					break
+				}
+			}
+			continue
+		}
+		break
+	}
+	return schema
+}
+
+func (s *Server) seedOf(req *http.Request) uint64 {
+	if seed, err := strconv.ParseUint(req.Header.Get(HeaderMockSeed), 10, 64); err == nil {
+		return seed
+	}
+
+	h := fnv.New64a()
+	_, _ = io.WriteString(h, req.Method+" "+req.URL.Path)
+	return h.Sum64() ^ s.seed
+}
+
+func preferredContentType(resp *openapi.ResponseObject) string {
+	contentTypes := slices.Sorted(maps.Keys(resp.Content))
+
+	for _, ct := range contentTypes {
+		if isJSONMediaType(ct) {
+			return ct
+		}
+	}
+
+	return contentTypes[0]
+}
+
+func example(mt *openapi.MediaTypeObject) (any, bool) {
+	if mt == nil {
+		return nil, false
+	}
+
+	if v, ok := mt.GetExtension("example"); ok {
+		return v, true
+	}
+
+	if examples, ok := mt.GetExtension("examples"); ok {
+		if m, ok := examples.(map[string]any); ok && len(m) > 0 {
+			return namedExample(mt, slices.Sorted(maps.Keys(m))[0])
+		}
+	}
+
+	return nil, false
+}
+
+// namedExample 读取 `examples` 中具名 example 的 value
+func namedExample(mt *openapi.MediaTypeObject, name string) (any, bool) {
+	if mt == nil {
+		return nil, false
+	}
+
+	examples, ok := mt.GetExtension("examples")
+	if !ok {
+		return nil, false
+	}
+
+	m, ok := examples.(map[string]any)
+	if !ok {
+		return nil, false
+	}
+
+	e, ok := m[name].(map[string]any)
+	if !ok {
+		return nil, false
+	}
+
+	v, ok := e["value"]
+	return v, ok
+}
+
+func isJSONMediaType(contentType string) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	mediaType = cmp.Or(mediaType, contentType)
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+func isStringSchema(s jsonschema.Schema) bool {
+	_, ok := s.(*jsonschema.StringType)
+	return ok
+}
+
+func writeErr(rw http.ResponseWriter, req *http.Request, err error) {
+	_ = courierhttp.WrapError(err).(courierhttp.ResponseWriter).WriteResponse(req.Context(), rw, httprequest.From(req))
+}
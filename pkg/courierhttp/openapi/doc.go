@@ -1,5 +1,6 @@
 // Package openapi 根据 courier 路由构建 OpenAPI 文档。
 //
 // 这个包会扫描 operator 的输入、输出、错误声明与运行时文档，
-// 产出 `pkg/openapi` 中定义的 OpenAPI 3.1 对象模型。
+// 产出 `pkg/openapi` 中定义的 OpenAPI 3.1 对象模型；
+// ValidateResponses 可在运行时将实际响应与该文档比对，发现偏差。
 package openapi
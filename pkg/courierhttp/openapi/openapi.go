@@ -84,10 +84,18 @@ func (p PkgNamingPrefix) Register(pkgPath string, prefix string) {
 	p[pkgPath] = prefix
 }
 
+// WithVersion 仅输出指定 API 版本的操作，未声明版本的操作会出现在每个版本中。
+func WithVersion(version string) BuildOptionFunc {
+	return func(o *buildOption) {
+		o.version = version
+	}
+}
+
 type BuildOptionFunc func(o *buildOption)
 
 type buildOption struct {
-	naming func(t string) string
+	naming  func(t string) string
+	version string
 }
 
 func FromRouter(r courier.Router, fns ...BuildOptionFunc) *openapi.OpenAPI {
@@ -204,6 +212,10 @@ func (b *scanner) scan(r courier.Route) error {
 	}
 
 	for _, rh := range handlers {
+		if v := rh.Version().Version; v != "" && b.opt.version != "" && v != b.opt.version {
+			continue
+		}
+
 		op := openapi.NewOperation(rh.OperationID())
 
 		op.Summary = rh.Summary()
@@ -228,7 +240,8 @@ func (b *scanner) scan(r courier.Route) error {
 				b.scanResponse(ctx, op, o)
 			}
 
-			b.scanResponseError(ctx, op, o)
+			b.scanResponseError(ctx, op, o, rh.ErrorFormat())
+			b.scanCallbacksAndWebhooks(ctx, op, o.Operator)
 		}
 
 		b.o.AddOperation(rh.Method(), b.patchPath(rh.Path(), op), op)
@@ -301,13 +314,17 @@ func (b *scanner) SchemaFromType(ctx context.Context, v any, def bool) jsonschem
 }
 
 func (b *scanner) scanResponse(ctx context.Context, op *openapi.OperationObject, o *courier.OperatorFactory) {
+	b.scanResponseOf(ctx, op, o.Operator)
+}
+
+func (b *scanner) scanResponseOf(ctx context.Context, op *openapi.OperationObject, o any) {
 	method := ""
 
 	statusCode := http.StatusNoContent
 	contentType := "application/json"
 	resp := &openapi.ResponseObject{}
 
-	if can, ok := o.Operator.(courierhttp.MethodDescriber); ok {
+	if can, ok := o.(courierhttp.MethodDescriber); ok {
 		method = can.Method()
 
 		if method == http.MethodPost {
@@ -321,15 +338,15 @@ func (b *scanner) scanResponse(ctx context.Context, op *openapi.OperationObject,
 		return
 	}
 
-	if can, ok := o.Operator.(CanResponseStatusCode); ok {
+	if can, ok := o.(CanResponseStatusCode); ok {
 		statusCode = can.ResponseStatusCode()
 	}
 
-	if can, ok := o.Operator.(CanResponseContentType); ok {
+	if can, ok := o.(CanResponseContentType); ok {
 		contentType = can.ResponseContentType()
 	}
 
-	if can, ok := o.Operator.(CanResponseContent); ok {
+	if can, ok := responseContentOf(o); ok {
 		if rt := can.ResponseContent(); rt != nil {
 			if c, ok := rt.(courierhttp.ContentTypeDescriber); ok {
 				contentType = c.ContentType()
@@ -338,6 +355,13 @@ func (b *scanner) scanResponse(ctx context.Context, op *openapi.OperationObject,
 			mt := &openapi.MediaTypeObject{}
 			mt.Schema = b.SchemaFromType(ctx, rt, false)
 			resp.AddContent(contentType, mt)
+
+			if _, ok := rt.(courierhttp.LinksDescriber); ok {
+				resp.AddHeader("Link", &openapi.HeaderObject{
+					Schema:      jsonschema.String(),
+					Description: "RFC 8288 分页链接",
+				})
+			}
 		} else {
 			statusCode = http.StatusNoContent
 		}
@@ -348,7 +372,7 @@ func (b *scanner) scanResponse(ctx context.Context, op *openapi.OperationObject,
 	op.AddResponse(statusCode, resp)
 }
 
-func (b *scanner) scanResponseError(ctx context.Context, op *openapi.OperationObject, o *courier.OperatorFactory) {
+func (b *scanner) scanResponseError(ctx context.Context, op *openapi.OperationObject, o *courier.OperatorFactory, format courierhttp.ErrorFormat) {
 	if can, ok := o.Operator.(CanResponseErrors); ok {
 		returnErrors := can.ResponseErrors()
 
@@ -373,23 +397,40 @@ func (b *scanner) scanResponseError(ctx context.Context, op *openapi.OperationOb
 			}
 
 			mt := &openapi.MediaTypeObject{}
+			contentType := "application/json"
 
-			switch x := returnErrors[0].(type) {
-			case *statuserror.Descriptor:
+			if format == courierhttp.ErrorFormatProblemDetails {
+				contentType = statuserror.MediaTypeProblemJSON
 				mt.Schema = b.SchemaFromType(
 					ctx,
-					&statuserror.ErrorResponse{},
-					false,
-				)
-			default:
-				mt.Schema = b.SchemaFromType(
-					ctx,
-					x,
+					&statuserror.ProblemDetails{},
 					false,
 				)
+			} else {
+				switch x := returnErrors[0].(type) {
+				case *statuserror.Descriptor:
+					mt.Schema = b.SchemaFromType(
+						ctx,
+						&statuserror.ErrorResponse{},
+						false,
+					)
+				default:
+					mt.Schema = b.SchemaFromType(
+						ctx,
+						x,
+						false,
+					)
+				}
 			}
 
-			errResp.AddContent("application/json", mt)
+			errResp.AddContent(contentType, mt)
+
+			// 未声明格式时客户端仍可通过 Accept 协商为 problem details，一并声明
+			if format == courierhttp.ErrorFormatDefault {
+				errResp.AddContent(statuserror.MediaTypeProblemJSON, &openapi.MediaTypeObject{
+					Schema: b.SchemaFromType(ctx, &statuserror.ProblemDetails{}, false),
+				})
+			}
 
 			if found, ok := errResp.GetExtension("x-status-return-errors"); ok {
 				errResp.AddExtension("x-status-return-errors", append(found.([]string), codes[statusCode]...))
@@ -402,6 +443,87 @@ func (b *scanner) scanResponseError(ctx context.Context, op *openapi.OperationOb
 	}
 }
 
+// responseContentOf 兼容客户端请求类型以 ResponseData 声明的响应
+func responseContentOf(o any) (CanResponseContent, bool) {
+	if can, ok := o.(CanResponseContent); ok {
+		return can, true
+	}
+
+	if m := reflect.ValueOf(o).MethodByName("ResponseData"); m.IsValid() && m.Type().NumIn() == 0 && m.Type().NumOut() == 1 {
+		rt := m.Call(nil)[0].Interface()
+		if _, noContent := rt.(*courier.NoContent); noContent {
+			rt = nil
+		}
+		return responseContent{v: rt}, true
+	}
+
+	return nil, false
+}
+
+type responseContent struct {
+	v any
+}
+
+func (r responseContent) ResponseContent() any {
+	return r.v
+}
+
+func (b *scanner) scanCallbacksAndWebhooks(ctx context.Context, op *openapi.OperationObject, o courier.Operator) {
+	if can, ok := o.(courierhttp.CallbacksDescriber); ok {
+		for _, c := range can.Callbacks() {
+			if c.Request == nil {
+				panic(fmt.Errorf("操作 %s 的回调 %s 缺少请求原型", op.OperationId, c.Name))
+			}
+
+			method, callbackOp := b.scanRequest(ctx, c.Request)
+
+			item := &openapi.PathItemObject{}
+			item.Set(strings.ToLower(method), callbackOp)
+
+			op.AddCallback(c.Name, c.Expression, item)
+		}
+	}
+
+	if can, ok := o.(courierhttp.WebhooksDescriber); ok {
+		for _, w := range can.Webhooks() {
+			if w.Request == nil {
+				panic(fmt.Errorf("操作 %s 声明的 webhook %s 缺少请求原型", op.OperationId, w.Name))
+			}
+
+			method, webhookOp := b.scanRequest(ctx, w.Request)
+
+			b.o.AddWebhook(w.Name, method, webhookOp)
+		}
+	}
+}
+
+// scanRequest 将请求原型转换为 OpenAPI 操作
+func (b *scanner) scanRequest(ctx context.Context, req any) (string, *openapi.OperationObject) {
+	t := reflect.TypeOf(req)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	op := openapi.NewOperation(t.Name())
+
+	if canRuntimeDoc, ok := req.(CanRuntimeDoc); ok {
+		if doc, ok := canRuntimeDoc.RuntimeDoc(); ok && len(doc) > 0 {
+			op.Summary = doc[0]
+			op.Description = strings.Join(doc[1:], "\n")
+		}
+	}
+
+	method := http.MethodPost
+	if can, ok := req.(courierhttp.MethodDescriber); ok && can.Method() != "" {
+		method = can.Method()
+	}
+
+	b.scanParameterOrRequestBody(ctx, op, t)
+	b.scanResponseOf(ctx, op, req)
+
+	return method, op
+}
+
 type CanRuntimeDoc interface {
 	RuntimeDoc(names ...string) ([]string, bool)
 }
@@ -0,0 +1,358 @@
+package openapi
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-json-experiment/json/jsontext"
+	"github.com/octohelm/x/logr"
+
+	"github.com/octohelm/courier/internal/httprequest"
+	"github.com/octohelm/courier/pkg/courier"
+	"github.com/octohelm/courier/pkg/courierhttp"
+	"github.com/octohelm/courier/pkg/courierhttp/handler"
+	"github.com/octohelm/courier/pkg/openapi"
+	"github.com/octohelm/courier/pkg/openapi/jsonschema/compiler"
+	"github.com/octohelm/courier/pkg/statuserror"
+	"github.com/octohelm/courier/pkg/validator"
+)
+
+// ResponseDrift 描述实际响应与 OpenAPI 声明不一致之处。
+type ResponseDrift struct {
+	OperationID string
+	StatusCode  int
+	ContentType string
+	Reason      string
+}
+
+func (d *ResponseDrift) Error() string {
+	return fmt.Sprintf("response of %s (%d %s) drifted from openapi: %s", d.OperationID, d.StatusCode, d.ContentType, d.Reason)
+}
+
+// ErrResponseDrift 为开启 FailOnResponseDrift 后替换原响应的错误。
+type ErrResponseDrift struct {
+	statuserror.InternalServerError
+
+	Drift *ResponseDrift
+}
+
+func (e *ErrResponseDrift) Error() string {
+	return e.Drift.Error()
+}
+
+type ResponseValidationOptionFunc func(o *responseValidationOption)
+
+type responseValidationOption struct {
+	onDrift func(ctx context.Context, drift *ResponseDrift)
+	fail    bool
+	build   []BuildOptionFunc
+}
+
+// OnResponseDrift 设置发现响应偏差时的回调，默认输出告警日志。
+func OnResponseDrift(fn func(ctx context.Context, drift *ResponseDrift)) ResponseValidationOptionFunc {
+	return func(o *responseValidationOption) {
+		o.onDrift = fn
+	}
+}
+
+// FailOnResponseDrift 发现偏差且响应尚未写出时，以 500 替换原响应，适用于测试与预发环境。
+func FailOnResponseDrift() ResponseValidationOptionFunc {
+	return func(o *responseValidationOption) {
+		o.fail = true
+	}
+}
+
+// WithBuildOptions 设置构建 OpenAPI 文档时的选项。
+func WithBuildOptions(fns ...BuildOptionFunc) ResponseValidationOptionFunc {
+	return func(o *responseValidationOption) {
+		o.build = append(o.build, fns...)
+	}
+}
+
+// ValidateResponses 创建路由中间件，按 FromRouter 构建的 OpenAPI 文档校验每个响应的状态码、内容类型与响应体。
+//
+// 需作为路由中间件使用，以便通过 OperationInfo 找到对应 operation；
+// JSON 响应体会被缓冲后校验，其余响应仅校验状态码与内容类型后直接透传。
+func ValidateResponses(r courier.Router, fns ...ResponseValidationOptionFunc) handler.Middleware {
+	v := &responseValidator{router: r}
+
+	for _, fn := range fns {
+		fn(&v.opt)
+	}
+
+	if v.opt.onDrift == nil {
+		v.opt.onDrift = func(ctx context.Context, drift *ResponseDrift) {
+			logr.FromContext(ctx).WithValues("operation", drift.OperationID).Warn(drift)
+		}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			info, ok := courierhttp.OperationInfoFromContext(req.Context())
+			if !ok {
+				next.ServeHTTP(rw, req)
+				return
+			}
+
+			op, ok := v.operation(info.ID)
+			if !ok {
+				next.ServeHTTP(rw, req)
+				return
+			}
+
+			rec := &driftRecorder{
+				ResponseWriter: rw,
+				validator:      v,
+				req:            req,
+				op:             op,
+			}
+
+			next.ServeHTTP(rec, req)
+
+			rec.finish()
+		})
+	}
+}
+
+type responseValidator struct {
+	router courier.Router
+	opt    responseValidationOption
+
+	once       sync.Once
+	compiler   *compiler.Compiler
+	operations map[string]*openapi.OperationObject
+	validators sync.Map
+}
+
+func (v *responseValidator) operation(id string) (*openapi.OperationObject, bool) {
+	v.once.Do(func() {
+		doc := FromRouter(v.router, v.opt.build...)
+
+		v.compiler = compiler.New(compiler.WithSchemas(doc.Schemas))
+		v.operations = map[string]*openapi.OperationObject{}
+
+		for _, item := range doc.Paths.KeyValues() {
+			for _, op := range item.KeyValues() {
+				if _, ok := v.operations[op.OperationId]; !ok {
+					v.operations[op.OperationId] = op
+				}
+			}
+		}
+	})
+
+	op, ok := v.operations[id]
+	return op, ok
+}
+
+func (v *responseValidator) validatorOf(mt *openapi.MediaTypeObject) (validator.Validator, error) {
+	get, _ := v.validators.LoadOrStore(mt, sync.OnceValues(func() (validator.Validator, error) {
+		return v.compiler.Compile(mt.Schema)
+	}))
+	return get.(func() (validator.Validator, error))()
+}
+
+func (v *responseValidator) report(req *http.Request, drift *ResponseDrift) {
+	v.opt.onDrift(req.Context(), drift)
+}
+
+type driftRecorder struct {
+	http.ResponseWriter
+
+	validator *responseValidator
+	req       *http.Request
+	op        *openapi.OperationObject
+
+	wroteHeader bool
+	statusCode  int
+	contentType string
+	declared    *openapi.ResponseObject
+	mediaType   *openapi.MediaTypeObject
+	buffering   bool
+	discard     bool
+	written     int
+	body        bytes.Buffer
+}
+
+func (r *driftRecorder) drift(reason string) *ResponseDrift {
+	return &ResponseDrift{
+		OperationID: r.op.OperationId,
+		StatusCode:  r.statusCode,
+		ContentType: r.contentType,
+		Reason:      reason,
+	}
+}
+
+func (r *driftRecorder) WriteHeader(statusCode int) {
+	if r.wroteHeader {
+		return
+	}
+	r.wroteHeader = true
+	r.statusCode = statusCode
+	r.contentType = r.Header().Get("Content-Type")
+
+	if reason := r.checkHeader(); reason != "" {
+		if r.fail(r.drift(reason)) {
+			return
+		}
+	}
+
+	if r.mediaType != nil && isJSONMediaType(r.contentType) {
+		r.buffering = true
+		return
+	}
+
+	r.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (r *driftRecorder) Write(p []byte) (int, error) {
+	if !r.wroteHeader {
+		r.WriteHeader(http.StatusOK)
+	}
+
+	if r.discard {
+		return len(p), nil
+	}
+
+	if r.buffering {
+		return r.body.Write(p)
+	}
+
+	r.written += len(p)
+	return r.ResponseWriter.Write(p)
+}
+
+func (r *driftRecorder) Flush() {
+	if r.buffering || r.discard {
+		return
+	}
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (r *driftRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+func (r *driftRecorder) finish() {
+	if !r.wroteHeader || r.discard {
+		return
+	}
+
+	if !r.buffering {
+		if r.written > 0 && r.declared != nil && len(r.declared.Content) == 0 {
+			r.validator.report(r.req, r.drift("response body is not declared"))
+		}
+		return
+	}
+
+	if reason := r.checkBody(); reason != "" {
+		if r.fail(r.drift(reason)) {
+			return
+		}
+	}
+
+	r.ResponseWriter.WriteHeader(r.statusCode)
+	_, _ = r.ResponseWriter.Write(r.body.Bytes())
+}
+
+// fail 上报偏差，需要时以错误替换原响应，返回原响应是否已被替换
+func (r *driftRecorder) fail(drift *ResponseDrift) bool {
+	r.validator.report(r.req, drift)
+
+	if !r.validator.opt.fail {
+		return false
+	}
+
+	r.discard = true
+	r.Header().Del("Content-Length")
+
+	ctx := r.req.Context()
+	_ = courierhttp.WrapError(&ErrResponseDrift{Drift: drift}).(courierhttp.ResponseWriter).WriteResponse(ctx, r.ResponseWriter, httprequest.From(r.req))
+	return true
+}
+
+func (r *driftRecorder) checkHeader() string {
+	resp, ok := responseOf(r.op, r.statusCode)
+	if !ok {
+		return fmt.Sprintf("status code %d is not declared", r.statusCode)
+	}
+	r.declared = resp
+
+	if len(resp.Content) == 0 || r.contentType == "" {
+		return ""
+	}
+
+	mt, ok := mediaTypeOf(resp, r.contentType)
+	if !ok {
+		return fmt.Sprintf("content type %s is not declared", r.contentType)
+	}
+
+	r.mediaType = mt
+
+	return ""
+}
+
+func (r *driftRecorder) checkBody() string {
+	if r.body.Len() == 0 {
+		return ""
+	}
+
+	v, err := r.validator.validatorOf(r.mediaType)
+	if err != nil {
+		return fmt.Sprintf("invalid response schema: %s", err)
+	}
+
+	if err := v.Validate(jsontext.Value(r.body.Bytes())); err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
+func responseOf(op *openapi.OperationObject, statusCode int) (*openapi.ResponseObject, bool) {
+	code := strconv.Itoa(statusCode)
+
+	for _, key := range []string{code, code[0:1] + "XX", "default"} {
+		if resp, ok := op.Responses[key]; ok {
+			return resp, true
+		}
+	}
+
+	return nil, false
+}
+
+func mediaTypeOf(resp *openapi.ResponseObject, contentType string) (*openapi.MediaTypeObject, bool) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = contentType
+	}
+
+	for declared, mt := range resp.Content {
+		// 未声明 schema 的响应内容类型由 operator 运行时决定
+		if mt == nil || mt.Schema == nil {
+			return nil, true
+		}
+
+		d, _, err := mime.ParseMediaType(declared)
+		if err != nil {
+			d = declared
+		}
+
+		if d == mediaType || d == "*/*" || (strings.HasSuffix(d, "/*") && strings.HasPrefix(mediaType, d[0:len(d)-1])) {
+			return mt, true
+		}
+	}
+
+	return nil, false
+}
+
+func isJSONMediaType(contentType string) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
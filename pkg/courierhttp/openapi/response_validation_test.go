@@ -0,0 +1,235 @@
+package openapi_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	. "github.com/octohelm/x/testing/v2"
+
+	"github.com/octohelm/courier/pkg/courier"
+	"github.com/octohelm/courier/pkg/courierhttp"
+	"github.com/octohelm/courier/pkg/courierhttp/handler/httprouter"
+	"github.com/octohelm/courier/pkg/courierhttp/openapi"
+	"github.com/octohelm/courier/pkg/statuserror"
+)
+
+type validationOrg struct {
+	Name string `json:"name"`
+	Size int    `json:"size"`
+}
+
+type GetValidationOrg struct {
+	courierhttp.MethodGet `path:"/orgs/{name}"`
+
+	Name string `name:"name" in:"path"`
+}
+
+func (*GetValidationOrg) ResponseContent() any {
+	return &validationOrg{}
+}
+
+func (req *GetValidationOrg) Output(ctx context.Context) (any, error) {
+	return &validationOrg{Name: req.Name, Size: 1}, nil
+}
+
+type GetDriftedOrg struct {
+	courierhttp.MethodGet `path:"/drifted-orgs/{name}"`
+
+	Name string `name:"name" in:"path"`
+}
+
+func (*GetDriftedOrg) ResponseContent() any {
+	return &validationOrg{}
+}
+
+func (req *GetDriftedOrg) Output(ctx context.Context) (any, error) {
+	return map[string]any{"name": req.Name, "size": "large"}, nil
+}
+
+type DeleteValidationOrg struct {
+	courierhttp.MethodDelete `path:"/orgs/{name}"`
+
+	Name string `name:"name" in:"path"`
+}
+
+func (req *DeleteValidationOrg) Output(ctx context.Context) (any, error) {
+	return nil, &statuserror.Descriptor{
+		Code:    "Conflict",
+		Message: "org in use",
+		Status:  http.StatusConflict,
+	}
+}
+
+type ErrValidationOrgInUse struct {
+	statuserror.Conflict
+}
+
+func (*ErrValidationOrgInUse) Error() string {
+	return "org in use"
+}
+
+type ArchiveValidationOrg struct {
+	courierhttp.MethodPost `path:"/orgs/{name}/archive"`
+
+	Name string `name:"name" in:"path"`
+}
+
+func (ArchiveValidationOrg) ResponseErrors() []error {
+	return []error{
+		&ErrValidationOrgInUse{},
+	}
+}
+
+func (req *ArchiveValidationOrg) Output(ctx context.Context) (any, error) {
+	return nil, &ErrValidationOrgInUse{}
+}
+
+type driftCollector struct {
+	mu     sync.Mutex
+	drifts []*openapi.ResponseDrift
+}
+
+func (c *driftCollector) collect(ctx context.Context, drift *openapi.ResponseDrift) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.drifts = append(c.drifts, drift)
+}
+
+func (c *driftCollector) take() []*openapi.ResponseDrift {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	drifts := c.drifts
+	c.drifts = nil
+	return drifts
+}
+
+func TestValidateResponses(t *testing.T) {
+	r := courierhttp.GroupRouter("/").With(
+		courier.NewRouter(&GetValidationOrg{}),
+		courier.NewRouter(&GetDriftedOrg{}),
+		courier.NewRouter(&DeleteValidationOrg{}),
+		courier.NewRouter(&ArchiveValidationOrg{}),
+	)
+
+	serve := func(fns ...openapi.ResponseValidationOptionFunc) (func(method string, path string, headers ...string) *httptest.ResponseRecorder, *driftCollector) {
+		c := &driftCollector{}
+
+		h, err := httprouter.New(r, "test", openapi.ValidateResponses(r, append([]openapi.ResponseValidationOptionFunc{openapi.OnResponseDrift(c.collect)}, fns...)...))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return func(method string, path string, headers ...string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(method, path, nil)
+			for i := 0; i+1 < len(headers); i += 2 {
+				req.Header.Set(headers[i], headers[i+1])
+			}
+			rw := httptest.NewRecorder()
+			h.ServeHTTP(rw, req)
+			return rw
+		}, c
+	}
+
+	Then(
+		t, "符合声明的响应不会产生偏差",
+		ExpectMust(func() error {
+			do, c := serve()
+
+			rw := do(http.MethodGet, "/orgs/a")
+			if rw.Code != http.StatusOK || !strings.Contains(rw.Body.String(), `"name":"a"`) {
+				return fmt.Errorf("unexpected response %d: %s", rw.Code, rw.Body.String())
+			}
+			if drifts := c.take(); len(drifts) != 0 {
+				return errors.Join(errorsOf(drifts)...)
+			}
+			return nil
+		}),
+	)
+
+	Then(
+		t, "响应体与声明不一致时上报偏差并保留原响应",
+		ExpectMust(func() error {
+			do, c := serve()
+
+			rw := do(http.MethodGet, "/drifted-orgs/a")
+			if rw.Code != http.StatusOK || !strings.Contains(rw.Body.String(), `"size":"large"`) {
+				return fmt.Errorf("unexpected response %d: %s", rw.Code, rw.Body.String())
+			}
+
+			drifts := c.take()
+			if len(drifts) != 1 || drifts[0].OperationID != "GetDriftedOrg" || !strings.Contains(drifts[0].Reason, "/size") {
+				return fmt.Errorf("unexpected drifts %v", errorsOf(drifts))
+			}
+			return nil
+		}),
+	)
+
+	Then(
+		t, "未声明的错误状态码会被上报",
+		ExpectMust(func() error {
+			do, c := serve()
+
+			rw := do(http.MethodDelete, "/orgs/a")
+			if rw.Code != http.StatusConflict {
+				return fmt.Errorf("unexpected response %d: %s", rw.Code, rw.Body.String())
+			}
+
+			drifts := c.take()
+			if len(drifts) != 1 || drifts[0].StatusCode != http.StatusConflict {
+				return fmt.Errorf("unexpected drifts %v", errorsOf(drifts))
+			}
+			return nil
+		}),
+	)
+
+	Then(
+		t, "开启 FailOnResponseDrift 后偏差响应被替换为 500",
+		ExpectMust(func() error {
+			do, _ := serve(openapi.FailOnResponseDrift())
+
+			rw := do(http.MethodGet, "/drifted-orgs/a")
+			if rw.Code != http.StatusInternalServerError || !strings.Contains(rw.Body.String(), "drifted from openapi") {
+				return fmt.Errorf("unexpected response %d: %s", rw.Code, rw.Body.String())
+			}
+
+			rw = do(http.MethodGet, "/orgs/a")
+			if rw.Code != http.StatusOK {
+				return fmt.Errorf("unexpected response %d: %s", rw.Code, rw.Body.String())
+			}
+			return nil
+		}),
+	)
+
+	Then(
+		t, "按 Accept 协商的 problem details 错误响应符合声明",
+		ExpectMust(func() error {
+			do, c := serve(openapi.FailOnResponseDrift())
+
+			for _, accept := range []string{"", statuserror.MediaTypeProblemJSON} {
+				rw := do(http.MethodPost, "/orgs/a/archive", "Accept", accept)
+				if rw.Code != http.StatusConflict {
+					return fmt.Errorf("unexpected response %d: %s", rw.Code, rw.Body.String())
+				}
+			}
+
+			if drifts := c.take(); len(drifts) != 0 {
+				return errors.Join(errorsOf(drifts)...)
+			}
+			return nil
+		}),
+	)
+}
+
+func errorsOf(drifts []*openapi.ResponseDrift) []error {
+	errs := make([]error, 0, len(drifts))
+	for _, d := range drifts {
+		errs = append(errs, d)
+	}
+	return errs
+}
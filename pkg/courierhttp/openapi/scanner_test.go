@@ -217,8 +217,8 @@ func TestScannerHelpersAndFromRouter(t *testing.T) {
 			}
 
 			op := pkgopenapi.NewOperation("scan")
-			b.scanResponseError(context.Background(), op, courier.NewOperatorFactory(&scannerDescriptorErrOp{}, true))
-			b.scanResponseError(context.Background(), op, courier.NewOperatorFactory(&scannerDescriptorErrOp{}, true))
+			b.scanResponseError(context.Background(), op, courier.NewOperatorFactory(&scannerDescriptorErrOp{}, true), courierhttp.ErrorFormatDefault)
+			b.scanResponseError(context.Background(), op, courier.NewOperatorFactory(&scannerDescriptorErrOp{}, true), courierhttp.ErrorFormatDefault)
 			resp := op.Responses["400"]
 			if resp == nil {
 				return errScanner("missing repeated error response")
@@ -318,6 +318,18 @@ func TestScannerErrorMessages(t *testing.T) {
 			t.Fatalf("expected inner error context, got: %v", err)
 		}
 	})
+
+	t.Run("回调或 webhook 缺少请求原型时应指明名称", func(t *testing.T) {
+		err := captureScannerPanic(func() {
+			_ = FromRouter(courier.NewRouter(&scannerNilCallbackOp{}))
+		})
+		if err == nil {
+			t.Fatalf("expected panic error")
+		}
+		if !strings.Contains(err.Error(), "的回调 itemCreated 缺少请求原型") {
+			t.Fatalf("unexpected error message: %v", err)
+		}
+	})
 }
 
 func captureScannerPanic(fn func()) (err error) {
@@ -339,3 +351,85 @@ func captureScannerPanic(fn func()) (err error) {
 func errScanner(msg string) error {
 	return errors.New(msg)
 }
+
+type scannerSubscribeOp struct {
+	courierhttp.MethodPost `path:"/api/subscriptions"`
+
+	Body struct {
+		CallbackURL string `json:"callbackUrl"`
+	} `in:"body"`
+}
+
+func (*scannerSubscribeOp) Output(context.Context) (any, error) { return nil, nil }
+
+func (*scannerSubscribeOp) Callbacks() []courierhttp.Callback {
+	return []courierhttp.Callback{
+		{Name: "itemCreated", Expression: "{$request.body#/callbackUrl}", Request: &scannerItemCreatedCallback{}},
+	}
+}
+
+func (*scannerSubscribeOp) Webhooks() []courierhttp.Webhook {
+	return []courierhttp.Webhook{
+		{Name: "item.created", Request: &scannerItemCreatedCallback{}},
+	}
+}
+
+type scannerNilCallbackOp struct {
+	courierhttp.MethodPost `path:"/api/subscriptions"`
+}
+
+func (*scannerNilCallbackOp) Output(context.Context) (any, error) { return nil, nil }
+
+func (*scannerNilCallbackOp) Callbacks() []courierhttp.Callback {
+	return []courierhttp.Callback{
+		{Name: "itemCreated", Expression: "{$request.body#/callbackUrl}"},
+	}
+}
+
+type scannerItemCreatedCallback struct {
+	courierhttp.MethodPost
+
+	Signature string             `name:"Webhook-Signature" in:"header"`
+	Body      scannerRequestBody `in:"body"`
+}
+
+func (scannerItemCreatedCallback) ResponseData() *courier.NoContent {
+	return new(courier.NoContent)
+}
+
+func TestScannerCallbacksAndWebhooks(t *testing.T) {
+	o := FromRouter(courier.NewRouter(&scannerSubscribeOp{}))
+
+	must := func(ok bool, what string) {
+		t.Helper()
+		if !ok {
+			t.Fatalf("missing %s", what)
+		}
+	}
+
+	pathItem, ok := o.Paths.Get("/api/subscriptions")
+	must(ok, "path /api/subscriptions")
+	op, ok := pathItem.Get("post")
+	must(ok, "operation")
+
+	callback, ok := op.Callbacks.Get("itemCreated")
+	must(ok, "callback itemCreated")
+	callbackItem, ok := callback.Get("{$request.body#/callbackUrl}")
+	must(ok, "callback expression")
+	callbackOp, ok := callbackItem.Get("post")
+	must(ok, "callback operation")
+
+	webhookItem, ok := o.Webhooks.Get("item.created")
+	must(ok, "webhook item.created")
+	webhookOp, ok := webhookItem.Get("post")
+	must(ok, "webhook operation")
+
+	Then(t, "操作声明的回调与 webhook 带有完整的请求描述",
+		Expect(callbackOp.OperationId, Equal("scannerItemCreatedCallback")),
+		Expect(len(callbackOp.Parameters), Equal(1)),
+		Expect(callbackOp.RequestBody != nil, Equal(true)),
+		Expect(callbackOp.Responses["204"] != nil, Equal(true)),
+		Expect(webhookOp.OperationId, Equal("scannerItemCreatedCallback")),
+		Expect(webhookOp.RequestBody != nil, Equal(true)),
+	)
+}
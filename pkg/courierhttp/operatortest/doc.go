@@ -2,4 +2,10 @@
 //
 // `Serve` 会把一个 operator 包装成临时测试服务器，
 // 便于在不组装完整路由树的情况下验证请求解码与响应编码。
+//
+// `ServeRouter` 以整个 router 启动测试服务器，`Server.Snapshot` 依次执行用例并输出响应快照，
+// `RouteSnapshotFile` 与 `OpenAPISnapshotFile` 输出路由表与 OpenAPI 文档，
+// 配合 `MatchSnapshot` 与 testdata 下的黄金文件比较，用于发现非预期的接口变更。
+//
+// `Server.ApplyHttpTransport` 可挂载 `client/cassette` 录制的交互，在录制模式下记录对测试服务器的调用。
 package operatortest
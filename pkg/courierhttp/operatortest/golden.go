@@ -0,0 +1,145 @@
+package operatortest
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/go-json-experiment/json"
+	"github.com/go-json-experiment/json/jsontext"
+	testingv2 "github.com/octohelm/x/testing/v2"
+
+	"github.com/octohelm/courier/pkg/courier"
+	"github.com/octohelm/courier/pkg/courierhttp/client"
+	"github.com/octohelm/courier/pkg/courierhttp/handler/httprouter"
+	courierhttpopenapi "github.com/octohelm/courier/pkg/courierhttp/openapi"
+)
+
+// Case 为快照用例。
+type Case struct {
+	// 快照文件名，不含扩展名
+	Name string
+	// 请求 operator
+	Request  any
+	Metadata courier.Metadata
+}
+
+// Snapshot 依次执行 cases，每个用例输出为 `<Name>.http` 快照文件，
+// 内容为状态行、headers 指定的响应头与规范化后的 JSON 响应体。
+//
+// 配合 `MatchSnapshot` 与黄金文件比较：
+//
+//	Then(t, "接口响应与快照一致",
+//		ExpectMustValue(func() (Snapshot, error) {
+//			return s.Snapshot(ctx, cases, "Content-Type")
+//		}, MatchSnapshot("orgs")),
+//	)
+func (s *Server) Snapshot(ctx context.Context, cases []Case, headers ...string) (testingv2.Snapshot, error) {
+	files := make([]testingv2.SnapshotFile, 0, len(cases))
+
+	for _, x := range cases {
+		var resp *http.Response
+		var body []byte
+
+		c := &client.Client{
+			Endpoint: s.URL,
+			HttpTransports: append(slices.Clone(s.transports), client.HttpTransportFunc(func(req *http.Request, next client.RoundTrip) (*http.Response, error) {
+				r, err := next(req)
+				if err != nil {
+					return nil, err
+				}
+
+				// 保留原始响应体，非 2xx 响应同样输出
+				body, err = io.ReadAll(r.Body)
+				_ = r.Body.Close()
+				if err != nil {
+					return nil, err
+				}
+				r.Body = io.NopCloser(bytes.NewReader(body))
+				resp = r
+
+				return r, nil
+			})),
+		}
+
+		metas := make([]courier.Metadata, 0, 1)
+		if x.Metadata != nil {
+			metas = append(metas, x.Metadata)
+		}
+
+		if _, err := c.Do(ctx, x.Request, metas...).Into(nil); err != nil && resp == nil {
+			return nil, fmt.Errorf("%s: %w", x.Name, err)
+		}
+
+		files = append(files, testingv2.SnapshotFileFromRaw(x.Name+".http", dumpResponse(resp, body, headers)))
+	}
+
+	return testingv2.SnapshotOf(files...), nil
+}
+
+func dumpResponse(resp *http.Response, body []byte, headers []string) []byte {
+	b := bytes.NewBuffer(nil)
+
+	_, _ = fmt.Fprintf(b, "HTTP/1.1 %d %s\n", resp.StatusCode, http.StatusText(resp.StatusCode))
+
+	headers = slices.Clone(headers)
+	for i := range headers {
+		headers[i] = http.CanonicalHeaderKey(headers[i])
+	}
+	slices.Sort(headers)
+
+	for _, h := range headers {
+		for _, v := range resp.Header.Values(h) {
+			_, _ = fmt.Fprintf(b, "%s: %s\n", h, v)
+		}
+	}
+
+	if len(body) > 0 {
+		b.WriteString("\n")
+		b.Write(canonicalJSON(resp.Header.Get("Content-Type"), body))
+		b.WriteString("\n")
+	}
+
+	return b.Bytes()
+}
+
+// canonicalJSON 按键排序并缩进 JSON，非 JSON 内容原样返回
+func canonicalJSON(contentType string, data []byte) []byte {
+	if !strings.Contains(contentType, "json") {
+		return data
+	}
+
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return data
+	}
+
+	out, err := json.Marshal(v, json.Deterministic(true), jsontext.WithIndent("  "))
+	if err != nil {
+		return data
+	}
+
+	return out
+}
+
+// RouteSnapshotFile 返回 `httprouter.RouteSnapshot` 的快照文件 `routes.txt`。
+func RouteSnapshotFile(r courier.Router, service string) (testingv2.SnapshotFile, error) {
+	routes, err := httprouter.RouteSnapshot(r, service)
+	if err != nil {
+		return testingv2.SnapshotFile{}, err
+	}
+	return testingv2.SnapshotFileFromRaw("routes.txt", []byte(routes)), nil
+}
+
+// OpenAPISnapshotFile 返回生成的 OpenAPI 文档的快照文件 `openapi.json`，键按字典序排列以保证输出稳定。
+func OpenAPISnapshotFile(r courier.Router, opts ...courierhttpopenapi.BuildOptionFunc) (testingv2.SnapshotFile, error) {
+	data, err := json.Marshal(courierhttpopenapi.FromRouter(r, opts...))
+	if err != nil {
+		return testingv2.SnapshotFile{}, err
+	}
+	return testingv2.SnapshotFileFromRaw("openapi.json", canonicalJSON("application/json", data)), nil
+}
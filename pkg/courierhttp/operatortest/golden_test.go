@@ -0,0 +1,81 @@
+package operatortest
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/octohelm/x/testing/v2"
+
+	"github.com/octohelm/courier/pkg/courier"
+	"github.com/octohelm/courier/pkg/courierhttp"
+	"github.com/octohelm/courier/pkg/statuserror"
+)
+
+type orgStore map[string]string
+
+type orgStoreKey struct{}
+
+type testListOrgs struct {
+	courierhttp.MethodGet `path:"/orgs"`
+}
+
+func (req *testListOrgs) Output(ctx context.Context) (any, error) {
+	return ctx.Value(orgStoreKey{}).(orgStore), nil
+}
+
+type testGetOrg struct {
+	courierhttp.MethodGet `path:"/orgs/{name}"`
+
+	Name string `name:"name" in:"path"`
+}
+
+type ErrOrgNotFound struct {
+	statuserror.NotFound
+}
+
+func (ErrOrgNotFound) Error() string {
+	return "org not found"
+}
+
+func (req *testGetOrg) Output(ctx context.Context) (any, error) {
+	owner, ok := ctx.Value(orgStoreKey{}).(orgStore)[req.Name]
+	if !ok {
+		return nil, &ErrOrgNotFound{}
+	}
+	return map[string]string{"name": req.Name, "owner": owner}, nil
+}
+
+func TestGolden(t *testing.T) {
+	r := courier.NewRouter(courierhttp.Group("/"))
+	r.Register(courier.NewRouter(&testListOrgs{}))
+	r.Register(courier.NewRouter(&testGetOrg{}))
+
+	ctx := context.WithValue(context.Background(), orgStoreKey{}, orgStore{"a": "u1", "b": "u2"})
+
+	s := ServeRouter(ctx, r)
+	t.Cleanup(s.Close)
+
+	Then(t, "接口响应与快照一致",
+		ExpectMustValue(func() (Snapshot, error) {
+			return s.Snapshot(context.Background(), []Case{
+				{Name: "list", Request: &testListOrgs{}},
+				{Name: "get", Request: &testGetOrg{Name: "a"}},
+				{Name: "get_not_found", Request: &testGetOrg{Name: "x"}},
+			}, "Content-Type")
+		}, MatchSnapshot("orgs")),
+	)
+
+	Then(t, "路由表与 OpenAPI 文档与快照一致",
+		ExpectMustValue(func() (Snapshot, error) {
+			routes, err := RouteSnapshotFile(r, "test")
+			if err != nil {
+				return nil, err
+			}
+			oas, err := OpenAPISnapshotFile(r)
+			if err != nil {
+				return nil, err
+			}
+			return SnapshotOf(routes, oas), nil
+		}, MatchSnapshot("api")),
+	)
+}
@@ -16,6 +16,11 @@ func Serve(ctx context.Context, o courier.Operator, middlewares ...handler.Middl
 	r := courier.NewRouter(courierhttp.Group("/"))
 	r.Register(courier.NewRouter(o))
 
+	return ServeRouter(ctx, r, middlewares...)
+}
+
+// ServeRouter 将整个 router 包装成临时测试服务器，ctx 会注入每个请求，可用于注入 fake 依赖。
+func ServeRouter(ctx context.Context, r courier.Router, middlewares ...handler.Middleware) *Server {
 	h, err := httprouter.New(r, "test")
 	if err != nil {
 		panic(err)
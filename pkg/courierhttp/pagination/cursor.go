@@ -0,0 +1,81 @@
+package pagination
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+
+	"github.com/go-json-experiment/json"
+
+	"github.com/octohelm/courier/pkg/statuserror"
+)
+
+// NewCursorCodec 创建游标编解码器，secret 非空时游标附带 HMAC-SHA256 签名以防篡改。
+func NewCursorCodec(secret []byte) *CursorCodec {
+	return &CursorCodec{secret: secret}
+}
+
+// CursorCodec 将游标值编码为不透明的 URL 安全字符串。
+type CursorCodec struct {
+	secret []byte
+}
+
+// Encode 编码游标值。
+func (c *CursorCodec) Encode(v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	cursor := base64.RawURLEncoding.EncodeToString(data)
+
+	if len(c.secret) > 0 {
+		cursor += "." + base64.RawURLEncoding.EncodeToString(c.sign(data))
+	}
+
+	return cursor, nil
+}
+
+// Decode 解码游标值，游标格式错误或签名不匹配时返回 ErrInvalidCursor。
+func (c *CursorCodec) Decode(cursor string, v any) error {
+	payload, sig, signed := strings.Cut(cursor, ".")
+
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return &ErrInvalidCursor{Cursor: cursor}
+	}
+
+	if len(c.secret) > 0 {
+		if !signed {
+			return &ErrInvalidCursor{Cursor: cursor}
+		}
+
+		expect, err := base64.RawURLEncoding.DecodeString(sig)
+		if err != nil || !hmac.Equal(expect, c.sign(data)) {
+			return &ErrInvalidCursor{Cursor: cursor}
+		}
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return &ErrInvalidCursor{Cursor: cursor}
+	}
+
+	return nil
+}
+
+func (c *CursorCodec) sign(data []byte) []byte {
+	h := hmac.New(sha256.New, c.secret)
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+type ErrInvalidCursor struct {
+	statuserror.BadRequest
+
+	Cursor string
+}
+
+func (e *ErrInvalidCursor) Error() string {
+	return "无效的分页游标"
+}
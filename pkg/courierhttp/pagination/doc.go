@@ -0,0 +1,7 @@
+// Package pagination 提供游标分页与偏移分页的通用列表响应类型。
+//
+// 响应类型会根据当前请求自动输出 RFC 8288 `Link` 头（first/prev/next），
+// 并实现 `courier.Pageable`，可配合 `courier.DoPages` 在客户端遍历全部分页。
+//
+// +gengo:runtimedoc=false
+package pagination
@@ -0,0 +1,90 @@
+package pagination
+
+import (
+	"net/url"
+	"strconv"
+
+	"github.com/octohelm/courier/pkg/courierhttp"
+)
+
+// OffsetParams 为偏移分页的请求参数，可匿名嵌入 operator。
+type OffsetParams struct {
+	// 偏移量
+	Offset int64 `name:"offset,omitzero" in:"query"`
+	// 每页数量
+	Limit int64 `name:"limit,omitzero" in:"query"`
+}
+
+func (p *OffsetParams) SetPageToken(token string) {
+	if offset, err := strconv.ParseInt(token, 10, 64); err == nil {
+		p.Offset = offset
+	}
+}
+
+// Offset 为偏移分页的列表响应。
+type Offset[T any] struct {
+	// 当前页数据
+	Items []T `json:"items"`
+	// 总数
+	Total int64 `json:"total"`
+	// 偏移量
+	Offset int64 `json:"offset"`
+	// 每页数量
+	Limit int64 `json:"limit,omitzero"`
+}
+
+func (p *Offset[T]) PageItems() []T {
+	return p.Items
+}
+
+func (p *Offset[T]) NextPageToken() string {
+	if next, ok := p.next(); ok {
+		return strconv.FormatInt(next, 10)
+	}
+	return ""
+}
+
+func (p *Offset[T]) next() (int64, bool) {
+	n := int64(len(p.Items))
+	if n == 0 {
+		return 0, false
+	}
+	if next := p.Offset + n; next < p.Total {
+		return next, true
+	}
+	return 0, false
+}
+
+func (p *Offset[T]) Links(u *url.URL) []courierhttp.Link {
+	limit := p.Limit
+	if limit <= 0 {
+		limit = int64(len(p.Items))
+	}
+
+	links := []courierhttp.Link{
+		{Target: withQuery(u, QueryOffset, ""), Rel: "first"},
+	}
+
+	if p.Offset > 0 && limit > 0 {
+		prev := max(p.Offset-limit, 0)
+		links = append(links, courierhttp.Link{Target: withQuery(u, QueryOffset, offsetValue(prev)), Rel: "prev"})
+	}
+
+	if next, ok := p.next(); ok {
+		links = append(links, courierhttp.Link{Target: withQuery(u, QueryOffset, offsetValue(next)), Rel: "next"})
+	}
+
+	if p.Total > 0 && limit > 0 {
+		last := (p.Total - 1) / limit * limit
+		links = append(links, courierhttp.Link{Target: withQuery(u, QueryOffset, offsetValue(last)), Rel: "last"})
+	}
+
+	return links
+}
+
+func offsetValue(offset int64) string {
+	if offset == 0 {
+		return ""
+	}
+	return strconv.FormatInt(offset, 10)
+}
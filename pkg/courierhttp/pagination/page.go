@@ -0,0 +1,78 @@
+package pagination
+
+import (
+	"net/url"
+
+	"github.com/octohelm/courier/pkg/courierhttp"
+)
+
+const (
+	QueryCursor = "cursor"
+	QueryOffset = "offset"
+	QueryLimit  = "limit"
+)
+
+// CursorParams 为游标分页的请求参数，可匿名嵌入 operator。
+type CursorParams struct {
+	// 分页游标
+	Cursor string `name:"cursor,omitzero" in:"query"`
+	// 每页数量
+	Limit int64 `name:"limit,omitzero" in:"query"`
+}
+
+func (p *CursorParams) SetPageToken(token string) {
+	p.Cursor = token
+}
+
+// Page 为游标分页的列表响应。
+type Page[T any] struct {
+	// 当前页数据
+	Items []T `json:"items"`
+	// 下一页游标，为空表示已到末页
+	NextCursor string `json:"nextCursor,omitzero"`
+	// 上一页游标
+	PrevCursor string `json:"prevCursor,omitzero"`
+}
+
+func (p *Page[T]) PageItems() []T {
+	return p.Items
+}
+
+func (p *Page[T]) NextPageToken() string {
+	return p.NextCursor
+}
+
+func (p *Page[T]) Links(u *url.URL) []courierhttp.Link {
+	links := []courierhttp.Link{
+		{Target: withQuery(u, QueryCursor, ""), Rel: "first"},
+	}
+
+	if p.PrevCursor != "" {
+		links = append(links, courierhttp.Link{Target: withQuery(u, QueryCursor, p.PrevCursor), Rel: "prev"})
+	}
+
+	if p.NextCursor != "" {
+		links = append(links, courierhttp.Link{Target: withQuery(u, QueryCursor, p.NextCursor), Rel: "next"})
+	}
+
+	return links
+}
+
+// withQuery 返回替换了查询参数 key 的相对地址，value 为空时移除该参数。
+func withQuery(u *url.URL, key string, value string) string {
+	query := u.Query()
+
+	if value == "" {
+		query.Del(key)
+	} else {
+		query.Set(key, value)
+	}
+
+	next := &url.URL{
+		Path:     u.Path,
+		RawPath:  u.RawPath,
+		RawQuery: query.Encode(),
+	}
+
+	return next.String()
+}
@@ -0,0 +1,254 @@
+package pagination_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"testing"
+
+	. "github.com/octohelm/x/testing/v2"
+
+	"github.com/octohelm/courier/pkg/courier"
+	"github.com/octohelm/courier/pkg/courierhttp"
+	"github.com/octohelm/courier/pkg/courierhttp/client"
+	"github.com/octohelm/courier/pkg/courierhttp/handler/httprouter"
+	courierhttpopenapi "github.com/octohelm/courier/pkg/courierhttp/openapi"
+	"github.com/octohelm/courier/pkg/courierhttp/pagination"
+)
+
+var codec = pagination.NewCursorCodec([]byte("secret"))
+
+var orgs = []string{"a", "b", "c", "d", "e"}
+
+type ListOrgs struct {
+	courierhttp.MethodGet `path:"/orgs"`
+
+	pagination.CursorParams
+}
+
+func (ListOrgs) ResponseData() *pagination.Page[string] {
+	return new(pagination.Page[string])
+}
+
+func (ListOrgs) ResponseContent() any {
+	return new(pagination.Page[string])
+}
+
+func (req *ListOrgs) Output(ctx context.Context) (any, error) {
+	offset := 0
+	if req.Cursor != "" {
+		if err := codec.Decode(req.Cursor, &offset); err != nil {
+			return nil, err
+		}
+	}
+
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = 2
+	}
+
+	end := min(offset+limit, len(orgs))
+
+	page := &pagination.Page[string]{
+		Items: orgs[offset:end],
+	}
+
+	if end < len(orgs) {
+		page.NextCursor, _ = codec.Encode(end)
+	}
+
+	if offset > 0 {
+		page.PrevCursor, _ = codec.Encode(max(offset-limit, 0))
+	}
+
+	return page, nil
+}
+
+type ListOrgsByOffset struct {
+	courierhttp.MethodGet `path:"/orgs-by-offset"`
+
+	pagination.OffsetParams
+}
+
+func (ListOrgsByOffset) ResponseData() *pagination.Offset[string] {
+	return new(pagination.Offset[string])
+}
+
+func (req *ListOrgsByOffset) Output(ctx context.Context) (any, error) {
+	limit := req.Limit
+	if limit <= 0 {
+		limit = 2
+	}
+
+	end := min(req.Offset+limit, int64(len(orgs)))
+
+	return &pagination.Offset[string]{
+		Items:  orgs[req.Offset:end],
+		Total:  int64(len(orgs)),
+		Offset: req.Offset,
+		Limit:  limit,
+	}, nil
+}
+
+func TestPagination(t *testing.T) {
+	r := courierhttp.GroupRouter("/").With(
+		courier.NewRouter(&ListOrgs{}),
+		courier.NewRouter(&ListOrgsByOffset{}),
+	)
+
+	h, err := httprouter.New(r, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	c := &client.Client{Endpoint: srv.URL}
+
+	Then(
+		t, "游标分页输出 Link 头",
+		ExpectMust(func() error {
+			cursor, _ := codec.Encode(2)
+
+			resp, err := http.Get(srv.URL + "/orgs?cursor=" + url.QueryEscape(cursor))
+			if err != nil {
+				return err
+			}
+			_ = resp.Body.Close()
+
+			rels := map[string]string{}
+			for _, l := range courierhttp.ParseLinks(resp.Header.Values("Link")...) {
+				rels[l.Rel] = l.Target
+			}
+
+			if rels["first"] != "/orgs" {
+				return fmt.Errorf("unexpected first link %q", rels["first"])
+			}
+			if rels["prev"] == "" || rels["next"] == "" {
+				return fmt.Errorf("missing prev or next link %v", rels)
+			}
+			return nil
+		}),
+		ExpectMust(func() error {
+			resp, err := http.Get(srv.URL + "/orgs?cursor=forged")
+			if err != nil {
+				return err
+			}
+			_ = resp.Body.Close()
+
+			if resp.StatusCode != http.StatusBadRequest {
+				return fmt.Errorf("unexpected status %d", resp.StatusCode)
+			}
+			return nil
+		}),
+	)
+
+	Then(
+		t, "OpenAPI 描述分页响应的 Link 头",
+		ExpectMust(func() error {
+			oas := courierhttpopenapi.FromRouter(r)
+
+			pathItem, ok := oas.Paths.Get("/orgs")
+			if !ok {
+				return fmt.Errorf("missing path")
+			}
+
+			op, ok := pathItem.Get("get")
+			if !ok {
+				return fmt.Errorf("missing operation")
+			}
+
+			resp := op.Responses["200"]
+			if resp == nil || resp.Headers["Link"] == nil {
+				return fmt.Errorf("missing link header")
+			}
+			return nil
+		}),
+	)
+
+	Then(
+		t, "客户端可遍历全部分页",
+		ExpectMust(func() error {
+			items := make([]string, 0)
+			for item, err := range courier.DoPages[string](context.Background(), c, &ListOrgs{}) {
+				if err != nil {
+					return err
+				}
+				items = append(items, item)
+			}
+			if !slices.Equal(items, orgs) {
+				return fmt.Errorf("unexpected items %v", items)
+			}
+			return nil
+		}),
+		ExpectMust(func() error {
+			items := make([]string, 0)
+			for item, err := range courier.DoPages[string](context.Background(), c, &ListOrgsByOffset{}) {
+				if err != nil {
+					return err
+				}
+				items = append(items, item)
+				if len(items) == 3 {
+					break
+				}
+			}
+			if !slices.Equal(items, orgs[:3]) {
+				return fmt.Errorf("unexpected items %v", items)
+			}
+			return nil
+		}),
+	)
+}
+
+func TestOffsetLinks(t *testing.T) {
+	u, _ := url.Parse("/orgs?offset=2&limit=2&q=x")
+
+	p := &pagination.Offset[string]{
+		Items:  []string{"c", "d"},
+		Total:  5,
+		Offset: 2,
+		Limit:  2,
+	}
+
+	Then(
+		t, "偏移分页链接与下一页标记",
+		Expect(p.Links(u), Equal([]courierhttp.Link{
+			{Target: "/orgs?limit=2&q=x", Rel: "first"},
+			{Target: "/orgs?limit=2&q=x", Rel: "prev"},
+			{Target: "/orgs?limit=2&offset=4&q=x", Rel: "next"},
+			{Target: "/orgs?limit=2&offset=4&q=x", Rel: "last"},
+		})),
+		Expect(p.NextPageToken(), Equal("4")),
+		Expect((&pagination.Offset[string]{Items: []string{"e"}, Total: 5, Offset: 4}).NextPageToken(), Equal("")),
+	)
+}
+
+func TestCursorCodec(t *testing.T) {
+	Then(
+		t, "签名游标可往返且拒绝篡改",
+		ExpectMust(func() error {
+			cursor, err := codec.Encode(map[string]any{"id": 1})
+			if err != nil {
+				return err
+			}
+
+			v := map[string]any{}
+			if err := codec.Decode(cursor, &v); err != nil {
+				return err
+			}
+
+			unsigned, _ := pagination.NewCursorCodec(nil).Encode(map[string]any{"id": 2})
+			if err := codec.Decode(unsigned, &v); err == nil {
+				return fmt.Errorf("unsigned cursor should be rejected")
+			}
+			if err := pagination.NewCursorCodec(nil).Decode(unsigned, &v); err != nil {
+				return err
+			}
+			return nil
+		}),
+	)
+}
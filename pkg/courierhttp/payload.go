@@ -49,11 +49,7 @@ type WithHeader interface {
 }
 
 // FileHeader 表示上传文件的头部信息。
-type FileHeader interface {
-	io.ReadCloser
-	Filename() string
-	Header() http.Header
-}
+type FileHeader = content.FileHeader
 
 type RequestInfo = httprequest.Request
 
@@ -235,9 +231,22 @@ func (r *response[T]) WriteResponse(ctx context.Context, rw http.ResponseWriter,
 	resp := r.v
 
 	if err, ok := resp.(error); ok {
-		opInfo, _ := OperationInfoFromContext(ctx)
+		source := ""
+		if opInfo, ok := OperationInfoFromContext(ctx); ok {
+			source = opInfo.Server.UserAgent()
+		}
 
-		resp = statuserror.AsErrorResponse(err, opInfo.Server.UserAgent())
+		errResp := statuserror.AsLocalizedErrorResponse(err, source, localizerFor(ctx, req))
+
+		switch resolveErrorFormat(ctx, req) {
+		case ErrorFormatProblemDetails:
+			resp = errResp.ProblemDetails(req.Path())
+			if r.contentType == "" {
+				r.SetContentType(statuserror.MediaTypeProblemJSON)
+			}
+		default:
+			resp = errResp
+		}
 	}
 
 	if statusCodeDescriber, ok := resp.(StatusCodeDescriber); ok {
@@ -278,6 +287,14 @@ func (r *response[T]) WriteResponse(ctx context.Context, rw http.ResponseWriter,
 		}
 	}
 
+	if linksDescriber, ok := resp.(LinksDescriber); ok {
+		if u := req.Underlying().URL; u != nil {
+			for _, l := range linksDescriber.Links(u) {
+				rw.Header().Add("Link", l.String())
+			}
+		}
+	}
+
 	if r.cookies != nil {
 		for i := range r.cookies {
 			cookie := r.cookies[i]
@@ -0,0 +1,92 @@
+package courierhttp
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+
+	"github.com/octohelm/x/logr"
+
+	"github.com/octohelm/courier/pkg/statuserror"
+)
+
+// ErrPanic 表示处理请求时发生了 panic。
+//
+// 仅在调试模式下对外暴露 panic 值与调用栈。
+type ErrPanic struct {
+	statuserror.InternalServerError
+
+	Value any
+	Stack []byte
+
+	debug bool
+}
+
+func (e *ErrPanic) Error() string {
+	if e.debug {
+		return fmt.Sprintf("panic: %v", e.Value)
+	}
+	return "internal server error"
+}
+
+func (e *ErrPanic) Description() string {
+	if e.debug {
+		return string(e.Stack)
+	}
+	return ""
+}
+
+// CrashReporter 接收请求处理中发生的 panic，可对接错误上报服务。
+type CrashReporter interface {
+	ReportCrash(ctx context.Context, info OperationInfo, err *ErrPanic)
+}
+
+type contextCrashReporter struct{}
+
+// ContextWithCrashReporter 注入 panic 上报器。
+func ContextWithCrashReporter(ctx context.Context, r CrashReporter) context.Context {
+	return context.WithValue(ctx, contextCrashReporter{}, r)
+}
+
+// CrashReporterFromContext 获取 panic 上报器。
+func CrashReporterFromContext(ctx context.Context) (CrashReporter, bool) {
+	r, ok := ctx.Value(contextCrashReporter{}).(CrashReporter)
+	return r, ok
+}
+
+type contextDebugMode struct{}
+
+// ContextWithDebugMode 设置是否处于调试模式，调试模式下错误响应会包含 panic 调用栈。
+func ContextWithDebugMode(ctx context.Context, debug bool) context.Context {
+	return context.WithValue(ctx, contextDebugMode{}, debug)
+}
+
+// DebugModeFromContext 返回是否处于调试模式。
+func DebugModeFromContext(ctx context.Context) bool {
+	debug, _ := ctx.Value(contextDebugMode{}).(bool)
+	return debug
+}
+
+// Recovered 将 recover 得到的值转换为 ErrPanic，并记录日志与上报。
+func Recovered(ctx context.Context, v any) *ErrPanic {
+	err := &ErrPanic{
+		Value: v,
+		Stack: debug.Stack(),
+		debug: DebugModeFromContext(ctx),
+	}
+
+	info := OperationInfo{}
+	if opInfo, ok := OperationInfoFromContext(ctx); ok {
+		info = *opInfo
+	}
+
+	logr.FromContext(ctx).WithValues("operation", info.ID, "route", info.Route).Error(
+		fmt.Errorf("panic: %v\n%s", v, err.Stack),
+	)
+
+	if r, ok := CrashReporterFromContext(ctx); ok {
+		r.ReportCrash(ctx, info, err)
+	}
+
+	return err
+}
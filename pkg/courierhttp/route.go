@@ -51,8 +51,11 @@ func Group(p string) courier.Operator {
 }
 
 type metaOperator struct {
-	path     string
-	basePath string
+	path        string
+	basePath    string
+	errorFormat ErrorFormat
+	version     *VersionInfo
+	versioning  *Versioning
 	courier.EmptyOperator
 }
 
@@ -64,7 +67,17 @@ func (g *metaOperator) BasePath() string {
 	return g.basePath
 }
 
+func (g *metaOperator) ErrorFormat() ErrorFormat {
+	return g.errorFormat
+}
+
 func (g *metaOperator) String() string {
+	if g.version != nil || g.versioning != nil {
+		return g.versionString()
+	}
+	if g.errorFormat != "" {
+		return fmt.Sprintf("errorFormat(%s)", g.errorFormat)
+	}
 	if g.basePath != "" {
 		return fmt.Sprintf("basePath(%s)", g.basePath)
 	}
@@ -0,0 +1,9 @@
+// Package static 提供以 `fs.FS`（含 `embed.FS`）为来源的静态文件与 SPA 服务 operator。
+//
+// `Static` 挂载在 `{path...}` 路由上，按扩展名设置 Content-Type，
+// 以内容哈希生成 ETag，对带哈希的资源文件名输出 immutable 缓存头，
+// 并在客户端支持时优先返回预压缩的 `.br`/`.gz` 文件。
+// 目录请求返回目录下的 `index.html`，未带末尾斜杠时先重定向；启用 SPA 模式时未命中的页面路径回退到根 `index.html`。
+//
+// +gengo:runtimedoc=false
+package static
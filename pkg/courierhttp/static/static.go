@@ -0,0 +1,284 @@
+package static
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/octohelm/courier/pkg/courier"
+	"github.com/octohelm/courier/pkg/courierhttp"
+	"github.com/octohelm/courier/pkg/statuserror"
+)
+
+const (
+	CacheControlImmutable = "public, max-age=31536000, immutable"
+	CacheControlNoCache   = "no-cache"
+)
+
+type ErrNotFound struct {
+	statuserror.NotFound
+
+	Path string
+}
+
+func (e *ErrNotFound) Error() string {
+	return fmt.Sprintf("file %q not found", e.Path)
+}
+
+type OptionFunc func(o *option)
+
+type option struct {
+	index     string
+	spa       bool
+	immutable func(name string) bool
+}
+
+// WithIndex 设置目录索引文件名，默认为 `index.html`。
+func WithIndex(index string) OptionFunc {
+	return func(o *option) {
+		o.index = index
+	}
+}
+
+// WithSPA 启用 SPA 模式，未命中且不带扩展名的路径回退到根索引文件。
+func WithSPA() OptionFunc {
+	return func(o *option) {
+		o.spa = true
+	}
+}
+
+// WithImmutable 自定义哪些文件名视为带内容哈希，可长期缓存。
+func WithImmutable(immutable func(name string) bool) OptionFunc {
+	return func(o *option) {
+		o.immutable = immutable
+	}
+}
+
+var reHashedName = regexp.MustCompile(`[-.]([A-Za-z0-9_]{8,})\.[A-Za-z0-9]+$`)
+
+// IsHashedName 判断文件名是否带有构建工具生成的内容哈希，如 `app-3f2a9c1d.js`、`main.8e1b0c2f.css`。
+func IsHashedName(name string) bool {
+	m := reHashedName.FindStringSubmatch(path.Base(name))
+	return m != nil && strings.ContainsAny(m[1], "0123456789")
+}
+
+// New 创建以 fsys 为来源的静态文件 operator。
+func New(fsys fs.FS, opts ...OptionFunc) *Static {
+	s := &Static{
+		fsys: fsys,
+		opt: option{
+			index:     "index.html",
+			immutable: IsHashedName,
+		},
+		etags: &sync.Map{},
+	}
+	for _, opt := range opts {
+		opt(&s.opt)
+	}
+	return s
+}
+
+// Static 提供静态文件服务。
+type Static struct {
+	courierhttp.MethodGet `path:"/{path...}"`
+
+	Path string `name:"path,omitzero" in:"path"`
+
+	fsys  fs.FS
+	opt   option
+	etags *sync.Map
+}
+
+func (s *Static) InitFrom(o courier.Operator) {
+	if x, ok := o.(*Static); ok {
+		s.fsys = x.fsys
+		s.opt = x.opt
+		s.etags = x.etags
+	}
+}
+
+func (Static) ResponseErrors() []error {
+	return []error{
+		&ErrNotFound{},
+	}
+}
+
+func (s *Static) Output(ctx context.Context) (any, error) {
+	return s, nil
+}
+
+func (s *Static) Upgrade(rw http.ResponseWriter, req *http.Request) error {
+	name, cacheControl, dir, err := s.resolve(s.Path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return &ErrNotFound{Path: s.Path}
+		}
+		return err
+	}
+
+	// 与 http.FileServer 一致，目录请求补全末尾斜杠，以便索引文件中的相对路径正确解析
+	if dir && !strings.HasSuffix(req.URL.Path, "/") {
+		target := path.Base(req.URL.Path) + "/"
+		if req.URL.RawQuery != "" {
+			target += "?" + req.URL.RawQuery
+		}
+		http.Redirect(rw, req, target, http.StatusMovedPermanently)
+		return nil
+	}
+
+	encoding, encoded := s.negotiateEncoding(name, req.Header.Get("Accept-Encoding"))
+
+	f, err := s.fsys.Open(encoded)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	content, ok := f.(io.ReadSeeker)
+	if !ok {
+		data, err := io.ReadAll(f)
+		if err != nil {
+			return err
+		}
+		content = bytes.NewReader(data)
+	}
+
+	etag, err := s.etag(encoded, info, content)
+	if err != nil {
+		return err
+	}
+
+	header := rw.Header()
+
+	// 未知扩展名时由 http.ServeContent 嗅探内容类型
+	if ct := mime.TypeByExtension(path.Ext(name)); ct != "" {
+		header.Set("Content-Type", ct)
+	}
+	header.Set("Cache-Control", cacheControl)
+	header.Add("Vary", "Accept-Encoding")
+	header.Set("ETag", etag)
+	if encoding != "" {
+		header.Set("Content-Encoding", encoding)
+	}
+
+	http.ServeContent(rw, req, name, info.ModTime(), content)
+	return nil
+}
+
+// resolve 返回实际读取的文件名与缓存策略，dir 表示请求路径为目录，返回的是其索引文件
+func (s *Static) resolve(p string) (name string, cacheControl string, dir bool, err error) {
+	name = strings.TrimPrefix(path.Clean("/"+p), "/")
+	if name == "" {
+		name = "."
+	}
+
+	info, err := fs.Stat(s.fsys, name)
+	if err == nil {
+		if !info.IsDir() {
+			cacheControl := CacheControlNoCache
+			if s.opt.immutable != nil && s.opt.immutable(name) {
+				cacheControl = CacheControlImmutable
+			}
+			return name, cacheControl, false, nil
+		}
+
+		index := path.Join(name, s.opt.index)
+		if _, err := fs.Stat(s.fsys, index); err == nil {
+			return index, CacheControlNoCache, true, nil
+		}
+	}
+
+	if s.opt.spa && path.Ext(name) == "" {
+		if _, err := fs.Stat(s.fsys, s.opt.index); err == nil {
+			return s.opt.index, CacheControlNoCache, false, nil
+		}
+	}
+
+	if err == nil {
+		err = fs.ErrNotExist
+	}
+
+	return "", "", false, err
+}
+
+var encodings = []struct {
+	name string
+	ext  string
+}{
+	{name: "br", ext: ".br"},
+	{name: "gzip", ext: ".gz"},
+}
+
+func (s *Static) negotiateEncoding(name string, acceptEncoding string) (string, string) {
+	accepted := map[string]bool{}
+	for part := range strings.SplitSeq(acceptEncoding, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if v, err := strconv.ParseFloat(q, 64); err == nil && v == 0 {
+				continue
+			}
+		}
+		accepted[strings.ToLower(strings.TrimSpace(coding))] = true
+	}
+
+	for _, e := range encodings {
+		if !accepted[e.name] {
+			continue
+		}
+		if info, err := fs.Stat(s.fsys, name+e.ext); err == nil && !info.IsDir() {
+			return e.name, name + e.ext
+		}
+	}
+
+	return "", name
+}
+
+type etagEntry struct {
+	size    int64
+	modTime time.Time
+	etag    string
+}
+
+// etag 按文件大小与修改时间缓存内容哈希，计算后将 content 复位至开头
+func (s *Static) etag(name string, info fs.FileInfo, content io.ReadSeeker) (string, error) {
+	if v, ok := s.etags.Load(name); ok {
+		if e := v.(*etagEntry); e.size == info.Size() && e.modTime.Equal(info.ModTime()) {
+			return e.etag, nil
+		}
+	}
+
+	h := sha256.New()
+	if _, err := io.Copy(h, content); err != nil {
+		return "", err
+	}
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+
+	e := &etagEntry{
+		size:    info.Size(),
+		modTime: info.ModTime(),
+		etag:    `"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`,
+	}
+	s.etags.Store(name, e)
+
+	return e.etag, nil
+}
@@ -0,0 +1,95 @@
+package static_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	. "github.com/octohelm/x/testing/v2"
+
+	"github.com/octohelm/courier/pkg/courier"
+	"github.com/octohelm/courier/pkg/courierhttp"
+	"github.com/octohelm/courier/pkg/courierhttp/handler/httprouter"
+	"github.com/octohelm/courier/pkg/courierhttp/static"
+)
+
+func TestStatic(t *testing.T) {
+	fsys := fstest.MapFS{
+		"index.html":                   {Data: []byte("<html>app</html>")},
+		"docs/index.html":              {Data: []byte("<html>docs</html>")},
+		"assets/app-3f2a9c1d.js":       {Data: []byte("console.log(1)")},
+		"assets/app-3f2a9c1d.js.br":    {Data: []byte("br-content")},
+		"assets/app-3f2a9c1d.js.gz":    {Data: []byte("gz-content")},
+		"assets/style.css":             {Data: []byte("body{}")},
+		"assets/settings-dialog.js":    {Data: []byte("dialog")},
+		"assets/settings-dialog.js.gz": {Data: []byte("dialog-gz")},
+	}
+
+	h, err := httprouter.New(courierhttp.GroupRouter("/").With(
+		courier.NewRouter(courierhttp.Group("/app"), static.New(fsys, static.WithSPA())),
+	), "test")
+	Then(t, "构建 httprouter handler 成功", Expect(err, Equal[error](nil)))
+
+	serve := func(path string, header http.Header) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		for k, vs := range header {
+			req.Header[k] = vs
+		}
+		rw := httptest.NewRecorder()
+		h.ServeHTTP(rw, req)
+		return rw
+	}
+
+	css := serve("/app/assets/style.css", nil)
+	Then(t, "按扩展名输出 Content-Type，未带哈希的文件不长期缓存",
+		Expect(css.Code, Equal(http.StatusOK)),
+		Expect(css.Header().Get("Content-Type"), Equal("text/css; charset=utf-8")),
+		Expect(css.Header().Get("Cache-Control"), Equal(static.CacheControlNoCache)),
+		Expect(css.Body.String(), Equal("body{}")),
+	)
+
+	Then(t, "携带匹配的 If-None-Match 时返回 304",
+		Expect(serve("/app/assets/style.css", http.Header{"If-None-Match": {css.Header().Get("ETag")}}).Code, Equal(http.StatusNotModified)),
+	)
+
+	br := serve("/app/assets/app-3f2a9c1d.js", http.Header{"Accept-Encoding": {"gzip, br"}})
+	Then(t, "带哈希的资源使用 immutable 缓存并优先返回预压缩文件",
+		Expect(br.Header().Get("Cache-Control"), Equal(static.CacheControlImmutable)),
+		Expect(br.Header().Get("Content-Encoding"), Equal("br")),
+		Expect(br.Header().Get("Content-Type"), Equal("text/javascript; charset=utf-8")),
+		Expect(br.Body.String(), Equal("br-content")),
+	)
+
+	Then(t, "按 Accept-Encoding 选择压缩格式",
+		Expect(serve("/app/assets/app-3f2a9c1d.js", http.Header{"Accept-Encoding": {"gzip, br;q=0"}}).Body.String(), Equal("gz-content")),
+		Expect(serve("/app/assets/app-3f2a9c1d.js", nil).Body.String(), Equal("console.log(1)")),
+	)
+
+	Then(t, "目录请求返回索引文件",
+		Expect(serve("/app/docs/", nil).Body.String(), Equal("<html>docs</html>")),
+	)
+
+	dir := serve("/app/docs?lang=zh", nil)
+	Then(t, "未带末尾斜杠的目录请求重定向",
+		Expect(dir.Code, Equal(http.StatusMovedPermanently)),
+		Expect(dir.Header().Get("Location"), Equal("/app/docs/?lang=zh")),
+	)
+
+	spa := serve("/app/orgs/1/settings", nil)
+	Then(t, "SPA 模式下页面路径回退到根索引",
+		Expect(spa.Code, Equal(http.StatusOK)),
+		Expect(spa.Body.String(), Equal("<html>app</html>")),
+		Expect(spa.Header().Get("Cache-Control"), Equal(static.CacheControlNoCache)),
+	)
+
+	Then(t, "不存在的资源文件返回 404",
+		Expect(serve("/app/assets/missing.js", nil).Code, Equal(http.StatusNotFound)),
+	)
+
+	Then(t, "识别带内容哈希的文件名",
+		Expect(static.IsHashedName("assets/app-3f2a9c1d.js"), Equal(true)),
+		Expect(static.IsHashedName("main.8e1b0c2f.css"), Equal(true)),
+		Expect(static.IsHashedName("assets/settings-dialog.js"), Equal(false)),
+	)
+}
@@ -0,0 +1,15 @@
+// Package tus 提供 tus 1.0 可续传上传协议的服务端 operator。
+//
+// `NewRouter` 挂载以下接口，并在所有响应中输出 `Tus-Resumable`：
+//
+//	OPTIONS /        协议发现，返回支持的版本与扩展
+//	POST    /        创建上传（creation / creation-defer-length）
+//	HEAD    /{id}    查询已接收的偏移量
+//	PATCH   /{id}    从 `Upload-Offset` 处追加数据
+//	DELETE  /{id}    终止上传（termination）
+//
+// 上传在创建时设定过期时间（expiration），过期后返回 410。
+// 数据存取通过 `Store` 抽象，`NewFSStore` 提供本地文件系统实现。
+//
+// +gengo:runtimedoc=false
+package tus
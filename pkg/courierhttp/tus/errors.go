@@ -0,0 +1,87 @@
+package tus
+
+import (
+	"fmt"
+
+	"github.com/octohelm/courier/pkg/statuserror"
+)
+
+type ErrUnsupportedVersion struct {
+	statuserror.PreconditionFailed
+
+	Version string
+}
+
+func (e *ErrUnsupportedVersion) Error() string {
+	return fmt.Sprintf("unsupported tus version %q, only %s is supported", e.Version, Version)
+}
+
+type ErrUploadNotFound struct {
+	statuserror.NotFound
+
+	ID string
+}
+
+func (e *ErrUploadNotFound) Error() string {
+	return fmt.Sprintf("upload %s not found", e.ID)
+}
+
+type ErrUploadExpired struct {
+	statuserror.Gone
+
+	ID string
+}
+
+func (e *ErrUploadExpired) Error() string {
+	return fmt.Sprintf("upload %s expired", e.ID)
+}
+
+type ErrUploadLocked struct {
+	statuserror.Locked
+
+	ID string
+}
+
+func (e *ErrUploadLocked) Error() string {
+	return fmt.Sprintf("upload %s is being written by another request", e.ID)
+}
+
+type ErrOffsetMismatch struct {
+	statuserror.Conflict
+
+	ID       string
+	Offset   int64
+	Expected int64
+}
+
+func (e *ErrOffsetMismatch) Error() string {
+	return fmt.Sprintf("upload %s offset mismatch, got %d, expect %d", e.ID, e.Offset, e.Expected)
+}
+
+type ErrUploadTooLarge struct {
+	statuserror.RequestEntityTooLarge
+
+	MaxSize int64
+}
+
+func (e *ErrUploadTooLarge) Error() string {
+	return fmt.Sprintf("upload exceeds the limit of %d bytes", e.MaxSize)
+}
+
+type ErrInvalidUploadLength struct {
+	statuserror.BadRequest
+}
+
+func (e *ErrInvalidUploadLength) Error() string {
+	return "one of Upload-Length or Upload-Defer-Length: 1 is required"
+}
+
+type ErrInvalidContentType struct {
+	statuserror.UnsupportedMediaType
+
+	ContentType string
+}
+
+func (e *ErrInvalidContentType) Error() string {
+	return fmt.Sprintf("content type must be %s, got %q", ContentTypeOffsetOctetStream, e.ContentType)
+}
@@ -0,0 +1,51 @@
+package tus
+
+import (
+	"encoding/base64"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Metadata 对应 `Upload-Metadata` 头，格式为逗号分隔的 `key base64(value)`。
+type Metadata map[string]string
+
+func ParseMetadata(s string) (Metadata, error) {
+	m := Metadata{}
+
+	for pair := range strings.SplitSeq(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		key, encoded, _ := strings.Cut(pair, " ")
+
+		value, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of upload metadata %q: %w", key, err)
+		}
+
+		m[key] = string(value)
+	}
+
+	return m, nil
+}
+
+func (m Metadata) String() string {
+	b := &strings.Builder{}
+
+	for i, key := range slices.Sorted(maps.Keys(m)) {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		b.WriteString(key)
+		if v := m[key]; v != "" {
+			b.WriteString(" ")
+			b.WriteString(base64.StdEncoding.EncodeToString([]byte(v)))
+		}
+	}
+
+	return b.String()
+}
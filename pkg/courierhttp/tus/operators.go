@@ -0,0 +1,296 @@
+package tus
+
+import (
+	"context"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+
+	"github.com/octohelm/courier/pkg/courier"
+	"github.com/octohelm/courier/pkg/courierhttp"
+)
+
+// CreateUpload 创建上传，`Location` 响应头为后续 PATCH 的地址。
+type CreateUpload struct {
+	courierhttp.MethodPost
+
+	// 上传总字节数
+	UploadLength string `name:"Upload-Length,omitzero" in:"header"`
+	// 为 1 时延迟声明总字节数
+	UploadDeferLength string `name:"Upload-Defer-Length,omitzero" in:"header"`
+	// 上传元数据
+	UploadMetadata string `name:"Upload-Metadata,omitzero" in:"header"`
+
+	server *server
+}
+
+func (c *CreateUpload) InitFrom(o courier.Operator) {
+	if x, ok := o.(*CreateUpload); ok {
+		c.server = x.server
+	}
+}
+
+func (CreateUpload) ResponseErrors() []error {
+	return []error{
+		&ErrInvalidUploadLength{},
+		&ErrUploadTooLarge{},
+	}
+}
+
+func (c *CreateUpload) Output(ctx context.Context) (any, error) {
+	u := &Upload{Length: -1}
+
+	switch {
+	case c.UploadLength != "":
+		n, err := strconv.ParseInt(c.UploadLength, 10, 64)
+		if err != nil || n < 0 {
+			return nil, &ErrInvalidUploadLength{}
+		}
+		if c.server.maxSize > 0 && n > c.server.maxSize {
+			return nil, &ErrUploadTooLarge{MaxSize: c.server.maxSize}
+		}
+		u.Length = n
+	case c.UploadDeferLength != "1":
+		return nil, &ErrInvalidUploadLength{}
+	}
+
+	if c.UploadMetadata != "" {
+		m, err := ParseMetadata(c.UploadMetadata)
+		if err != nil {
+			return nil, err
+		}
+		u.Metadata = m
+	}
+
+	if c.server.expiration > 0 {
+		u.ExpiresAt = c.server.now().Add(c.server.expiration)
+	}
+
+	if err := c.server.store.Create(ctx, u); err != nil {
+		return nil, err
+	}
+
+	if u.IsComplete() && c.server.onComplete != nil {
+		if err := c.server.onComplete(ctx, u); err != nil {
+			return nil, err
+		}
+	}
+
+	location := u.ID
+	if req, ok := courierhttp.RequestFromContext(ctx); ok {
+		location = path.Join(req.URL.Path, u.ID)
+	}
+
+	return courierhttp.Wrap[any](
+		nil,
+		courierhttp.WithStatusCode(http.StatusCreated),
+		courierhttp.WithMetadata("Location", location),
+		withUploadExpires(u),
+	), nil
+}
+
+// GetUploadOffset 查询上传已接收的偏移量。
+type GetUploadOffset struct {
+	courierhttp.MethodHead `path:"/{id}"`
+
+	ID string `name:"id" in:"path"`
+
+	server *server
+}
+
+func (g *GetUploadOffset) InitFrom(o courier.Operator) {
+	if x, ok := o.(*GetUploadOffset); ok {
+		g.server = x.server
+	}
+}
+
+func (GetUploadOffset) ResponseErrors() []error {
+	return []error{
+		&ErrUploadNotFound{},
+		&ErrUploadExpired{},
+	}
+}
+
+func (g *GetUploadOffset) Output(ctx context.Context) (any, error) {
+	u, err := g.server.get(ctx, g.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []courierhttp.ResponseSettingFunc{
+		courierhttp.WithStatusCode(http.StatusOK),
+		courierhttp.WithMetadata("Cache-Control", "no-store"),
+		courierhttp.WithMetadata(HeaderUploadOffset, strconv.FormatInt(u.Offset, 10)),
+		withUploadExpires(u),
+	}
+
+	if u.IsDeferred() {
+		opts = append(opts, courierhttp.WithMetadata(HeaderUploadDeferLength, "1"))
+	} else {
+		opts = append(opts, courierhttp.WithMetadata(HeaderUploadLength, strconv.FormatInt(u.Length, 10)))
+	}
+
+	if len(u.Metadata) > 0 {
+		opts = append(opts, courierhttp.WithMetadata(HeaderUploadMetadata, u.Metadata.String()))
+	}
+
+	return courierhttp.Wrap[any](nil, opts...), nil
+}
+
+// AppendUpload 从 `Upload-Offset` 处追加上传数据。
+type AppendUpload struct {
+	courierhttp.MethodPatch `path:"/{id}"`
+
+	ID string `name:"id" in:"path"`
+	// 须为 application/offset+octet-stream
+	ContentType string `name:"Content-Type,omitzero" in:"header"`
+	// 本次数据的起始偏移量，须与服务端已接收的字节数一致
+	UploadOffset int64 `name:"Upload-Offset" in:"header"`
+	// 延迟声明总字节数的上传可在此声明
+	UploadLength string `name:"Upload-Length,omitzero" in:"header"`
+
+	Body io.ReadCloser `in:"body" mime:"octet"`
+
+	server *server
+}
+
+func (a *AppendUpload) InitFrom(o courier.Operator) {
+	if x, ok := o.(*AppendUpload); ok {
+		a.server = x.server
+	}
+}
+
+func (AppendUpload) ResponseErrors() []error {
+	return []error{
+		&ErrInvalidContentType{},
+		&ErrInvalidUploadLength{},
+		&ErrUploadNotFound{},
+		&ErrUploadExpired{},
+		&ErrUploadLocked{},
+		&ErrOffsetMismatch{},
+		&ErrUploadTooLarge{},
+	}
+}
+
+func (a *AppendUpload) Output(ctx context.Context) (any, error) {
+	defer a.Body.Close()
+
+	if mediaType, _, _ := mime.ParseMediaType(a.ContentType); mediaType != ContentTypeOffsetOctetStream {
+		return nil, &ErrInvalidContentType{ContentType: a.ContentType}
+	}
+
+	u, err := a.server.get(ctx, a.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.Offset != a.UploadOffset {
+		return nil, &ErrOffsetMismatch{ID: u.ID, Offset: a.UploadOffset, Expected: u.Offset}
+	}
+
+	if u.IsDeferred() && a.UploadLength != "" {
+		n, err := strconv.ParseInt(a.UploadLength, 10, 64)
+		if err != nil || n < u.Offset {
+			return nil, &ErrInvalidUploadLength{}
+		}
+		if a.server.maxSize > 0 && n > a.server.maxSize {
+			return nil, &ErrUploadTooLarge{MaxSize: a.server.maxSize}
+		}
+		if err := a.server.store.DeclareLength(ctx, u.ID, n); err != nil {
+			return nil, err
+		}
+		u.Length = n
+	}
+
+	var r io.Reader = a.Body
+
+	switch {
+	case !u.IsDeferred():
+		r = &limitedReader{r: r, n: u.Length - u.Offset, maxSize: u.Length}
+	case a.server.maxSize > 0:
+		r = &limitedReader{r: r, n: a.server.maxSize - u.Offset, maxSize: a.server.maxSize}
+	}
+
+	n, writeErr := a.server.store.WriteChunk(ctx, u.ID, u.Offset, r)
+	u.Offset += n
+
+	// 请求体超长时数据本身已完整，仍视为上传完成
+	if n > 0 && u.IsComplete() && a.server.onComplete != nil {
+		if err := a.server.onComplete(ctx, u); err != nil {
+			return nil, err
+		}
+	}
+
+	if writeErr != nil {
+		return nil, writeErr
+	}
+
+	return courierhttp.Wrap[any](
+		nil,
+		courierhttp.WithStatusCode(http.StatusNoContent),
+		courierhttp.WithMetadata(HeaderUploadOffset, strconv.FormatInt(u.Offset, 10)),
+		withUploadExpires(u),
+	), nil
+}
+
+// TerminateUpload 终止上传并删除已接收的数据。
+type TerminateUpload struct {
+	courierhttp.MethodDelete `path:"/{id}"`
+
+	ID string `name:"id" in:"path"`
+
+	server *server
+}
+
+func (t *TerminateUpload) InitFrom(o courier.Operator) {
+	if x, ok := o.(*TerminateUpload); ok {
+		t.server = x.server
+	}
+}
+
+func (TerminateUpload) ResponseErrors() []error {
+	return []error{
+		&ErrUploadNotFound{},
+		&ErrUploadLocked{},
+	}
+}
+
+func (t *TerminateUpload) Output(ctx context.Context) (any, error) {
+	if _, err := t.server.store.Get(ctx, t.ID); err != nil {
+		return nil, err
+	}
+
+	if err := t.server.store.Terminate(ctx, t.ID); err != nil {
+		return nil, err
+	}
+
+	return courierhttp.Wrap[any](nil, courierhttp.WithStatusCode(http.StatusNoContent)), nil
+}
+
+// limitedReader 读取超过 n 字节时返回 *ErrUploadTooLarge，超出部分不会交给存储。
+type limitedReader struct {
+	r       io.Reader
+	n       int64
+	maxSize int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.n <= 0 {
+		var b [1]byte
+		n, err := l.r.Read(b[:])
+		if n > 0 {
+			return 0, &ErrUploadTooLarge{MaxSize: l.maxSize}
+		}
+		return 0, err
+	}
+
+	if int64(len(p)) > l.n {
+		p = p[:l.n]
+	}
+
+	n, err := l.r.Read(p)
+	l.n -= int64(n)
+	return n, err
+}
@@ -0,0 +1,49 @@
+package tus
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// Store 表示上传数据的存储。
+//
+// 实现需保证同一个上传的 `WriteChunk`、`DeclareLength` 与 `Terminate` 互斥，并发操作时返回 *ErrUploadLocked。
+type Store interface {
+	// Create 创建上传并分配 ID。
+	Create(ctx context.Context, u *Upload) error
+	// Get 获取上传状态，不存在时返回 *ErrUploadNotFound。
+	Get(ctx context.Context, id string) (*Upload, error)
+	// WriteChunk 在 offset 处追加数据，返回写入的字节数。
+	// 读取 r 中途出错时，已写入的部分仍需计入偏移量，以便客户端续传。
+	WriteChunk(ctx context.Context, id string, offset int64, r io.Reader) (int64, error)
+	// DeclareLength 为延迟声明长度的上传设定总长度。
+	DeclareLength(ctx context.Context, id string, length int64) error
+	// Terminate 删除上传及其数据。
+	Terminate(ctx context.Context, id string) error
+}
+
+// Upload 表示一次上传的状态。
+type Upload struct {
+	ID string `json:"id"`
+	// 总长度，延迟声明时为 -1
+	Length int64 `json:"length"`
+	// 已接收的字节数
+	Offset int64 `json:"offset"`
+	// 客户端通过 `Upload-Metadata` 携带的元数据
+	Metadata Metadata `json:"metadata,omitzero"`
+	// 过期时间，零值表示不过期
+	ExpiresAt time.Time `json:"expiresAt,omitzero"`
+}
+
+func (u *Upload) IsDeferred() bool {
+	return u.Length < 0
+}
+
+func (u *Upload) IsComplete() bool {
+	return !u.IsDeferred() && u.Offset == u.Length
+}
+
+func (u *Upload) IsExpired(now time.Time) bool {
+	return !u.ExpiresAt.IsZero() && !now.Before(u.ExpiresAt)
+}
@@ -0,0 +1,256 @@
+package tus
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-json-experiment/json"
+)
+
+// NewFSStore 创建以本地目录 dir 保存上传的存储。
+//
+// 每个上传对应 `<id>` 数据文件与 `<id>.info` 状态文件。
+func NewFSStore(dir string) *FSStore {
+	return &FSStore{dir: dir}
+}
+
+type FSStore struct {
+	dir string
+
+	mu      sync.Mutex
+	writing map[string]bool
+}
+
+var _ Store = &FSStore{}
+
+func (s *FSStore) Create(ctx context.Context, u *Upload) error {
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return err
+	}
+
+	id, err := newID()
+	if err != nil {
+		return err
+	}
+	u.ID = id
+
+	f, err := os.OpenFile(s.dataPath(id), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	_ = f.Close()
+
+	return s.save(u)
+}
+
+func (s *FSStore) Get(ctx context.Context, id string) (*Upload, error) {
+	if !validID(id) {
+		return nil, &ErrUploadNotFound{ID: id}
+	}
+
+	data, err := os.ReadFile(s.infoPath(id))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, &ErrUploadNotFound{ID: id}
+		}
+		return nil, err
+	}
+
+	u := &Upload{}
+	if err := json.Unmarshal(data, u); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+func (s *FSStore) WriteChunk(ctx context.Context, id string, offset int64, r io.Reader) (int64, error) {
+	if !s.lock(id) {
+		return 0, &ErrUploadLocked{ID: id}
+	}
+	defer s.unlock(id)
+
+	u, err := s.Get(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+
+	if u.Offset != offset {
+		return 0, &ErrOffsetMismatch{ID: id, Offset: offset, Expected: u.Offset}
+	}
+
+	f, err := os.OpenFile(s.dataPath(id), os.O_WRONLY, 0o644)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	// 丢弃上次中断时写入但未计入偏移量的数据
+	if err := f.Truncate(offset); err != nil {
+		return 0, err
+	}
+
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	n, copyErr := io.Copy(f, r)
+
+	if n > 0 {
+		if err := f.Sync(); err != nil {
+			return 0, err
+		}
+
+		u.Offset += n
+		if err := s.save(u); err != nil {
+			return 0, err
+		}
+	}
+
+	return n, copyErr
+}
+
+func (s *FSStore) DeclareLength(ctx context.Context, id string, length int64) error {
+	if !s.lock(id) {
+		return &ErrUploadLocked{ID: id}
+	}
+	defer s.unlock(id)
+
+	u, err := s.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	u.Length = length
+
+	return s.save(u)
+}
+
+func (s *FSStore) Terminate(ctx context.Context, id string) error {
+	if !validID(id) {
+		return &ErrUploadNotFound{ID: id}
+	}
+
+	if !s.lock(id) {
+		return &ErrUploadLocked{ID: id}
+	}
+	defer s.unlock(id)
+
+	if err := os.Remove(s.infoPath(id)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	if err := os.Remove(s.dataPath(id)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+// Open 打开上传的数据文件，用于读取已完成的上传。
+func (s *FSStore) Open(ctx context.Context, id string) (*os.File, error) {
+	if _, err := s.Get(ctx, id); err != nil {
+		return nil, err
+	}
+	return os.Open(s.dataPath(id))
+}
+
+// PurgeExpired 删除在 now 之前过期的上传。
+func (s *FSStore) PurgeExpired(ctx context.Context, now time.Time) error {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+
+	for _, e := range entries {
+		id, ok := strings.CutSuffix(e.Name(), ".info")
+		if !ok {
+			continue
+		}
+
+		u, err := s.Get(ctx, id)
+		if err != nil {
+			continue
+		}
+
+		if u.IsExpired(now) {
+			// 正在写入的上传留待下次清理
+			if err := s.Terminate(ctx, id); err != nil && !errors.As(err, new(*ErrUploadLocked)) {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *FSStore) lock(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.writing == nil {
+		s.writing = map[string]bool{}
+	}
+
+	if s.writing[id] {
+		return false
+	}
+	s.writing[id] = true
+	return true
+}
+
+func (s *FSStore) unlock(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.writing, id)
+}
+
+func (s *FSStore) save(u *Upload) error {
+	data, err := json.Marshal(u)
+	if err != nil {
+		return err
+	}
+
+	// 先写临时文件再替换，避免中断时留下不完整的状态
+	tmp := s.infoPath(u.ID) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.infoPath(u.ID))
+}
+
+func (s *FSStore) dataPath(id string) string {
+	return filepath.Join(s.dir, id)
+}
+
+func (s *FSStore) infoPath(id string) string {
+	return filepath.Join(s.dir, id+".info")
+}
+
+func newID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func validID(id string) bool {
+	if len(id) != 32 {
+		return false
+	}
+	_, err := hex.DecodeString(id)
+	return err == nil
+}
@@ -0,0 +1,165 @@
+package tus
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/octohelm/courier/internal/httprequest"
+	"github.com/octohelm/courier/pkg/courier"
+	"github.com/octohelm/courier/pkg/courierhttp"
+)
+
+const (
+	// Version 为支持的协议版本。
+	Version = "1.0.0"
+	// ContentTypeOffsetOctetStream 为 PATCH 请求体的媒体类型。
+	ContentTypeOffsetOctetStream = "application/offset+octet-stream"
+
+	HeaderTusResumable      = "Tus-Resumable"
+	HeaderTusVersion        = "Tus-Version"
+	HeaderTusExtension      = "Tus-Extension"
+	HeaderTusMaxSize        = "Tus-Max-Size"
+	HeaderUploadOffset      = "Upload-Offset"
+	HeaderUploadLength      = "Upload-Length"
+	HeaderUploadDeferLength = "Upload-Defer-Length"
+	HeaderUploadMetadata    = "Upload-Metadata"
+	HeaderUploadExpires     = "Upload-Expires"
+)
+
+var extensions = []string{"creation", "creation-defer-length", "termination", "expiration"}
+
+type OptionFunc func(s *server)
+
+// WithMaxSize 限制单个上传的最大字节数。
+func WithMaxSize(maxSize int64) OptionFunc {
+	return func(s *server) {
+		s.maxSize = maxSize
+	}
+}
+
+// WithExpiration 设置上传自创建起的有效期，默认 24 小时，为 0 时不过期。
+func WithExpiration(ttl time.Duration) OptionFunc {
+	return func(s *server) {
+		s.expiration = ttl
+	}
+}
+
+// WithOnComplete 设置上传完成时的回调，如将文件移交给后续处理；返回错误时 PATCH 请求失败。
+func WithOnComplete(fn func(ctx context.Context, u *Upload) error) OptionFunc {
+	return func(s *server) {
+		s.onComplete = fn
+	}
+}
+
+type server struct {
+	store      Store
+	maxSize    int64
+	expiration time.Duration
+	onComplete func(ctx context.Context, u *Upload) error
+	now        func() time.Time
+}
+
+// NewRouter 创建挂载于 basePath 的 tus 上传路由。
+func NewRouter(basePath string, store Store, opts ...OptionFunc) courier.Router {
+	s := &server{
+		store:      store,
+		expiration: 24 * time.Hour,
+		now:        time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return courier.NewRouter(courierhttp.Group(basePath), &Resumable{}).With(
+		courier.NewRouter(&Discover{server: s}),
+		courier.NewRouter(&CreateUpload{server: s}),
+		courier.NewRouter(&GetUploadOffset{server: s}),
+		courier.NewRouter(&AppendUpload{server: s}),
+		courier.NewRouter(&TerminateUpload{server: s}),
+	)
+}
+
+// Resumable 为所有响应输出 `Tus-Resumable`，并拒绝协议版本不匹配的请求。
+type Resumable struct{}
+
+func (Resumable) ResponseErrors() []error {
+	return []error{
+		&ErrUnsupportedVersion{},
+	}
+}
+
+func (*Resumable) Output(ctx context.Context) (any, error) {
+	return nil, nil
+}
+
+func (*Resumable) PreHandlerMiddleware(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set(HeaderTusResumable, Version)
+
+		// 协议发现请求无需声明版本
+		if req.Method != http.MethodOptions {
+			if v := req.Header.Get(HeaderTusResumable); v != Version {
+				rw.Header().Set(HeaderTusVersion, Version)
+
+				err := courierhttp.WrapError(&ErrUnsupportedVersion{Version: v})
+				_ = err.(courierhttp.ResponseWriter).WriteResponse(req.Context(), rw, httprequest.From(req))
+				return
+			}
+		}
+
+		h.ServeHTTP(rw, req)
+	})
+}
+
+// Discover 返回服务端支持的协议版本、扩展与大小限制。
+type Discover struct {
+	courierhttp.MethodOptions
+
+	server *server
+}
+
+func (d *Discover) InitFrom(o courier.Operator) {
+	if x, ok := o.(*Discover); ok {
+		d.server = x.server
+	}
+}
+
+func (d *Discover) Output(ctx context.Context) (any, error) {
+	opts := []courierhttp.ResponseSettingFunc{
+		courierhttp.WithStatusCode(http.StatusNoContent),
+		courierhttp.WithMetadata(HeaderTusVersion, Version),
+		courierhttp.WithMetadata(HeaderTusExtension, strings.Join(extensions, ",")),
+	}
+
+	if d.server.maxSize > 0 {
+		opts = append(opts, courierhttp.WithMetadata(HeaderTusMaxSize, strconv.FormatInt(d.server.maxSize, 10)))
+	}
+
+	return courierhttp.Wrap[any](nil, opts...), nil
+}
+
+func withUploadExpires(u *Upload) courierhttp.ResponseSettingFunc {
+	return func(s courierhttp.ResponseSetting) {
+		if !u.ExpiresAt.IsZero() {
+			s.SetMetadata(HeaderUploadExpires, u.ExpiresAt.UTC().Format(http.TimeFormat))
+		}
+	}
+}
+
+func (s *server) get(ctx context.Context, id string) (*Upload, error) {
+	u, err := s.store.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.IsExpired(s.now()) {
+		_ = s.store.Terminate(ctx, id)
+		return nil, &ErrUploadExpired{ID: id}
+	}
+
+	return u, nil
+}
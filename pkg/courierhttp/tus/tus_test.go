@@ -0,0 +1,195 @@
+package tus_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/octohelm/x/testing/v2"
+
+	"github.com/octohelm/courier/pkg/courierhttp"
+	"github.com/octohelm/courier/pkg/courierhttp/handler/httprouter"
+	"github.com/octohelm/courier/pkg/courierhttp/tus"
+)
+
+func TestTus(t *testing.T) {
+	store := tus.NewFSStore(t.TempDir())
+
+	completed := make(chan *tus.Upload, 1)
+
+	h, err := httprouter.New(courierhttp.GroupRouter("/").With(
+		tus.NewRouter("/uploads", store,
+			tus.WithMaxSize(16),
+			tus.WithOnComplete(func(ctx context.Context, u *tus.Upload) error {
+				completed <- u
+				return nil
+			}),
+		),
+	), "test")
+	Then(t, "构建 httprouter handler 成功", Expect(err, Equal[error](nil)))
+
+	serve := func(method string, path string, body string, headers ...string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(tus.HeaderTusResumable, tus.Version)
+		for i := 0; i+1 < len(headers); i += 2 {
+			req.Header.Set(headers[i], headers[i+1])
+		}
+		rw := httptest.NewRecorder()
+		h.ServeHTTP(rw, req)
+		return rw
+	}
+
+	options := serve(http.MethodOptions, "/uploads", "", tus.HeaderTusResumable, "")
+	Then(t, "OPTIONS 返回支持的版本与扩展",
+		Expect(options.Code, Equal(http.StatusNoContent)),
+		Expect(options.Header().Get(tus.HeaderTusVersion), Equal(tus.Version)),
+		Expect(options.Header().Get(tus.HeaderTusMaxSize), Equal("16")),
+		Expect(strings.Contains(options.Header().Get(tus.HeaderTusExtension), "termination"), Equal(true)),
+	)
+
+	Then(t, "协议版本不匹配时返回 412",
+		Expect(serve(http.MethodPost, "/uploads", "", tus.HeaderTusResumable, "0.2.0", tus.HeaderUploadLength, "4").Code, Equal(http.StatusPreconditionFailed)),
+	)
+
+	Then(t, "缺少长度或超出限制时拒绝创建",
+		Expect(serve(http.MethodPost, "/uploads", "").Code, Equal(http.StatusBadRequest)),
+		Expect(serve(http.MethodPost, "/uploads", "", tus.HeaderUploadLength, "17").Code, Equal(http.StatusRequestEntityTooLarge)),
+	)
+
+	created := serve(http.MethodPost, "/uploads", "",
+		tus.HeaderUploadLength, "10",
+		tus.HeaderUploadMetadata, tus.Metadata{"filename": "a.txt"}.String(),
+	)
+	location := created.Header().Get("Location")
+
+	Then(t, "创建上传返回地址与过期时间",
+		Expect(created.Code, Equal(http.StatusCreated)),
+		Expect(created.Header().Get(tus.HeaderTusResumable), Equal(tus.Version)),
+		Expect(strings.HasPrefix(location, "/uploads/"), Equal(true)),
+		Expect(created.Header().Get(tus.HeaderUploadExpires) != "", Equal(true)),
+	)
+
+	patch := func(offset string, body string) *httptest.ResponseRecorder {
+		return serve(http.MethodPatch, location, body,
+			"Content-Type", tus.ContentTypeOffsetOctetStream,
+			tus.HeaderUploadOffset, offset,
+		)
+	}
+
+	first := patch("0", "01234")
+	Then(t, "追加数据后返回新的偏移量",
+		Expect(first.Code, Equal(http.StatusNoContent)),
+		Expect(first.Header().Get(tus.HeaderUploadOffset), Equal("5")),
+	)
+
+	head := serve(http.MethodHead, location, "")
+	Then(t, "HEAD 返回偏移量、长度与元数据",
+		Expect(head.Code, Equal(http.StatusOK)),
+		Expect(head.Header().Get(tus.HeaderUploadOffset), Equal("5")),
+		Expect(head.Header().Get(tus.HeaderUploadLength), Equal("10")),
+		Expect(head.Header().Get("Cache-Control"), Equal("no-store")),
+		Expect(head.Header().Get(tus.HeaderUploadMetadata), Equal(tus.Metadata{"filename": "a.txt"}.String())),
+	)
+
+	Then(t, "偏移量不一致或类型错误时拒绝追加",
+		Expect(patch("3", "34").Code, Equal(http.StatusConflict)),
+		Expect(serve(http.MethodPatch, location, "56789", tus.HeaderUploadOffset, "5").Code, Equal(http.StatusUnsupportedMediaType)),
+		Expect(patch("5", "56789x").Code, Equal(http.StatusRequestEntityTooLarge)),
+	)
+
+	Then(t, "超出长度的请求不影响已接收的数据",
+		Expect(serve(http.MethodHead, location, "").Header().Get(tus.HeaderUploadOffset), Equal("10")),
+	)
+
+	Then(t, "上传完成后触发回调",
+		ExpectMust(func() error {
+			select {
+			case u := <-completed:
+				f, err := store.Open(context.Background(), u.ID)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				data, err := io.ReadAll(f)
+				if err != nil {
+					return err
+				}
+				if string(data) != "0123456789" {
+					return io.ErrUnexpectedEOF
+				}
+				return nil
+			case <-time.After(time.Second):
+				return context.DeadlineExceeded
+			}
+		}),
+	)
+
+	Then(t, "终止后上传不存在",
+		Expect(serve(http.MethodDelete, location, "").Code, Equal(http.StatusNoContent)),
+		Expect(serve(http.MethodHead, location, "").Code, Equal(http.StatusNotFound)),
+	)
+}
+
+func TestTusExpiration(t *testing.T) {
+	h, err := httprouter.New(courierhttp.GroupRouter("/").With(
+		tus.NewRouter("/uploads", tus.NewFSStore(t.TempDir()), tus.WithExpiration(time.Nanosecond)),
+	), "test")
+	Then(t, "构建 httprouter handler 成功", Expect(err, Equal[error](nil)))
+
+	req := httptest.NewRequest(http.MethodPost, "/uploads", nil)
+	req.Header.Set(tus.HeaderTusResumable, tus.Version)
+	req.Header.Set(tus.HeaderUploadDeferLength, "1")
+	created := httptest.NewRecorder()
+	h.ServeHTTP(created, req)
+
+	time.Sleep(time.Millisecond)
+
+	req = httptest.NewRequest(http.MethodHead, created.Header().Get("Location"), nil)
+	req.Header.Set(tus.HeaderTusResumable, tus.Version)
+	rw := httptest.NewRecorder()
+	h.ServeHTTP(rw, req)
+
+	Then(t, "过期的上传返回 410",
+		Expect(created.Code, Equal(http.StatusCreated)),
+		Expect(rw.Code, Equal(http.StatusGone)),
+	)
+}
+
+func TestFSStoreLock(t *testing.T) {
+	ctx := context.Background()
+	store := tus.NewFSStore(t.TempDir())
+
+	u := &tus.Upload{Length: -1, ExpiresAt: time.Now()}
+	if err := store.Create(ctx, u); err != nil {
+		t.Fatal(err)
+	}
+
+	r, w := io.Pipe()
+	written := make(chan error, 1)
+
+	go func() {
+		_, err := store.WriteChunk(ctx, u.ID, 0, r)
+		written <- err
+	}()
+
+	// 确认 WriteChunk 已持有锁并阻塞在读取上
+	_, _ = w.Write([]byte("a"))
+
+	Then(t, "写入过程中终止上传返回 ErrUploadLocked",
+		ExpectDo(func() error { return store.Terminate(ctx, u.ID) }, ErrorAsType[*tus.ErrUploadLocked]()),
+		ExpectDo(func() error { return store.DeclareLength(ctx, u.ID, 1) }, ErrorAsType[*tus.ErrUploadLocked]()),
+		ExpectMust(func() error { return store.PurgeExpired(ctx, time.Now().Add(time.Hour)) }),
+	)
+
+	_ = w.Close()
+
+	Then(t, "写入结束后可终止上传",
+		ExpectMust(func() error { return <-written }),
+		ExpectMust(func() error { return store.Terminate(ctx, u.ID) }),
+		ExpectDo(func() error { _, err := store.Get(ctx, u.ID); return err }, ErrorAsType[*tus.ErrUploadNotFound]()),
+	)
+}
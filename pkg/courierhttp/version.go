@@ -0,0 +1,127 @@
+package courierhttp
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/octohelm/courier/pkg/courier"
+)
+
+// VersionInfo 描述路由声明的 API 版本。
+type VersionInfo struct {
+	Version     string
+	Deprecation Deprecation
+}
+
+// VersionDescriber 用于描述路由的 API 版本。
+type VersionDescriber interface {
+	VersionInfo() VersionInfo
+}
+
+// VersioningDescriber 用于描述路由的版本选择方式。
+type VersioningDescriber interface {
+	Versioning() Versioning
+}
+
+// DefaultVersioning 为未声明 UseVersioning 时的版本选择方式。
+var DefaultVersioning = Versioning{Path: true}
+
+// Versioning 描述同一操作多个版本之间的选择方式。
+type Versioning struct {
+	// 以路径前缀 `/v{version}` 区分版本，启用后 Accept 与 Header 不再生效
+	Path bool
+	// 以 `Accept` 媒体类型参数 `version` 区分版本，如 `application/vnd.x+json;version=2`
+	Accept bool
+	// 以指定请求头区分版本，如 `Api-Version`
+	Header string
+}
+
+func (v Versioning) IsZero() bool {
+	return !v.Path && !v.Accept && v.Header == ""
+}
+
+// RequestedVersion 按 Header 与 Accept 方式从请求中解析客户端要求的版本，未声明时返回空。
+func (v Versioning) RequestedVersion(req *http.Request) string {
+	if v.Header != "" {
+		if version := req.Header.Get(v.Header); version != "" {
+			return version
+		}
+	}
+
+	if v.Accept {
+		for _, accept := range req.Header.Values("Accept") {
+			for mediaRange := range strings.SplitSeq(accept, ",") {
+				if _, params, err := mime.ParseMediaType(strings.TrimSpace(mediaRange)); err == nil {
+					if version := params["version"]; version != "" {
+						return version
+					}
+				}
+			}
+		}
+	}
+
+	return ""
+}
+
+func (v Versioning) String() string {
+	parts := make([]string, 0, 3)
+	if v.Path {
+		parts = append(parts, "path")
+	}
+	if v.Accept {
+		parts = append(parts, "accept")
+	}
+	if v.Header != "" {
+		parts = append(parts, "header="+v.Header)
+	}
+	return strings.Join(parts, ",")
+}
+
+type VersionOptionFunc = func(v *VersionInfo)
+
+// VersionDeprecated 将版本标记为自 at 起弃用，sunset 非零时同时声明下线时间。
+func VersionDeprecated(at time.Time, sunset time.Time) VersionOptionFunc {
+	return func(v *VersionInfo) {
+		v.Deprecation = Deprecation{At: at, Sunset: sunset}
+	}
+}
+
+// Version 创建声明 API 版本的操作符，作用于其所在路由及子路由。
+//
+// 已弃用的版本会在响应中自动输出 `Deprecation` 与 `Sunset` 头。
+func Version(version string, opts ...VersionOptionFunc) courier.Operator {
+	info := &VersionInfo{Version: version}
+	for _, opt := range opts {
+		opt(info)
+	}
+	return &metaOperator{version: info}
+}
+
+// UseVersioning 创建声明版本选择方式的操作符，未声明时使用 DefaultVersioning。
+func UseVersioning(v Versioning) courier.Operator {
+	return &metaOperator{versioning: &v}
+}
+
+func (g *metaOperator) VersionInfo() VersionInfo {
+	if g.version != nil {
+		return *g.version
+	}
+	return VersionInfo{}
+}
+
+func (g *metaOperator) Versioning() Versioning {
+	if g.versioning != nil {
+		return *g.versioning
+	}
+	return Versioning{}
+}
+
+func (g *metaOperator) versionString() string {
+	if g.version != nil {
+		return fmt.Sprintf("version(%s)", g.version.Version)
+	}
+	return fmt.Sprintf("versioning(%s)", g.versioning)
+}
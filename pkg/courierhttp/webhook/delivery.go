@@ -0,0 +1,118 @@
+package webhook
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+type DeliveryStatus string
+
+const (
+	DeliveryStatusPending   DeliveryStatus = "pending"
+	DeliveryStatusSucceeded DeliveryStatus = "succeeded"
+	DeliveryStatusFailed    DeliveryStatus = "failed"
+)
+
+// Delivery 表示一次事件投递，重放时沿用同一个 ID 作为 `Webhook-Id`，便于接收方去重。
+type Delivery struct {
+	ID             string
+	SubscriptionID string
+	URL            string
+	EventID        string
+	EventType      string
+	Payload        []byte
+	Status         DeliveryStatus
+	Attempts       []Attempt
+	CreatedAt      time.Time
+}
+
+// Attempt 表示一次投递尝试。
+type Attempt struct {
+	At         time.Time
+	Duration   time.Duration
+	StatusCode int
+	Error      string
+}
+
+// DeliveryLog 持久化投递记录。
+type DeliveryLog interface {
+	// Save 保存或更新投递记录。
+	Save(ctx context.Context, d *Delivery) error
+	// Get 获取投递记录，不存在时返回 *ErrDeliveryNotFound。
+	Get(ctx context.Context, id string) (*Delivery, error)
+	// List 按创建时间倒序列出订阅的投递记录，subscriptionID 为空时列出全部。
+	List(ctx context.Context, subscriptionID string, limit int) ([]*Delivery, error)
+}
+
+// NewMemDeliveryLog 创建内存投递记录，最多保留 capacity 条，超出时淘汰最早的记录。
+func NewMemDeliveryLog(capacity int) DeliveryLog {
+	return &memDeliveryLog{
+		capacity:   capacity,
+		deliveries: map[string]*Delivery{},
+	}
+}
+
+type memDeliveryLog struct {
+	capacity int
+
+	mu         sync.RWMutex
+	deliveries map[string]*Delivery
+	order      []string
+}
+
+func (l *memDeliveryLog) Save(ctx context.Context, d *Delivery) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.deliveries[d.ID]; !ok {
+		l.order = append(l.order, d.ID)
+
+		if l.capacity > 0 && len(l.order) > l.capacity {
+			delete(l.deliveries, l.order[0])
+			l.order = l.order[1:]
+		}
+	}
+
+	l.deliveries[d.ID] = clone(d)
+
+	return nil
+}
+
+func (l *memDeliveryLog) Get(ctx context.Context, id string) (*Delivery, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	d, ok := l.deliveries[id]
+	if !ok {
+		return nil, &ErrDeliveryNotFound{ID: id}
+	}
+	return clone(d), nil
+}
+
+func (l *memDeliveryLog) List(ctx context.Context, subscriptionID string, limit int) ([]*Delivery, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	list := make([]*Delivery, 0)
+
+	for _, id := range slices.Backward(l.order) {
+		d := l.deliveries[id]
+		if subscriptionID != "" && d.SubscriptionID != subscriptionID {
+			continue
+		}
+		list = append(list, clone(d))
+		if limit > 0 && len(list) >= limit {
+			break
+		}
+	}
+
+	return list, nil
+}
+
+func clone(d *Delivery) *Delivery {
+	c := *d
+	c.Attempts = slices.Clone(d.Attempts)
+	return &c
+}
@@ -0,0 +1,190 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-json-experiment/json"
+
+	"github.com/octohelm/courier/pkg/courierhttp/client"
+)
+
+// Dispatcher 投递 webhook 事件。
+type Dispatcher struct {
+	// 发送请求的客户端，可配置 HttpTransports；Endpoint 不生效，投递地址取自订阅
+	Client *client.Client
+	// 投递记录，为空时不记录，也无法重放
+	Log DeliveryLog
+	// 单次投递的最大尝试次数，默认 5
+	MaxAttempts int
+	// 第 n 次失败后的等待时间，默认为 1s 起逐次翻倍，最长 1h
+	Backoff func(n int) time.Duration
+}
+
+// Publish 将事件投递给订阅了该事件类型的订阅，返回各订阅的投递记录。
+//
+// 投递按订阅依次同步进行，单个订阅投递失败不影响其余订阅，失败时返回合并后的错误。
+func Publish[T EventTypeDescriber](ctx context.Context, d *Dispatcher, subscriptions []Subscription, data T) ([]*Delivery, error) {
+	event := NewEvent(data)
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := make([]*Delivery, 0, len(subscriptions))
+
+	var errs []error
+
+	for i := range subscriptions {
+		sub := &subscriptions[i]
+		if !sub.Accepts(event.Type) {
+			continue
+		}
+
+		delivery, err := d.Deliver(ctx, sub, event.ID, event.Type, payload)
+		if delivery != nil {
+			deliveries = append(deliveries, delivery)
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return deliveries, errors.Join(errs...)
+}
+
+// Deliver 将已编码的事件载荷投递给订阅，失败时按 Backoff 重试。
+func (d *Dispatcher) Deliver(ctx context.Context, sub *Subscription, eventID string, eventType string, payload []byte) (*Delivery, error) {
+	delivery := &Delivery{
+		ID:             newID("msg_"),
+		SubscriptionID: sub.ID,
+		URL:            sub.URL,
+		EventID:        eventID,
+		EventType:      eventType,
+		Payload:        payload,
+		Status:         DeliveryStatusPending,
+		CreatedAt:      time.Now(),
+	}
+
+	return delivery, d.deliver(ctx, delivery, sub.Secret)
+}
+
+// Replay 按投递记录重新投递，沿用原有的 `Webhook-Id`。
+func (d *Dispatcher) Replay(ctx context.Context, deliveryID string, secret []byte) (*Delivery, error) {
+	if d.Log == nil {
+		return nil, &ErrDeliveryNotFound{ID: deliveryID}
+	}
+
+	delivery, err := d.Log.Get(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery.Status = DeliveryStatusPending
+
+	return delivery, d.deliver(ctx, delivery, secret)
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, delivery *Delivery, secret []byte) error {
+	maxAttempts := d.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 5
+	}
+
+	for n := 1; ; n++ {
+		attempt, err := d.attempt(ctx, delivery, secret)
+		delivery.Attempts = append(delivery.Attempts, attempt)
+
+		if err == nil {
+			delivery.Status = DeliveryStatusSucceeded
+		} else if n >= maxAttempts || !retryable(ctx, err) {
+			delivery.Status = DeliveryStatusFailed
+		}
+
+		if d.Log != nil {
+			if err := d.Log.Save(ctx, delivery); err != nil {
+				return err
+			}
+		}
+
+		if delivery.Status != DeliveryStatusPending {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			delivery.Status = DeliveryStatusFailed
+			if d.Log != nil {
+				_ = d.Log.Save(context.WithoutCancel(ctx), delivery)
+			}
+			return ctx.Err()
+		case <-time.After(d.backoff(n)):
+		}
+	}
+}
+
+func (d *Dispatcher) attempt(ctx context.Context, delivery *Delivery, secret []byte) (Attempt, error) {
+	attempt := Attempt{At: time.Now()}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderWebhookID, delivery.ID)
+	req.Header.Set(HeaderWebhookTimestamp, strconv.FormatInt(attempt.At.Unix(), 10))
+	req.Header.Set(HeaderWebhookSignature, Sign(secret, delivery.ID, attempt.At, delivery.Payload))
+
+	c := d.Client
+	if c == nil {
+		c = &client.Client{}
+	}
+
+	result := c.Do(ctx, req)
+	_, err = result.Into(nil)
+
+	attempt.Duration = time.Since(attempt.At)
+
+	if x, ok := result.(interface{ StatusCode() int }); ok {
+		attempt.StatusCode = x.StatusCode()
+	}
+
+	if err != nil {
+		attempt.Error = err.Error()
+	}
+
+	return attempt, err
+}
+
+func (d *Dispatcher) backoff(n int) time.Duration {
+	if d.Backoff != nil {
+		return d.Backoff(n)
+	}
+	// 1s<<12 已超过 1h，限制 n 避免移位溢出为负数
+	return min(time.Second<<(min(max(n, 1), 13)-1), time.Hour)
+}
+
+func retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	var statusErr interface{ StatusCode() int }
+	if errors.As(err, &statusErr) {
+		switch code := statusErr.StatusCode(); code {
+		case http.StatusRequestTimeout, http.StatusTooManyRequests:
+			return true
+		default:
+			return code >= http.StatusInternalServerError
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,20 @@
+package webhook
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/octohelm/x/testing/v2"
+)
+
+func TestDispatcherBackoff(t *testing.T) {
+	d := &Dispatcher{}
+
+	Then(t, "默认退避逐次翻倍，最长 1h 且不会溢出",
+		Expect(d.backoff(1), Equal(time.Second)),
+		Expect(d.backoff(3), Equal(4*time.Second)),
+		Expect(d.backoff(13), Equal(time.Hour)),
+		Expect(d.backoff(35), Equal(time.Hour)),
+		Expect(d.backoff(100), Equal(time.Hour)),
+	)
+}
@@ -0,0 +1,10 @@
+// Package webhook 提供 webhook 的投递与签名校验。
+//
+// `Dispatcher` 基于 `client.Client` 将事件以 JSON 投递给订阅地址，
+// 按 Standard Webhooks 约定携带 `Webhook-Id`、`Webhook-Timestamp` 与 HMAC-SHA256 签名 `Webhook-Signature`，
+// 失败时退避重试，每次投递记录到 `DeliveryLog`，可按记录重放。
+//
+// 接收方将 `Verifier` 作为中间 operator 挂在路由链上，校验签名与时间戳偏差。
+//
+// +gengo:runtimedoc=false
+package webhook
@@ -0,0 +1,39 @@
+package webhook
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/octohelm/courier/pkg/statuserror"
+)
+
+type ErrInvalidSignature struct {
+	statuserror.Unauthorized
+
+	Reason string
+}
+
+func (e *ErrInvalidSignature) Error() string {
+	return fmt.Sprintf("invalid webhook signature: %s", e.Reason)
+}
+
+type ErrTimestampOutOfTolerance struct {
+	statuserror.Unauthorized
+
+	Timestamp time.Time
+	Tolerance time.Duration
+}
+
+func (e *ErrTimestampOutOfTolerance) Error() string {
+	return fmt.Sprintf("webhook timestamp %s is out of tolerance %s", e.Timestamp.UTC().Format(time.RFC3339), e.Tolerance)
+}
+
+type ErrDeliveryNotFound struct {
+	statuserror.NotFound
+
+	ID string
+}
+
+func (e *ErrDeliveryNotFound) Error() string {
+	return fmt.Sprintf("webhook delivery %s not found", e.ID)
+}
@@ -0,0 +1,65 @@
+package webhook
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+// EventTypeDescriber 由事件数据类型实现，声明事件类型，如 `order.created`。
+type EventTypeDescriber interface {
+	EventType() string
+}
+
+// Event 为投递的事件载荷。
+type Event[T any] struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	Timestamp time.Time `json:"timestamp"`
+	Data      T         `json:"data"`
+}
+
+// NewEvent 以 data 创建事件，事件类型取自 data。
+func NewEvent[T EventTypeDescriber](data T) *Event[T] {
+	return &Event[T]{
+		ID:        newID("evt_"),
+		Type:      data.EventType(),
+		Timestamp: time.Now().UTC(),
+		Data:      data,
+	}
+}
+
+// Subscription 表示 webhook 订阅。
+type Subscription struct {
+	ID string
+	// 投递地址
+	URL string
+	// 签名密钥
+	Secret []byte
+	// 订阅的事件类型，为空时订阅全部；`order.*` 匹配 `order.` 开头的事件
+	EventTypes []string
+}
+
+func (s *Subscription) Accepts(eventType string) bool {
+	if len(s.EventTypes) == 0 {
+		return true
+	}
+
+	for _, t := range s.EventTypes {
+		if t == "*" || t == eventType {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(t, "*"); ok && strings.HasPrefix(eventType, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func newID(prefix string) string {
+	b := make([]byte, 12)
+	_, _ = rand.Read(b)
+	return prefix + hex.EncodeToString(b)
+}
@@ -0,0 +1,67 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	HeaderWebhookID        = "Webhook-Id"
+	HeaderWebhookTimestamp = "Webhook-Timestamp"
+	HeaderWebhookSignature = "Webhook-Signature"
+
+	signatureVersion = "v1"
+)
+
+// Sign 计算 `id.timestamp.body` 的 HMAC-SHA256 签名，格式为 `v1,<base64>`。
+func Sign(secret []byte, id string, timestamp time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(id))
+	mac.Write([]byte("."))
+	mac.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return signatureVersion + "," + base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Verify 校验签名与时间戳。
+//
+// signature 可包含以空格分隔的多个签名，任一与 secrets 中任一密钥匹配即通过，以支持密钥轮换；
+// 拒绝与 now 偏差超过 tolerance 的时间戳以防止重放，tolerance 不大于 0 时使用 DefaultTolerance。
+func Verify(secrets [][]byte, id string, timestamp string, signature string, body []byte, now time.Time, tolerance time.Duration) error {
+	if id == "" || timestamp == "" || signature == "" {
+		return &ErrInvalidSignature{Reason: "missing webhook headers"}
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return &ErrInvalidSignature{Reason: "invalid timestamp"}
+	}
+
+	ts := time.Unix(unix, 0)
+
+	if tolerance <= 0 {
+		tolerance = DefaultTolerance
+	}
+
+	if d := now.Sub(ts); d > tolerance || d < -tolerance {
+		return &ErrTimestampOutOfTolerance{Timestamp: ts, Tolerance: tolerance}
+	}
+
+	for _, secret := range secrets {
+		expected := Sign(secret, id, ts, body)
+
+		for s := range strings.FieldsSeq(signature) {
+			if hmac.Equal([]byte(s), []byte(expected)) {
+				return nil
+			}
+		}
+	}
+
+	return &ErrInvalidSignature{Reason: "no matching signature"}
+}
@@ -0,0 +1,90 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/octohelm/courier/internal/httprequest"
+	"github.com/octohelm/courier/pkg/courier"
+	"github.com/octohelm/courier/pkg/courierhttp"
+)
+
+// DefaultTolerance 为未指定时允许的时间戳偏差
+const DefaultTolerance = 5 * time.Minute
+
+// NewVerifier 创建校验 webhook 签名的中间 operator，tolerance 为允许的时间戳偏差，
+// 不大于 0 时使用 DefaultTolerance。
+func NewVerifier(tolerance time.Duration, secrets ...[]byte) *Verifier {
+	if tolerance <= 0 {
+		tolerance = DefaultTolerance
+	}
+
+	return &Verifier{
+		tolerance: tolerance,
+		secrets:   secrets,
+	}
+}
+
+// Verifier 校验请求的 webhook 签名，通过后请求体可被后续 operator 正常读取。
+type Verifier struct {
+	WebhookID        string `name:"Webhook-Id,omitzero" in:"header"`
+	WebhookTimestamp string `name:"Webhook-Timestamp,omitzero" in:"header"`
+	WebhookSignature string `name:"Webhook-Signature,omitzero" in:"header"`
+
+	tolerance   time.Duration
+	secrets     [][]byte
+	maxBodySize int64
+}
+
+// WithMaxBodySize 设置校验签名时读取请求体的上限，见 courierhttp.ReadBody。
+func (v *Verifier) WithMaxBodySize(n int64) *Verifier {
+	v.maxBodySize = n
+	return v
+}
+
+func (v *Verifier) InitFrom(o courier.Operator) {
+	if x, ok := o.(*Verifier); ok {
+		v.tolerance = x.tolerance
+		v.secrets = x.secrets
+		v.maxBodySize = x.maxBodySize
+	}
+}
+
+func (Verifier) ResponseErrors() []error {
+	return []error{
+		&ErrInvalidSignature{},
+		&ErrTimestampOutOfTolerance{},
+		&courierhttp.ErrRequestBodyTooLarge{},
+	}
+}
+
+func (*Verifier) Output(ctx context.Context) (any, error) {
+	return nil, nil
+}
+
+// PreHandlerMiddleware 在解析请求前读取原始请求体完成校验，并还原请求体。
+func (v *Verifier) PreHandlerMiddleware(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		body, err := courierhttp.ReadBody(rw, req, v.maxBodySize)
+		if err == nil {
+			err = Verify(
+				v.secrets,
+				req.Header.Get(HeaderWebhookID),
+				req.Header.Get(HeaderWebhookTimestamp),
+				req.Header.Get(HeaderWebhookSignature),
+				body,
+				time.Now(),
+				v.tolerance,
+			)
+		}
+
+		if err != nil {
+			e := courierhttp.WrapError(err)
+			_ = e.(courierhttp.ResponseWriter).WriteResponse(req.Context(), rw, httprequest.From(req))
+			return
+		}
+
+		h.ServeHTTP(rw, req)
+	})
+}
@@ -0,0 +1,158 @@
+package webhook_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/octohelm/x/testing/v2"
+
+	"github.com/octohelm/courier/pkg/courier"
+	"github.com/octohelm/courier/pkg/courierhttp"
+	"github.com/octohelm/courier/pkg/courierhttp/client"
+	"github.com/octohelm/courier/pkg/courierhttp/handler/httprouter"
+	"github.com/octohelm/courier/pkg/courierhttp/webhook"
+)
+
+type OrderCreated struct {
+	OrderID string `json:"orderID"`
+}
+
+func (OrderCreated) EventType() string {
+	return "order.created"
+}
+
+type ReceiveOrderCreated struct {
+	courierhttp.MethodPost `path:"/hooks"`
+
+	Body webhook.Event[OrderCreated] `in:"body"`
+}
+
+var received = make(chan webhook.Event[OrderCreated], 10)
+
+func (r *ReceiveOrderCreated) Output(ctx context.Context) (any, error) {
+	received <- r.Body
+	return nil, nil
+}
+
+func TestWebhook(t *testing.T) {
+	secret := []byte("secret")
+
+	h, err := httprouter.New(courierhttp.GroupRouter("/").With(
+		courier.NewRouter(webhook.NewVerifier(5*time.Minute, []byte("rotated"), secret), &ReceiveOrderCreated{}),
+	), "test")
+	Then(t, "构建 httprouter handler 成功", Expect(err, Equal[error](nil)))
+
+	requests := atomic.Int32{}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		// 首次投递返回 503 以触发重试
+		if requests.Add(1) == 1 {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		h.ServeHTTP(rw, req)
+	}))
+	t.Cleanup(srv.Close)
+
+	log := webhook.NewMemDeliveryLog(10)
+
+	d := &webhook.Dispatcher{
+		// 拦截器包装 Result 后仍应记录投递的状态码
+		Client: &client.Client{
+			Interceptors: []courier.Interceptor{
+				func(next courier.Invoker) courier.Invoker {
+					return func(ctx context.Context, req any, metas ...courier.Metadata) courier.Result {
+						r := next(ctx, req, metas...)
+						return courier.WrapResult(r, r.Into)
+					}
+				},
+			},
+		},
+		Log:     log,
+		Backoff: func(n int) time.Duration { return time.Millisecond },
+	}
+
+	subscriptions := []webhook.Subscription{
+		{ID: "sub1", URL: srv.URL + "/hooks", Secret: secret, EventTypes: []string{"order.*"}},
+		{ID: "sub2", URL: srv.URL + "/hooks", Secret: secret, EventTypes: []string{"user.created"}},
+	}
+
+	deliveries, err := webhook.Publish(context.Background(), d, subscriptions, OrderCreated{OrderID: "o1"})
+
+	Then(t, "仅投递给订阅了该事件的订阅，失败后重试成功",
+		Expect(err, Equal[error](nil)),
+		Expect(len(deliveries), Equal(1)),
+		Expect(deliveries[0].Status, Equal(webhook.DeliveryStatusSucceeded)),
+		Expect(len(deliveries[0].Attempts), Equal(2)),
+		Expect(deliveries[0].Attempts[0].StatusCode, Equal(http.StatusServiceUnavailable)),
+		Expect((<-received).Data.OrderID, Equal("o1")),
+	)
+
+	replayed, err := d.Replay(context.Background(), deliveries[0].ID, secret)
+
+	Then(t, "可按投递记录重放",
+		Expect(err, Equal[error](nil)),
+		Expect(len(replayed.Attempts), Equal(3)),
+		Expect((<-received).Data.OrderID, Equal("o1")),
+	)
+
+	Then(t, "投递记录已持久化",
+		ExpectMust(func() error {
+			list, err := log.List(context.Background(), "sub1", 0)
+			if err != nil {
+				return err
+			}
+			if len(list) != 1 || list[0].Status != webhook.DeliveryStatusSucceeded {
+				return &webhook.ErrDeliveryNotFound{ID: deliveries[0].ID}
+			}
+			return nil
+		}),
+	)
+
+	post := func(ts time.Time, secret []byte) int {
+		body := []byte(`{"id":"evt_1","type":"order.created","timestamp":"2024-01-01T00:00:00Z","data":{"orderID":"o2"}}`)
+		req := httptest.NewRequest(http.MethodPost, "/hooks", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(webhook.HeaderWebhookID, "msg_1")
+		req.Header.Set(webhook.HeaderWebhookTimestamp, strconv.FormatInt(ts.Unix(), 10))
+		req.Header.Set(webhook.HeaderWebhookSignature, webhook.Sign(secret, "msg_1", ts, body))
+		rw := httptest.NewRecorder()
+		h.ServeHTTP(rw, req)
+		return rw.Code
+	}
+
+	Then(t, "签名错误或时间戳超出容差时拒绝",
+		Expect(post(time.Now(), []byte("wrong")), Equal(http.StatusUnauthorized)),
+		Expect(post(time.Now().Add(-time.Hour), secret), Equal(http.StatusUnauthorized)),
+		Expect(post(time.Now(), secret), Equal(http.StatusNoContent)),
+	)
+
+	body := []byte(`{}`)
+	staled := time.Now().Add(-time.Hour)
+
+	Then(t, "偏差为 0 时使用默认偏差",
+		Expect(errors.As(
+			webhook.Verify([][]byte{secret}, "msg_1", strconv.FormatInt(staled.Unix(), 10), webhook.Sign(secret, "msg_1", staled, body), body, time.Now(), 0),
+			new(*webhook.ErrTimestampOutOfTolerance),
+		), Equal(true)),
+	)
+
+	limited, err := httprouter.New(courierhttp.GroupRouter("/").With(
+		courier.NewRouter(webhook.NewVerifier(5*time.Minute, secret).WithMaxBodySize(8), &ReceiveOrderCreated{}),
+	), "test")
+	Then(t, "构建 httprouter handler 成功", Expect(err, Equal[error](nil)))
+
+	rw := httptest.NewRecorder()
+	limited.ServeHTTP(rw, httptest.NewRequest(http.MethodPost, "/hooks", bytes.NewReader([]byte(`{"id":"evt_1"}`))))
+
+	Then(t, "请求体超出限制时在校验签名前拒绝",
+		Expect(rw.Code, Equal(http.StatusRequestEntityTooLarge)),
+	)
+}
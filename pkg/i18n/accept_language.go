@@ -0,0 +1,23 @@
+package i18n
+
+import (
+	"golang.org/x/text/language"
+)
+
+// ParseAcceptLanguage 按权重从高到低解析 `Accept-Language` 请求头中的语言。
+func ParseAcceptLanguage(values ...string) []string {
+	langs := make([]string, 0)
+
+	for _, v := range values {
+		tags, _, err := language.ParseAcceptLanguage(v)
+		if err != nil {
+			continue
+		}
+
+		for _, tag := range tags {
+			langs = append(langs, tag.String())
+		}
+	}
+
+	return langs
+}
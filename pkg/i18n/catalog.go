@@ -0,0 +1,152 @@
+package i18n
+
+import (
+	"fmt"
+	"io/fs"
+	"iter"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/go-json-experiment/json"
+	"golang.org/x/text/language"
+)
+
+// Messages 为单一语言下信息键到信息模板的映射。
+type Messages = map[string]string
+
+// NewCatalog 创建信息目录，fallbackLangs 为请求语言均未命中时依次尝试的语言。
+func NewCatalog(fallbackLangs ...string) *Catalog {
+	return &Catalog{
+		fallbackLangs: fallbackLangs,
+		messages:      map[string]Messages{},
+	}
+}
+
+// Catalog 表示多语言信息目录。
+type Catalog struct {
+	fallbackLangs []string
+
+	mu       sync.RWMutex
+	messages map[string]Messages
+}
+
+// Add 为指定语言添加信息，已存在的键会被覆盖。
+func (c *Catalog) Add(lang string, messages Messages) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	lang = normalizeLang(lang)
+
+	if c.messages[lang] == nil {
+		c.messages[lang] = Messages{}
+	}
+	maps.Copy(c.messages[lang], messages)
+}
+
+// LoadFS 从 fsys 加载 `<lang>.json` 翻译文件。
+func (c *Catalog) LoadFS(fsys fs.FS) error {
+	matches, err := fs.Glob(fsys, "*.json")
+	if err != nil {
+		return err
+	}
+
+	for _, filename := range matches {
+		data, err := fs.ReadFile(fsys, filename)
+		if err != nil {
+			return err
+		}
+
+		messages := Messages{}
+		if err := json.Unmarshal(data, &messages); err != nil {
+			return fmt.Errorf("解析翻译文件 %s 失败: %w", filename, err)
+		}
+
+		c.Add(strings.TrimSuffix(path.Base(filename), ".json"), messages)
+	}
+
+	return nil
+}
+
+// Languages 返回已加载的语言列表。
+func (c *Catalog) Languages() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return slices.Sorted(maps.Keys(c.messages))
+}
+
+// Lookup 按语言回退链查找信息模板，并返回命中的语言。
+func (c *Catalog) Lookup(key string, langs ...string) (tmpl string, lang string, ok bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if len(c.messages) == 0 {
+		return "", "", false
+	}
+
+	for _, l := range langs {
+		for candidate := range fallbackChain(l) {
+			if tmpl, ok := c.messages[candidate][key]; ok && tmpl != "" {
+				return tmpl, candidate, true
+			}
+		}
+	}
+
+	for _, l := range c.fallbackLangs {
+		l = normalizeLang(l)
+		if tmpl, ok := c.messages[l][key]; ok && tmpl != "" {
+			return tmpl, l, true
+		}
+	}
+
+	return "", "", false
+}
+
+// Localizer 创建绑定了请求语言的本地化器。
+func (c *Catalog) Localizer(langs ...string) *Localizer {
+	return &Localizer{catalog: c, langs: langs}
+}
+
+// Localizer 按绑定的语言偏好渲染信息。
+type Localizer struct {
+	catalog *Catalog
+	langs   []string
+}
+
+// Localize 查找 key 对应的信息模板并以 args 渲染。
+func (l *Localizer) Localize(key string, args map[string]any) (string, bool) {
+	tmpl, _, ok := l.catalog.Lookup(key, l.langs...)
+	if !ok {
+		return "", false
+	}
+	return Format(tmpl, args), true
+}
+
+func normalizeLang(lang string) string {
+	lang = strings.ReplaceAll(strings.TrimSpace(lang), "_", "-")
+	if tag, err := language.Parse(lang); err == nil {
+		return tag.String()
+	}
+	return lang
+}
+
+func fallbackChain(lang string) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		lang = normalizeLang(lang)
+
+		for lang != "" {
+			if !yield(lang) {
+				return
+			}
+
+			i := strings.LastIndex(lang, "-")
+			if i < 0 {
+				return
+			}
+			lang = lang[:i]
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package i18n
+
+import (
+	"context"
+)
+
+var defaultCatalog = NewCatalog()
+
+// Default 返回默认信息目录，未向上下文注入目录时使用。
+func Default() *Catalog {
+	return defaultCatalog
+}
+
+type contextCatalog struct{}
+
+// CatalogInjectContext 将信息目录注入上下文。
+func CatalogInjectContext(ctx context.Context, c *Catalog) context.Context {
+	return context.WithValue(ctx, contextCatalog{}, c)
+}
+
+// CatalogFromContext 从上下文中获取信息目录，缺省返回 Default()。
+func CatalogFromContext(ctx context.Context) *Catalog {
+	if c, ok := ctx.Value(contextCatalog{}).(*Catalog); ok && c != nil {
+		return c
+	}
+	return defaultCatalog
+}
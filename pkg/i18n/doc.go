@@ -0,0 +1,7 @@
+// Package i18n 提供按语言组织的错误信息目录与 `Accept-Language` 协商。
+//
+// 信息以键（错误编码或校验规则）索引，模板中的 `{Name}` 由错误的导出字段填充。
+// 查找顺序为请求语言、其父语言（如 `zh-Hant-TW` → `zh-Hant` → `zh`），最后为目录的回退语言。
+//
+// +gengo:runtimedoc=false
+package i18n
@@ -0,0 +1,70 @@
+package i18n
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Format 以 args 替换模板中的 `{Name}` 占位符，未提供的参数保留原样。
+func Format(tmpl string, args map[string]any) string {
+	if len(args) == 0 || !strings.Contains(tmpl, "{") {
+		return tmpl
+	}
+
+	b := &strings.Builder{}
+
+	for {
+		start := strings.IndexByte(tmpl, '{')
+		if start < 0 {
+			break
+		}
+
+		end := strings.IndexByte(tmpl[start:], '}')
+		if end < 0 {
+			break
+		}
+		end += start
+
+		name := tmpl[start+1 : end]
+
+		v, ok := args[name]
+		if !ok {
+			b.WriteString(tmpl[:end+1])
+			tmpl = tmpl[end+1:]
+			continue
+		}
+
+		b.WriteString(tmpl[:start])
+		b.WriteString(stringify(v))
+		tmpl = tmpl[end+1:]
+	}
+
+	b.WriteString(tmpl)
+
+	return b.String()
+}
+
+func stringify(v any) string {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return ""
+		}
+		rv = rv.Elem()
+	}
+
+	if !rv.IsValid() {
+		return ""
+	}
+
+	if rv.Kind() == reflect.Slice {
+		parts := make([]string, rv.Len())
+		for i := range rv.Len() {
+			parts[i] = stringify(rv.Index(i).Interface())
+		}
+		return strings.Join(parts, ", ")
+	}
+
+	return fmt.Sprint(rv.Interface())
+}
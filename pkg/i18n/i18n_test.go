@@ -0,0 +1,123 @@
+package i18n
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	. "github.com/octohelm/x/testing/v2"
+)
+
+func TestCatalog(t *testing.T) {
+	c := NewCatalog("en")
+	c.Add("en", Messages{
+		"ORG_CONFLICT": "org {Name} already exists",
+		"ONLY_EN":      "only en",
+	})
+	c.Add("zh", Messages{
+		"ORG_CONFLICT": "组织 {Name} 已存在",
+	})
+	c.Add("zh_Hant", Messages{
+		"ORG_CONFLICT": "組織 {Name} 已存在",
+	})
+
+	Then(
+		t, "按语言回退链查找信息",
+		ExpectMust(func() error {
+			tmpl, lang, ok := c.Lookup("ORG_CONFLICT", "zh-Hant-TW")
+			if !ok || lang != "zh-Hant" || tmpl != "組織 {Name} 已存在" {
+				return fmt.Errorf("unexpected lookup %q %q %v", tmpl, lang, ok)
+			}
+			return nil
+		}),
+		ExpectMust(func() error {
+			tmpl, lang, ok := c.Lookup("ORG_CONFLICT", "zh-CN")
+			if !ok || lang != "zh" || tmpl != "组织 {Name} 已存在" {
+				return fmt.Errorf("unexpected lookup %q %q %v", tmpl, lang, ok)
+			}
+			return nil
+		}),
+		ExpectMust(func() error {
+			_, lang, ok := c.Lookup("ONLY_EN", "zh")
+			if !ok || lang != "en" {
+				return fmt.Errorf("expect fallback to en, got %q %v", lang, ok)
+			}
+			_, _, ok = c.Lookup("MISSING", "zh")
+			if ok {
+				return fmt.Errorf("missing key should not be found")
+			}
+			return nil
+		}),
+		Expect(c.Languages(), Equal([]string{"en", "zh", "zh-Hant"})),
+	)
+
+	Then(
+		t, "本地化器按请求语言渲染模板",
+		ExpectMust(func() error {
+			msg, ok := c.Localizer(ParseAcceptLanguage("fr;q=0.9, zh-CN;q=0.8")...).Localize("ORG_CONFLICT", map[string]any{"Name": "demo"})
+			if !ok || msg != "组织 demo 已存在" {
+				return fmt.Errorf("unexpected message %q", msg)
+			}
+			return nil
+		}),
+	)
+}
+
+func TestFormat(t *testing.T) {
+	minimum := 1
+
+	Then(
+		t, "模板参数替换",
+		Expect(Format("{Subject} should be one of {Enums}", map[string]any{"Subject": "type", "Enums": []any{"A", "B"}}), Equal("type should be one of A, B")),
+		Expect(Format("larger than {Minimum}", map[string]any{"Minimum": &minimum}), Equal("larger than 1")),
+		Expect(Format("keep {Unknown} and {", map[string]any{"Name": "x"}), Equal("keep {Unknown} and {")),
+		Expect(Format("{Name}", nil), Equal("{Name}")),
+	)
+}
+
+func TestParseAcceptLanguage(t *testing.T) {
+	Then(
+		t, "按权重解析 Accept-Language",
+		Expect(ParseAcceptLanguage("en;q=0.5, zh-Hant-TW, fr;q=0"), Equal([]string{"zh-Hant-TW", "en"})),
+		Expect(ParseAcceptLanguage("!!"), Equal([]string{})),
+	)
+}
+
+func TestMessagesFile(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "zh.json")
+
+	Then(
+		t, "翻译文件合并时保留已有翻译",
+		ExpectMust(func() error {
+			if err := WriteMessagesFile(filename, Messages{"A": "甲"}); err != nil {
+				return err
+			}
+
+			messages, err := ReadMessagesFile(filename)
+			if err != nil {
+				return err
+			}
+
+			merged := MergeMessages(messages, Messages{"A": "a", "B": "b"}, false)
+			if merged["A"] != "甲" || merged["B"] != "" {
+				return fmt.Errorf("unexpected merged %v", merged)
+			}
+			return nil
+		}),
+		ExpectMust(func() error {
+			c := NewCatalog()
+			err := c.LoadFS(fstest.MapFS{
+				"zh.json": {Data: []byte(`{"A":"甲"}`)},
+			})
+			if err != nil {
+				return err
+			}
+			if msg, _, ok := c.Lookup("A", "zh"); !ok || msg != "甲" {
+				return fmt.Errorf("unexpected loaded message %q", msg)
+			}
+			return nil
+		}),
+	)
+}
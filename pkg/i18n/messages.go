@@ -0,0 +1,64 @@
+package i18n
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/go-json-experiment/json"
+	"github.com/go-json-experiment/json/jsontext"
+)
+
+// MergeMessages 将 source 中 target 缺失的键合并进 target，已有翻译不会被覆盖。
+//
+// fill 为 false 时新增条目留空，等待翻译。
+func MergeMessages(target Messages, source Messages, fill bool) Messages {
+	if target == nil {
+		target = Messages{}
+	}
+
+	for key, tmpl := range source {
+		if _, ok := target[key]; ok {
+			continue
+		}
+		if fill {
+			target[key] = tmpl
+		} else {
+			target[key] = ""
+		}
+	}
+
+	return target
+}
+
+// ReadMessagesFile 读取翻译文件，文件不存在时返回空信息。
+func ReadMessagesFile(filename string) (Messages, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return Messages{}, nil
+		}
+		return nil, err
+	}
+
+	messages := Messages{}
+	if err := json.Unmarshal(data, &messages); err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+// WriteMessagesFile 按键排序写出翻译文件。
+func WriteMessagesFile(filename string, messages Messages) error {
+	data, err := json.Marshal(messages, json.Deterministic(true), jsontext.WithIndent("  "))
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filename), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(filename, append(data, '\n'), 0o644)
+}
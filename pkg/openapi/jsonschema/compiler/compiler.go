@@ -0,0 +1,297 @@
+package compiler
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/octohelm/courier/pkg/openapi/jsonschema"
+	"github.com/octohelm/courier/pkg/validator"
+)
+
+type OptionFunc func(c *Compiler)
+
+// WithSchemas 设置用于解析 `#/components/schemas/{name}` 引用的 schema 集合。
+func WithSchemas(schemas map[string]jsonschema.Schema) OptionFunc {
+	return func(c *Compiler) {
+		c.schemas = schemas
+	}
+}
+
+// New 创建 schema 编译器。
+func New(fns ...OptionFunc) *Compiler {
+	c := &Compiler{
+		refs: map[string]*refValidator{},
+	}
+	for _, fn := range fns {
+		fn(c)
+	}
+	return c
+}
+
+// Compile 使用 fns 创建编译器并编译 s。
+func Compile(s jsonschema.Schema, fns ...OptionFunc) (validator.Validator, error) {
+	return New(fns...).Compile(s)
+}
+
+// Compiler 将 schema 编译为校验器，同一编译器内对 components 的引用只编译一次。
+type Compiler struct {
+	schemas map[string]jsonschema.Schema
+
+	mu   sync.Mutex
+	refs map[string]*refValidator
+}
+
+// Compile 编译 s；引用在编译时检查是否存在，引用目标在首次校验时按需编译，以支持递归 schema。
+func (c *Compiler) Compile(s jsonschema.Schema) (validator.Validator, error) {
+	sc := &scope{
+		compiler: c,
+		refs:     map[string]*refValidator{},
+	}
+
+	if s != nil {
+		sc.defs = s.GetCore().Defs
+	}
+
+	v, err := sc.compile(s)
+	if err != nil {
+		return nil, err
+	}
+	return &rootValidator{Validator: v}, nil
+}
+
+// scope 为单次编译的上下文，根 schema 的 `$defs` 只在其内可见
+type scope struct {
+	compiler *Compiler
+	defs     map[string]jsonschema.Schema
+
+	// 引用目标在校验时按需编译，同一校验器可能被并发使用
+	mu   sync.Mutex
+	refs map[string]*refValidator
+}
+
+func (sc *scope) compile(s jsonschema.Schema) (validator.Validator, error) {
+	switch x := s.(type) {
+	case nil, *jsonschema.AnyType:
+		return &anyValidator{}, nil
+	case *jsonschema.RefType:
+		return sc.ref(x)
+	case *jsonschema.ObjectType:
+		return sc.compileObject(x)
+	case *jsonschema.ArrayType:
+		return sc.compileArray(x)
+	case *jsonschema.StringType:
+		return compileString(x)
+	case *jsonschema.NumberType:
+		return &numberValidator{
+			integer:          x.Type == "integer",
+			minimum:          x.Minimum,
+			maximum:          x.Maximum,
+			exclusiveMinimum: x.ExclusiveMinimum,
+			exclusiveMaximum: x.ExclusiveMaximum,
+			multipleOf:       x.MultipleOf,
+		}, nil
+	case *jsonschema.BooleanType:
+		return &kindValidator{typ: "boolean"}, nil
+	case *jsonschema.NullType:
+		return &kindValidator{typ: "null"}, nil
+	case *jsonschema.EnumType:
+		return newEnumValidator(x.Enum)
+	case *jsonschema.UnionType:
+		return sc.compileUnion(x)
+	case *jsonschema.IntersectionType:
+		v := &allOfValidator{}
+		for _, sub := range x.AllOf {
+			subValidator, err := sc.compile(sub)
+			if err != nil {
+				return nil, err
+			}
+			v.validators = append(v.validators, subValidator)
+		}
+		return v, nil
+	}
+
+	return nil, fmt.Errorf("unsupported schema %T", s)
+}
+
+func (sc *scope) ref(x *jsonschema.RefType) (validator.Validator, error) {
+	ref := x.Ref
+	if ref == nil {
+		ref = x.DynamicRef
+	}
+	if ref == nil {
+		return nil, fmt.Errorf("invalid ref schema")
+	}
+
+	name := ref.RefName()
+
+	if strings.HasPrefix(ref.Fragment, "/$defs/") {
+		target, ok := sc.defs[name]
+		if !ok {
+			return nil, fmt.Errorf("unresolved ref %s", ref.Fragment)
+		}
+		return cachedRef(&sc.mu, sc.refs, name, target, func() *scope { return sc }), nil
+	}
+
+	target, ok := sc.compiler.schemas[name]
+	if !ok {
+		return nil, fmt.Errorf("unresolved ref %s", ref.Fragment)
+	}
+
+	// components 中的 schema 在独立的 scope 中编译，不继承当前根 schema 的 `$defs`
+	return cachedRef(&sc.compiler.mu, sc.compiler.refs, name, target, func() *scope {
+		return &scope{
+			compiler: sc.compiler,
+			defs:     target.GetCore().Defs,
+			refs:     map[string]*refValidator{},
+		}
+	}), nil
+}
+
+func cachedRef(mu *sync.Mutex, refs map[string]*refValidator, name string, target jsonschema.Schema, scopeOf func() *scope) *refValidator {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if v, ok := refs[name]; ok {
+		return v
+	}
+	v := &refValidator{
+		name: name,
+		compile: sync.OnceValues(func() (validator.Validator, error) {
+			return scopeOf().compile(target)
+		}),
+	}
+	refs[name] = v
+	return v
+}
+
+func (sc *scope) compileObject(x *jsonschema.ObjectType) (validator.Validator, error) {
+	v := &objectValidator{
+		properties:    map[string]validator.Validator{},
+		required:      x.Required,
+		minProperties: x.MinProperties,
+		maxProperties: x.MaxProperties,
+	}
+
+	for key, prop := range x.Properties.KeyValues() {
+		propValidator, err := sc.compile(prop)
+		if err != nil {
+			return nil, fmt.Errorf("compile property %s failed: %w", key, err)
+		}
+		v.properties[key] = propValidator
+	}
+
+	if x.AdditionalProperties != nil {
+		additional, err := sc.compile(x.AdditionalProperties)
+		if err != nil {
+			return nil, fmt.Errorf("compile additionalProperties failed: %w", err)
+		}
+		v.additionalProperties = additional
+	}
+
+	if x.PropertyNames != nil {
+		propertyNames, err := sc.compile(x.PropertyNames)
+		if err != nil {
+			return nil, fmt.Errorf("compile propertyNames failed: %w", err)
+		}
+		v.propertyNames = propertyNames
+	}
+
+	return v, nil
+}
+
+func (sc *scope) compileArray(x *jsonschema.ArrayType) (validator.Validator, error) {
+	v := &arrayValidator{
+		minItems:    x.MinItems,
+		maxItems:    x.MaxItems,
+		uniqueItems: x.UniqueItems != nil && *x.UniqueItems,
+	}
+
+	if x.Items != nil {
+		items, err := sc.compile(x.Items)
+		if err != nil {
+			return nil, fmt.Errorf("compile items failed: %w", err)
+		}
+		v.items = items
+	}
+
+	return v, nil
+}
+
+func compileString(x *jsonschema.StringType) (validator.Validator, error) {
+	v := &stringValidator{
+		minLength: x.MinLength,
+		maxLength: x.MaxLength,
+	}
+
+	if x.Pattern != "" {
+		re, err := regexp.Compile(x.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %s: %w", x.Pattern, err)
+		}
+		v.pattern = re
+
+		if errMsg, ok := x.GetExtension(jsonschema.XPatternErrMsg); ok {
+			v.patternErrMsg, _ = errMsg.(string)
+		}
+	}
+
+	if x.Format != "" {
+		v.format = formatValidator(x.Format)
+	}
+
+	return v, nil
+}
+
+// formatValidator 复用 validator 中注册的 strfmt，未注册的 format 仅作为标注
+func formatValidator(format string) validator.Validator {
+	v, err := validator.New(validator.Option{Rule: "@" + format})
+	if err != nil {
+		return nil
+	}
+
+	if u, ok := v.(interface{ Unwrap() validator.Validator }); ok {
+		if f, ok := u.Unwrap().(interface{ Format() string }); ok {
+			return f.(validator.Validator)
+		}
+	}
+
+	return nil
+}
+
+func (sc *scope) compileUnion(x *jsonschema.UnionType) (validator.Validator, error) {
+	v := &oneOfValidator{}
+
+	for _, sub := range x.OneOf {
+		subValidator, err := sc.compile(sub)
+		if err != nil {
+			return nil, err
+		}
+		v.validators = append(v.validators, subValidator)
+	}
+
+	if d := x.Discriminator; d != nil && d.PropertyName != "" {
+		v.discriminator = d.PropertyName
+		v.mapping = map[string]validator.Validator{}
+
+		for value, sub := range d.Mapping {
+			subValidator, err := sc.compile(sub)
+			if err != nil {
+				return nil, err
+			}
+			v.mapping[value] = subValidator
+		}
+
+		// 未声明 mapping 时，以引用名作为 discriminator 的值
+		for i, sub := range x.OneOf {
+			if r, ok := sub.(jsonschema.Refer); ok {
+				if _, ok := v.mapping[r.RefName()]; !ok {
+					v.mapping[r.RefName()] = v.validators[i]
+				}
+			}
+		}
+	}
+
+	return v, nil
+}
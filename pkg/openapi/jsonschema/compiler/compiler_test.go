@@ -0,0 +1,230 @@
+package compiler_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/go-json-experiment/json/jsontext"
+	. "github.com/octohelm/x/testing/v2"
+
+	"github.com/octohelm/courier/pkg/openapi"
+	"github.com/octohelm/courier/pkg/openapi/jsonschema"
+	"github.com/octohelm/courier/pkg/openapi/jsonschema/compiler"
+	"github.com/octohelm/courier/pkg/validator"
+	validatorerrors "github.com/octohelm/courier/pkg/validator/errors"
+)
+
+const doc = `{
+  "openapi": "3.1.0",
+  "info": {"title": "demo"},
+  "paths": {},
+  "components": {
+    "schemas": {
+      "Org": {
+        "type": "object",
+        "properties": {
+          "name": {"type": "string", "pattern": "^[a-z]+$"},
+          "email": {"type": "string", "format": "email"},
+          "size": {"type": "integer", "minimum": 1, "maximum": 100},
+          "type": {"enum": ["team", "company"]},
+          "tags": {"type": "array", "items": {"type": "string"}, "uniqueItems": true},
+          "labels": {"type": "object", "additionalProperties": {"type": "string"}}
+        },
+        "required": ["name", "type"]
+      },
+      "Node": {
+        "type": "object",
+        "properties": {
+          "name": {"type": "string"},
+          "children": {"type": "array", "items": {"$ref": "#/components/schemas/Node"}}
+        },
+        "required": ["name"]
+      },
+      "Cat": {
+        "type": "object",
+        "properties": {"kind": {"enum": ["Cat"]}, "lives": {"type": "integer"}},
+        "required": ["kind", "lives"]
+      },
+      "Dog": {
+        "type": "object",
+        "properties": {"kind": {"enum": ["Dog"]}, "bark": {"type": "boolean"}},
+        "required": ["kind", "bark"]
+      },
+      "Pet": {
+        "oneOf": [
+          {"$ref": "#/components/schemas/Cat"},
+          {"$ref": "#/components/schemas/Dog"}
+        ],
+        "discriminator": {"propertyName": "kind"}
+      },
+      "Nullable": {
+        "type": ["string", "null"],
+        "maxLength": 3
+      }
+    }
+  }
+}`
+
+func TestCompiler(t *testing.T) {
+	p := &openapi.Payload{}
+	if err := p.UnmarshalJSON([]byte(doc)); err != nil {
+		t.Fatal(err)
+	}
+
+	c := compiler.New(compiler.WithSchemas(p.Schemas))
+
+	compile := func(name string) validator.Validator {
+		v, err := c.Compile(p.RefSchema(name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+
+	validate := func(v validator.Validator, data string) error {
+		return v.Validate(jsontext.Value(data))
+	}
+
+	t.Run("object", func(t *testing.T) {
+		org := compile("Org")
+
+		Then(t, "符合 schema 的数据校验通过",
+			ExpectMust(func() error {
+				return validate(org, `{"name":"demo","email":"demo@example.com","size":10,"type":"team","tags":["a","b"],"labels":{"x":"y"}}`)
+			}),
+		)
+
+		err := validate(org, `{"email":"demo@example.com","size":1000,"type":"unknown","tags":["a","a"],"labels":{"x":1}}`)
+
+		Then(t, "错误以 JSON Pointer 标注位置",
+			Expect(err.Error(), Equal(
+				"integer value should be less or equal than 100, but got 1000 at /size; "+
+					`enum value should be one of team, company, but got "unknown" at /type; `+
+					`invalid unique item: "a" at /tags/1; `+
+					"invalid string: 1 at /labels/x; "+
+					"missing required field at /name",
+			)),
+			Expect(validatorerrors.IsValidationError(err), Equal(true)),
+		)
+
+		Then(t, "format 复用已注册的 strfmt 校验",
+			ExpectDo(func() error { return validate(org, `{"name":"demo","type":"team","email":"invalid"}`) }, ErrorAsType[*validatorerrors.ErrPatternNotMatch]()),
+		)
+
+		Then(t, "顶层类型不符",
+			Expect(validate(org, ` [] `).Error(), Equal("invalid object: array")),
+		)
+	})
+
+	t.Run("recursive ref", func(t *testing.T) {
+		node := compile("Node")
+
+		Then(t, "递归引用逐层校验",
+			ExpectMust(func() error {
+				return validate(node, `{"name":"a","children":[{"name":"b","children":[{"name":"c"}]}]}`)
+			}),
+			Expect(validate(node, `{"name":"a","children":[{"name":"b","children":[{}]}]}`).Error(), Equal(
+				"missing required field at /children/0/children/0/name",
+			)),
+		)
+	})
+
+	t.Run("discriminator", func(t *testing.T) {
+		pet := compile("Pet")
+
+		Then(t, "按 discriminator 选择分支",
+			ExpectMust(func() error { return validate(pet, `{"kind":"Cat","lives":9}`) }),
+			Expect(validate(pet, `{"kind":"Dog","lives":9}`).Error(), Equal("missing required field at /bark")),
+			Expect(validate(pet, `{"kind":"Fish"}`).Error(), Equal("kind should be one of Cat, Dog, but got Fish at /kind")),
+		)
+	})
+
+	t.Run("oneOf", func(t *testing.T) {
+		nullable := compile("Nullable")
+
+		Then(t, "仅匹配一个分支时校验通过",
+			ExpectMust(func() error { return validate(nullable, `null`) }),
+			ExpectMust(func() error { return validate(nullable, `"abc"`) }),
+		)
+
+		Then(t, "类型相符分支的错误被保留",
+			Expect(validate(nullable, `"abcd"`).Error(), Equal("string value length should be less or equal than 3, but got 4")),
+		)
+
+		Then(t, "均不匹配时返回 ErrOneOfNotMatched",
+			ExpectDo(func() error { return validate(nullable, `1`) }, ErrorAsType[*compiler.ErrOneOfNotMatched]()),
+		)
+	})
+
+	t.Run("defs", func(t *testing.T) {
+		s := jsonschema.ArrayOf(&jsonschema.RefType{Ref: mustRef(t, "#/$defs/ID")})
+		s.Defs = map[string]jsonschema.Schema{
+			"ID": jsonschema.String(),
+		}
+
+		v, err := compiler.Compile(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		Then(t, "根 schema 的 $defs 可被引用",
+			Expect(validate(v, `["a", 1]`).Error(), Equal("invalid string: 1 at /1")),
+		)
+	})
+
+	t.Run("concurrent first validation", func(t *testing.T) {
+		ref := func(name string) jsonschema.Schema {
+			return &jsonschema.RefType{Ref: mustRef(t, "#/$defs/"+name)}
+		}
+
+		s := jsonschema.ObjectOf(map[string]jsonschema.Schema{
+			"a": ref("A"),
+			"b": ref("B"),
+		})
+		s.Defs = map[string]jsonschema.Schema{
+			"A":  jsonschema.ArrayOf(ref("ID")),
+			"B":  jsonschema.ArrayOf(ref("ID")),
+			"ID": jsonschema.String(),
+		}
+
+		v, err := compiler.Compile(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		errs := make([]error, 16)
+		wg := &sync.WaitGroup{}
+		for i := range errs {
+			wg.Go(func() {
+				// 不同的数据触发不同引用目标的首次编译
+				if i%2 == 0 {
+					errs[i] = validate(v, `{"a":["x"]}`)
+				} else {
+					errs[i] = validate(v, `{"b":["x"]}`)
+				}
+			})
+		}
+		wg.Wait()
+
+		Then(t, "引用目标首次被并发校验时按需编译不产生竞争",
+			Expect(errors.Join(errs...), Equal[error](nil)),
+		)
+	})
+
+	t.Run("unresolved ref", func(t *testing.T) {
+		_, err := compiler.Compile(&jsonschema.RefType{Ref: mustRef(t, "#/components/schemas/Missing")})
+
+		Then(t, "无法解析的引用在编译时报错",
+			Expect(err.Error(), Equal("unresolved ref /components/schemas/Missing")),
+		)
+	})
+}
+
+func mustRef(t *testing.T, ref string) *jsonschema.URIReferenceString {
+	u, err := jsonschema.ParseURIReferenceString(ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u
+}
@@ -0,0 +1,12 @@
+// Package compiler 将 `jsonschema.Schema` 编译为运行时校验器。
+//
+// 编译得到的校验器实现 `validator.Validator`，直接校验 JSON 数据，
+// 错误复用 `pkg/validator/errors` 并以 JSON Pointer 标注位置；
+// `$ref` 经由 components 或根 schema 的 `$defs` 解析，可校验从 OpenAPI 文档加载的 schema：
+//
+//	c := compiler.New(compiler.WithSchemas(doc.Schemas))
+//	v, err := c.Compile(op.RequestBody.Content["application/json"].Schema)
+//	err = v.Validate(jsontext.Value(data))
+//
+// +gengo:runtimedoc=false
+package compiler
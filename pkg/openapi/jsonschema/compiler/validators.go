@@ -0,0 +1,599 @@
+package compiler
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-json-experiment/json"
+	"github.com/go-json-experiment/json/jsontext"
+
+	"github.com/octohelm/courier/pkg/validator"
+	validatorerrors "github.com/octohelm/courier/pkg/validator/errors"
+)
+
+// ErrOneOfNotMatched 表示数据未能恰好匹配 oneOf 中的一个 schema。
+type ErrOneOfNotMatched struct {
+	Matched int
+}
+
+func (ErrOneOfNotMatched) ValidationError() {}
+
+func (e *ErrOneOfNotMatched) Error() string {
+	return fmt.Sprintf("should match exactly one schema in oneOf, but matched %d", e.Matched)
+}
+
+func kindOf(value jsontext.Value) string {
+	switch value.Kind() {
+	case '{':
+		return "object"
+	case '[':
+		return "array"
+	case '"':
+		return "string"
+	case '0':
+		return "number"
+	case 't', 'f':
+		return "boolean"
+	case 'n':
+		return "null"
+	}
+	return "invalid"
+}
+
+func errInvalidType(typ string, value jsontext.Value) error {
+	target := string(value)
+	switch value.Kind() {
+	case '{', '[':
+		target = kindOf(value)
+	}
+	return &validatorerrors.ErrInvalidType{
+		Type:   typ,
+		Target: target,
+	}
+}
+
+func pointerOf(key string) jsontext.Pointer {
+	return jsontext.Pointer("").AppendToken(key)
+}
+
+// canonical 返回数据的规范化编码，用于 enum 与 uniqueItems 的比较
+func canonical(value any) ([]byte, error) {
+	raw, ok := value.(jsontext.Value)
+	if !ok {
+		b, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		raw = b
+	}
+
+	var v any
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(v, json.Deterministic(true))
+}
+
+// rootValidator 去除数据首尾空白，内部校验器只处理紧凑的 JSON 值
+type rootValidator struct {
+	validator.Validator
+}
+
+func (v *rootValidator) Validate(value jsontext.Value) error {
+	return v.Validator.Validate(bytes.TrimSpace(value))
+}
+
+type anyValidator struct{}
+
+func (anyValidator) String() string {
+	return "any"
+}
+
+func (anyValidator) Validate(value jsontext.Value) error {
+	return nil
+}
+
+type kindValidator struct {
+	typ string
+}
+
+func (v *kindValidator) String() string {
+	return v.typ
+}
+
+func (v *kindValidator) Validate(value jsontext.Value) error {
+	if kindOf(value) != v.typ {
+		return errInvalidType(v.typ, value)
+	}
+	return nil
+}
+
+type refValidator struct {
+	name    string
+	compile func() (validator.Validator, error)
+}
+
+func (v *refValidator) String() string {
+	return "#" + v.name
+}
+
+func (v *refValidator) Validate(value jsontext.Value) error {
+	target, err := v.compile()
+	if err != nil {
+		return fmt.Errorf("compile %s failed: %w", v.name, err)
+	}
+	return target.Validate(value)
+}
+
+type member struct {
+	key   string
+	value jsontext.Value
+}
+
+func decodeMembers(value jsontext.Value, end jsontext.Kind, fn func(key string, value jsontext.Value)) error {
+	dec := jsontext.NewDecoder(bytes.NewReader(value))
+
+	if _, err := dec.ReadToken(); err != nil {
+		return err
+	}
+
+	for dec.PeekKind() != end {
+		key := ""
+
+		if end == '}' {
+			t, err := dec.ReadToken()
+			if err != nil {
+				return err
+			}
+			key = t.String()
+		}
+
+		v, err := dec.ReadValue()
+		if err != nil {
+			return err
+		}
+
+		fn(key, v.Clone())
+	}
+
+	return nil
+}
+
+type objectValidator struct {
+	properties           map[string]validator.Validator
+	additionalProperties validator.Validator
+	propertyNames        validator.Validator
+	required             []string
+	minProperties        *uint64
+	maxProperties        *uint64
+}
+
+func (v *objectValidator) String() string {
+	return "object"
+}
+
+func (v *objectValidator) Validate(value jsontext.Value) error {
+	if value.Kind() != '{' {
+		return errInvalidType("object", value)
+	}
+
+	var members []member
+	if err := decodeMembers(value, '}', func(key string, value jsontext.Value) {
+		members = append(members, member{key: key, value: value})
+	}); err != nil {
+		return err
+	}
+
+	var errs []error
+
+	for _, m := range members {
+		if v.propertyNames != nil {
+			name, _ := jsontext.AppendQuote(nil, m.key)
+			if err := v.propertyNames.Validate(name); err != nil {
+				errs = append(errs, validatorerrors.PrefixJSONPointer(err, pointerOf(m.key)))
+				continue
+			}
+		}
+
+		propValidator, ok := v.properties[m.key]
+		if !ok {
+			propValidator = v.additionalProperties
+		}
+
+		if propValidator != nil {
+			if err := propValidator.Validate(m.value); err != nil {
+				errs = append(errs, validatorerrors.PrefixJSONPointer(err, pointerOf(m.key)))
+			}
+		}
+	}
+
+	for _, key := range v.required {
+		if !slices.ContainsFunc(members, func(m member) bool { return m.key == key }) {
+			errs = append(errs, validatorerrors.PrefixJSONPointer(&validatorerrors.ErrMissingRequired{}, pointerOf(key)))
+		}
+	}
+
+	n := uint64(len(members))
+
+	if v.minProperties != nil && n < *v.minProperties {
+		errs = append(errs, &validatorerrors.ErrOutOfRange{
+			Subject: "props count",
+			Target:  n,
+			Minimum: *v.minProperties,
+		})
+	}
+
+	if v.maxProperties != nil && n > *v.maxProperties {
+		errs = append(errs, &validatorerrors.ErrOutOfRange{
+			Subject: "props count",
+			Target:  n,
+			Maximum: *v.maxProperties,
+		})
+	}
+
+	return validatorerrors.Join(errs...)
+}
+
+type arrayValidator struct {
+	items       validator.Validator
+	minItems    *uint64
+	maxItems    *uint64
+	uniqueItems bool
+}
+
+func (v *arrayValidator) String() string {
+	if v.items != nil {
+		return "[]" + v.items.String()
+	}
+	return "array"
+}
+
+func (v *arrayValidator) Validate(value jsontext.Value) error {
+	if value.Kind() != '[' {
+		return errInvalidType("array", value)
+	}
+
+	var items []jsontext.Value
+	if err := decodeMembers(value, ']', func(_ string, value jsontext.Value) {
+		items = append(items, value)
+	}); err != nil {
+		return err
+	}
+
+	var errs []error
+
+	if v.items != nil {
+		for i, item := range items {
+			if err := v.items.Validate(item); err != nil {
+				errs = append(errs, validatorerrors.PrefixJSONPointer(err, pointerOf(strconv.Itoa(i))))
+			}
+		}
+	}
+
+	n := uint64(len(items))
+
+	if v.minItems != nil && n < *v.minItems {
+		errs = append(errs, &validatorerrors.ErrOutOfRange{
+			Subject: "array items",
+			Target:  n,
+			Minimum: *v.minItems,
+		})
+	}
+
+	if v.maxItems != nil && n > *v.maxItems {
+		errs = append(errs, &validatorerrors.ErrOutOfRange{
+			Subject: "array items",
+			Target:  n,
+			Maximum: *v.maxItems,
+		})
+	}
+
+	if v.uniqueItems {
+		seen := map[string]bool{}
+		for i, item := range items {
+			key, err := canonical(item)
+			if err != nil {
+				return err
+			}
+			if seen[string(key)] {
+				errs = append(errs, validatorerrors.PrefixJSONPointer(&validatorerrors.ErrInvalidType{
+					Type:   "unique item",
+					Target: string(item),
+				}, pointerOf(strconv.Itoa(i))))
+				continue
+			}
+			seen[string(key)] = true
+		}
+	}
+
+	return validatorerrors.Join(errs...)
+}
+
+type stringValidator struct {
+	minLength     *uint64
+	maxLength     *uint64
+	pattern       *regexp.Regexp
+	patternErrMsg string
+	format        validator.Validator
+}
+
+func (v *stringValidator) String() string {
+	if v.format != nil {
+		return v.format.String()
+	}
+	return "string"
+}
+
+func (v *stringValidator) Validate(value jsontext.Value) error {
+	if value.Kind() != '"' {
+		return errInvalidType("string", value)
+	}
+
+	unquoted, err := jsontext.AppendUnquote(nil, value)
+	if err != nil {
+		return err
+	}
+	val := string(unquoted)
+
+	if v.pattern != nil && !v.pattern.MatchString(val) {
+		return &validatorerrors.ErrPatternNotMatch{
+			Subject: "string value",
+			Pattern: v.pattern.String(),
+			ErrMsg:  v.patternErrMsg,
+			Target:  val,
+		}
+	}
+
+	n := uint64(utf8.RuneCountInString(val))
+
+	if v.minLength != nil && n < *v.minLength {
+		return &validatorerrors.ErrOutOfRange{
+			Subject: "string value length",
+			Target:  n,
+			Minimum: *v.minLength,
+		}
+	}
+
+	if v.maxLength != nil && n > *v.maxLength {
+		return &validatorerrors.ErrOutOfRange{
+			Subject: "string value length",
+			Target:  n,
+			Maximum: *v.maxLength,
+		}
+	}
+
+	if v.format != nil {
+		return v.format.Validate(value)
+	}
+
+	return nil
+}
+
+type numberValidator struct {
+	integer          bool
+	minimum          *float64
+	maximum          *float64
+	exclusiveMinimum *float64
+	exclusiveMaximum *float64
+	multipleOf       *float64
+}
+
+func (v *numberValidator) String() string {
+	if v.integer {
+		return "integer"
+	}
+	return "number"
+}
+
+func (v *numberValidator) subject() string {
+	if v.integer {
+		return "integer value"
+	}
+	return "number value"
+}
+
+func (v *numberValidator) Validate(value jsontext.Value) error {
+	if value.Kind() != '0' {
+		return errInvalidType(v.String(), value)
+	}
+
+	val, err := strconv.ParseFloat(string(value), 64)
+	if err != nil {
+		return errInvalidType(v.String(), value)
+	}
+
+	if v.integer && val != math.Trunc(val) {
+		return errInvalidType("integer", value)
+	}
+
+	if v.minimum != nil && val < *v.minimum {
+		return &validatorerrors.ErrOutOfRange{
+			Subject: v.subject(),
+			Target:  val,
+			Minimum: *v.minimum,
+		}
+	}
+
+	if v.exclusiveMinimum != nil && val <= *v.exclusiveMinimum {
+		return &validatorerrors.ErrOutOfRange{
+			Subject:          v.subject(),
+			Target:           val,
+			Minimum:          *v.exclusiveMinimum,
+			ExclusiveMinimum: true,
+		}
+	}
+
+	if v.maximum != nil && val > *v.maximum {
+		return &validatorerrors.ErrOutOfRange{
+			Subject: v.subject(),
+			Target:  val,
+			Maximum: *v.maximum,
+		}
+	}
+
+	if v.exclusiveMaximum != nil && val >= *v.exclusiveMaximum {
+		return &validatorerrors.ErrOutOfRange{
+			Subject:          v.subject(),
+			Target:           val,
+			Maximum:          *v.exclusiveMaximum,
+			ExclusiveMaximum: true,
+		}
+	}
+
+	if m := v.multipleOf; m != nil && *m != 0 {
+		q := val / *m
+		if math.Abs(q-math.Round(q)) > 1e-9 {
+			return &validatorerrors.ErrMultipleOf{
+				Subject:    v.subject(),
+				Target:     val,
+				MultipleOf: *m,
+			}
+		}
+	}
+
+	return nil
+}
+
+type enumValidator struct {
+	enums     []any
+	canonical []string
+}
+
+func newEnumValidator(enums []any) (*enumValidator, error) {
+	v := &enumValidator{enums: enums}
+
+	for _, e := range enums {
+		b, err := canonical(e)
+		if err != nil {
+			return nil, fmt.Errorf("invalid enum value %v: %w", e, err)
+		}
+		v.canonical = append(v.canonical, string(b))
+	}
+
+	return v, nil
+}
+
+func (v *enumValidator) String() string {
+	return "enum(" + strings.Join(v.canonical, ",") + ")"
+}
+
+func (v *enumValidator) Validate(value jsontext.Value) error {
+	b, err := canonical(value)
+	if err != nil {
+		return err
+	}
+
+	if !slices.Contains(v.canonical, string(b)) {
+		return &validatorerrors.ErrNotInEnum{
+			Subject: "enum value",
+			Target:  string(value),
+			Enums:   v.enums,
+		}
+	}
+
+	return nil
+}
+
+type allOfValidator struct {
+	validators []validator.Validator
+}
+
+func (v *allOfValidator) String() string {
+	return "allOf"
+}
+
+func (v *allOfValidator) Validate(value jsontext.Value) error {
+	var errs []error
+	for _, sub := range v.validators {
+		if err := sub.Validate(value); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return validatorerrors.Join(errs...)
+}
+
+type oneOfValidator struct {
+	validators    []validator.Validator
+	discriminator string
+	mapping       map[string]validator.Validator
+}
+
+func (v *oneOfValidator) String() string {
+	return "oneOf"
+}
+
+func (v *oneOfValidator) Validate(value jsontext.Value) error {
+	if v.discriminator != "" && value.Kind() == '{' {
+		return v.validateByDiscriminator(value)
+	}
+
+	matched := 0
+	var candidates []error
+
+	for _, sub := range v.validators {
+		err := sub.Validate(value)
+		if err == nil {
+			matched++
+			continue
+		}
+
+		// 类型不符的分支不作为候选错误
+		if _, ok := err.(*validatorerrors.ErrInvalidType); !ok {
+			candidates = append(candidates, err)
+		}
+	}
+
+	if matched == 1 {
+		return nil
+	}
+
+	if matched == 0 && len(candidates) == 1 {
+		return candidates[0]
+	}
+
+	return &ErrOneOfNotMatched{Matched: matched}
+}
+
+func (v *oneOfValidator) validateByDiscriminator(value jsontext.Value) error {
+	var found *jsontext.Value
+
+	if err := decodeMembers(value, '}', func(key string, value jsontext.Value) {
+		if key == v.discriminator {
+			found = &value
+		}
+	}); err != nil {
+		return err
+	}
+
+	if found == nil {
+		return validatorerrors.PrefixJSONPointer(&validatorerrors.ErrMissingRequired{}, pointerOf(v.discriminator))
+	}
+
+	var tag string
+	if err := json.Unmarshal(*found, &tag); err != nil {
+		return validatorerrors.PrefixJSONPointer(errInvalidType("string", *found), pointerOf(v.discriminator))
+	}
+
+	sub, ok := v.mapping[tag]
+	if !ok {
+		enums := make([]any, 0, len(v.mapping))
+		for _, k := range slices.Sorted(maps.Keys(v.mapping)) {
+			enums = append(enums, k)
+		}
+
+		return validatorerrors.PrefixJSONPointer(&validatorerrors.ErrNotInEnum{
+			Subject: v.discriminator,
+			Target:  tag,
+			Enums:   enums,
+		}, pointerOf(v.discriminator))
+	}
+
+	return sub.Validate(value)
+}
@@ -78,6 +78,11 @@ func SchemaFromType(ctx context.Context, t reflect.Type, opt Opt) (s jsonschema.
 		}
 	}
 
+	// 流式文件序列 iter.Seq2[FileHeader, error]
+	if isSeq2(t) {
+		return jsonschema.ArrayOf(jsonschema.Binary())
+	}
+
 	inst := reflect.New(t).Interface()
 
 	// named type
@@ -474,3 +479,11 @@ func pickStringEnumFromDesc(d string) []string {
 
 	return nil
 }
+
+func isSeq2(t reflect.Type) bool {
+	if t.Kind() != reflect.Func || t.NumIn() != 1 || t.NumOut() != 0 {
+		return false
+	}
+	yield := t.In(0)
+	return yield.Kind() == reflect.Func && yield.NumIn() == 2 && yield.NumOut() == 1 && yield.Out(0).Kind() == reflect.Bool
+}
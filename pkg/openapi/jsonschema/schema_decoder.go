@@ -83,7 +83,7 @@ func (u *schemaDecoder) schemaOfType(typ string, format string) (Schema, error)
 		}
 		return t, nil
 	case "string":
-		return &StringType{Type: typ}, nil
+		return &StringType{Type: typ, Format: format}, nil
 	case "null":
 		return &NullType{Type: typ}, nil
 	case "boolean":
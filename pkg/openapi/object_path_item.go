@@ -9,10 +9,19 @@ import (
 // no need $ref, server, parameters
 type PathItemObject = internal.Record[string, *OperationObject]
 
+// CallbackObject 以运行时表达式为键，如 `{$request.body#/callbackUrl}`
+// https://spec.openapis.org/oas/latest.html#callback-object
+type CallbackObject = internal.Record[string, *PathItemObject]
+
 type CallbacksObject struct {
-	Callbacks internal.Record[string, *PathItemObject] `json:"callbacks,omitzero"`
+	Callbacks internal.Record[string, *CallbackObject] `json:"callbacks,omitzero"`
 }
 
-func (o *CallbacksObject) AddCallback(name string, c *PathItemObject) {
-	o.Callbacks.Set(name, c)
+func (o *CallbacksObject) AddCallback(name string, expression string, c *PathItemObject) {
+	callback, ok := o.Callbacks.Get(name)
+	if !ok {
+		callback = &CallbackObject{}
+		o.Callbacks.Set(name, callback)
+	}
+	callback.Set(expression, c)
 }
@@ -41,6 +41,8 @@ type OpenAPI struct {
 	ComponentsObject `json:"components"`
 
 	Paths internal.Record[string, *PathItemObject] `json:"paths"`
+	// 服务主动发出的请求，接收方需实现对应接口
+	Webhooks internal.Record[string, *PathItemObject] `json:"webhooks,omitzero"`
 
 	jsonschema.Ext
 }
@@ -54,3 +56,13 @@ func (p *OpenAPI) AddOperation(method string, path string, op *OperationObject)
 
 	operations.Set(strings.ToLower(method), op)
 }
+
+func (p *OpenAPI) AddWebhook(name string, method string, op *OperationObject) {
+	operations, ok := p.Webhooks.Get(name)
+	if !ok {
+		operations = &PathItemObject{}
+		p.Webhooks.Set(name, operations)
+	}
+
+	operations.Set(strings.ToLower(method), op)
+}
@@ -41,7 +41,7 @@ func TestOpenAPIHelpers(t0 *testing.T) {
 
 			callback := &PathItemObject{}
 			callback.Set("post", NewOperation("notify"))
-			op.AddCallback("notify", callback)
+			op.AddCallback("notify", "{$request.body#/callbackUrl}", callback)
 
 			doc.AddOperation(http.MethodGet, "/pets", op)
 
@@ -66,6 +66,8 @@ func TestOpenAPIHelpers(t0 *testing.T) {
 			}
 			if callbackItem, ok := getOp.Callbacks.Get("notify"); !ok || callbackItem == nil {
 				return assertf("missing callback")
+			} else if pathItem, ok := callbackItem.Get("{$request.body#/callbackUrl}"); !ok || pathItem == nil {
+				return assertf("missing callback expression")
 			}
 
 			return nil
@@ -24,6 +24,16 @@ type WithJSONPointer interface {
 	JSONPointer() jsontext.Pointer
 }
 
+// WithDescription 用于为错误补充详情，对应 Descriptor.Description。
+type WithDescription interface {
+	Description() string
+}
+
+// WithProblemType 用于为错误声明 RFC 9457 problem type URI。
+type WithProblemType interface {
+	ProblemType() string
+}
+
 type IntOrString string
 
 type Descriptor struct {
@@ -41,36 +51,52 @@ type Descriptor struct {
 	Source string `json:"source,omitzero"`
 	// 错误链
 	Errors []*Descriptor `json:"errors,omitzero"`
+	// 问题类型 URI
+	Type string `json:"type,omitzero"`
+	// 问题实例 URI
+	Instance string `json:"instance,omitzero"`
 
 	Status int `json:"-"`
 }
 
+// UnmarshalErrorResponse 从错误响应体还原错误描述。
+//
+// 同时支持 `ErrorResponse` 与 RFC 9457 `ProblemDetails` 两种响应格式。
 func (e *Descriptor) UnmarshalErrorResponse(statusCode int, raw []byte) error {
 	d := Descriptor{}
 	d.Status = statusCode
 
-	errResp := &ErrorResponse{}
-	if err := json.Unmarshal(raw, errResp); err != nil {
+	body := &errorResponseBody{}
+	if err := json.Unmarshal(raw, body); err != nil {
 		d.Message = string(raw)
 		*e = d
 		return nil
 	}
 
-	switch len(errResp.Errors) {
+	switch len(body.Errors) {
 	case 0:
-		d.Message = errResp.Msg
+		d.Message = body.Msg
+		d.Code = body.code()
 	case 1:
-		d = *errResp.Errors[0]
+		d = *body.Errors[0]
 		d.Status = statusCode
+	default:
+		d.Message = body.Msg
+		d.Code = body.code()
+		d.Errors = body.Errors
 	}
 
-	if errResp.Extra != nil {
-		if v, ok := errResp.Extra["title"].(string); ok {
-			d.Message = v
-		}
-		if v, ok := errResp.Extra["detail"].(string); ok {
-			d.Message = v
-		}
+	if body.Title != "" {
+		d.Message = body.Title
+	}
+	if body.Detail != "" {
+		d.Message = body.Detail
+	}
+	if body.Type != "" && body.Type != ProblemTypeBlank {
+		d.Type = body.Type
+	}
+	if body.Instance != "" {
+		d.Instance = body.Instance
 	}
 
 	*e = d
@@ -78,6 +104,28 @@ func (e *Descriptor) UnmarshalErrorResponse(statusCode int, raw []byte) error {
 	return nil
 }
 
+// errorResponseBody 兼容 ErrorResponse 与 ProblemDetails 的字段，
+// 其中 code 在前者中为数值状态码，在后者中为字符串错误编码。
+type errorResponseBody struct {
+	Code     jsontext.Value `json:"code,omitzero"`
+	Msg      string         `json:"msg,omitzero"`
+	Type     string         `json:"type,omitzero"`
+	Title    string         `json:"title,omitzero"`
+	Detail   string         `json:"detail,omitzero"`
+	Instance string         `json:"instance,omitzero"`
+	Errors   []*Descriptor  `json:"errors,omitzero"`
+}
+
+func (b *errorResponseBody) code() string {
+	if b.Code.Kind() == '"' {
+		var code string
+		if err := json.Unmarshal(b.Code, &code); err == nil {
+			return code
+		}
+	}
+	return ""
+}
+
 func (e *Descriptor) StatusCode() int {
 	return e.Status
 }
@@ -86,7 +134,7 @@ func (e *Descriptor) Error() string {
 	return fmt.Sprintf("%s{message=%q}", e.Code, e.Message)
 }
 
-func asDescriptor(err error, source string, loc string) *Descriptor {
+func asDescriptor(err error, source string, loc string, l Localizer) *Descriptor {
 	if errResp, ok := err.(*Descriptor); ok {
 		return errResp
 	}
@@ -120,6 +168,14 @@ func asDescriptor(err error, source string, loc string) *Descriptor {
 		er.Code = v.ErrCode()
 	}
 
+	if v, ok := err.(WithProblemType); ok {
+		er.Type = v.ProblemType()
+	}
+
+	if v, ok := err.(WithDescription); ok {
+		er.Description = v.Description()
+	}
+
 	if er.Code == "" {
 		er.Code = ErrCodeOf(err)
 	}
@@ -132,5 +188,11 @@ func asDescriptor(err error, source string, loc string) *Descriptor {
 		er.Status = http.StatusInternalServerError
 	}
 
+	if l != nil {
+		if msg, ok := localize(l, err, er.Code); ok {
+			er.Message = msg
+		}
+	}
+
 	return er
 }
@@ -22,15 +22,20 @@ func ErrCodeOf(err any) string {
 		rv = rv.Elem()
 	}
 
-	if ast.IsExported(rv.Name()) {
-		if pkgPath := rv.PkgPath(); pkgPath != "" {
+	return ErrCodeOfTypeName(rv.PkgPath(), rv.Name())
+}
+
+// ErrCodeOfTypeName 按包路径与类型名推导错误码，与 ErrCodeOf 的规则一致。
+func ErrCodeOfTypeName(pkgPath string, name string) string {
+	if ast.IsExported(name) {
+		if pkgPath != "" {
 			dir, p := path.Split(pkgPath)
 			if isVersionSegment(p) {
 				p = path.Base(dir) + p
 			}
-			return p + "." + rv.Name()
+			return p + "." + name
 		}
-		return rv.Name()
+		return name
 	}
 	return ""
 }
@@ -9,6 +9,10 @@ import (
 
 // AsErrorResponse 将错误转换为错误响应格式。
 func AsErrorResponse(err error, source string) *ErrorResponse {
+	return asErrorResponse(err, source, nil)
+}
+
+func asErrorResponse(err error, source string, l Localizer) *ErrorResponse {
 	if err == nil {
 		return nil
 	}
@@ -23,7 +27,7 @@ func AsErrorResponse(err error, source string) *ErrorResponse {
 			continue
 		}
 
-		ee := asDescriptor(e, source, loc)
+		ee := asDescriptor(e, source, loc, l)
 		if er == nil {
 			er = &ErrorResponse{}
 
@@ -0,0 +1,63 @@
+package statuserror
+
+import (
+	"errors"
+	"go/ast"
+	"reflect"
+)
+
+// WithMessageKey 用于声明本地化错误信息时使用的键，未声明时使用错误编码；返回空表示不做本地化。
+type WithMessageKey interface {
+	MessageKey() string
+}
+
+// Localizer 按信息键与模板参数返回本地化后的错误信息。
+type Localizer interface {
+	Localize(key string, args map[string]any) (string, bool)
+}
+
+// AsLocalizedErrorResponse 将错误转换为错误响应格式，并通过 l 本地化错误信息。
+//
+// 未命中翻译的错误保留原始信息。
+func AsLocalizedErrorResponse(err error, source string, l Localizer) *ErrorResponse {
+	return asErrorResponse(err, source, l)
+}
+
+func localize(l Localizer, err error, code string) (string, bool) {
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		if v, ok := e.(WithMessageKey); ok {
+			key := v.MessageKey()
+			if key == "" {
+				return "", false
+			}
+			return l.Localize(key, MessageArgsOf(e))
+		}
+	}
+	return l.Localize(code, MessageArgsOf(err))
+}
+
+// MessageArgsOf 提取错误结构体的导出字段作为信息模板参数，匿名嵌入字段会被忽略。
+func MessageArgsOf(err error) map[string]any {
+	rv := reflect.ValueOf(err)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+
+	args := map[string]any{}
+
+	for f, fv := range rv.Fields() {
+		if f.Anonymous || !ast.IsExported(f.Name) {
+			continue
+		}
+		args[f.Name] = fv.Interface()
+	}
+
+	return args
+}
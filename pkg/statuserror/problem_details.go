@@ -0,0 +1,79 @@
+package statuserror
+
+import (
+	"net/http"
+)
+
+const (
+	// MediaTypeProblemJSON 为 RFC 9457 problem details 的媒体类型。
+	MediaTypeProblemJSON = "application/problem+json"
+	// ProblemTypeBlank 为未声明 problem type 时的默认值。
+	ProblemTypeBlank = "about:blank"
+)
+
+// AsProblemDetails 将错误转换为 RFC 9457 problem details 格式。
+//
+// instance 通常为当前请求路径；错误链保留在扩展成员 `errors` 中。
+func AsProblemDetails(err error, source string, instance string) *ProblemDetails {
+	if err == nil {
+		return nil
+	}
+	return AsErrorResponse(err, source).ProblemDetails(instance)
+}
+
+// ProblemDetails 将错误响应转换为 RFC 9457 problem details 格式。
+func (e *ErrorResponse) ProblemDetails(instance string) *ProblemDetails {
+	p := &ProblemDetails{
+		Status:   e.StatusCode(),
+		Detail:   e.Msg,
+		Instance: instance,
+		Errors:   e.Errors,
+	}
+
+	p.Title = http.StatusText(p.Status)
+
+	if len(e.Errors) > 0 {
+		first := e.Errors[0]
+
+		p.Code = first.Code
+		p.Type = first.Type
+
+		if len(e.Errors) == 1 {
+			p.Detail = first.Message
+		}
+	}
+
+	return p
+}
+
+// ProblemDetails 表示 RFC 9457 定义的错误响应。
+type ProblemDetails struct {
+	// 问题类型 URI，缺省视为 about:blank
+	Type string `json:"type,omitzero"`
+	// 问题摘要
+	Title string `json:"title,omitzero"`
+	// 错误状态码
+	Status int `json:"status,omitzero"`
+	// 问题详情
+	Detail string `json:"detail,omitzero"`
+	// 问题实例 URI
+	Instance string `json:"instance,omitzero"`
+	// 错误编码
+	Code string `json:"code,omitzero"`
+	// 错误详情
+	Errors []*Descriptor `json:"errors,omitzero"`
+
+	Extra map[string]any `json:",embed"`
+}
+
+func (p *ProblemDetails) StatusCode() int {
+	return p.Status
+}
+
+func (p *ProblemDetails) ContentType() string {
+	return MediaTypeProblemJSON
+}
+
+func (p *ProblemDetails) Unwrap() []error {
+	return (&ErrorResponse{Errors: p.Errors}).Unwrap()
+}
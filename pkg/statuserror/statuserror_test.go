@@ -4,8 +4,10 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 	"testing"
 
+	"github.com/go-json-experiment/json"
 	"github.com/go-json-experiment/json/jsontext"
 	. "github.com/octohelm/x/testing/v2"
 )
@@ -231,3 +233,84 @@ func TestRuntimeDocAndSequenceBranches(t0 *testing.T) {
 		Expect(isVersionSegment("vx"), Equal(false)),
 	)
 }
+
+func TestProblemDetails(t0 *testing.T) {
+	Then(
+		t0, "错误可转换为 problem details 并可回解析",
+		ExpectMust(func() error {
+			p := AsProblemDetails(Wrap(errors.New("org exists"), http.StatusConflict, "ORG_CONFLICT"), "courier", "/orgs")
+			if p.Status != http.StatusConflict || p.Title != http.StatusText(http.StatusConflict) {
+				return fmt.Errorf("unexpected problem %#v", p)
+			}
+			if p.Detail != "org exists" || p.Code != "ORG_CONFLICT" || p.Instance != "/orgs" {
+				return fmt.Errorf("unexpected problem %#v", p)
+			}
+			if p.ContentType() != MediaTypeProblemJSON {
+				return fmt.Errorf("unexpected content type %s", p.ContentType())
+			}
+
+			raw, err := json.Marshal(p)
+			if err != nil {
+				return err
+			}
+
+			var d Descriptor
+			if err := d.UnmarshalErrorResponse(http.StatusConflict, raw); err != nil {
+				return err
+			}
+			if d.Code != "ORG_CONFLICT" || d.Message != "org exists" || d.Instance != "/orgs" {
+				return fmt.Errorf("unexpected descriptor %#v", d)
+			}
+			return nil
+		}),
+		Expect(AsProblemDetails(nil, "courier", "/"), Equal[*ProblemDetails](nil)),
+	)
+}
+
+type localizerStub map[string]string
+
+func (l localizerStub) Localize(key string, args map[string]any) (string, bool) {
+	tmpl, ok := l[key]
+	if !ok {
+		return "", false
+	}
+	return strings.ReplaceAll(tmpl, "{Name}", fmt.Sprint(args["Name"])), true
+}
+
+type OrgConflict struct {
+	Conflict
+
+	Name string
+}
+
+func (e *OrgConflict) Error() string { return "org " + e.Name + " exists" }
+
+func TestAsLocalizedErrorResponse(t0 *testing.T) {
+	l := localizerStub{
+		"statuserror.OrgConflict": "组织 {Name} 已存在",
+		"CONFLICT":                "冲突",
+	}
+
+	Then(
+		t0, "错误信息可按错误码本地化",
+		ExpectMust(func() error {
+			resp := AsLocalizedErrorResponse(&OrgConflict{Name: "demo"}, "courier", l)
+			if resp.Msg != "组织 demo 已存在" {
+				return fmt.Errorf("unexpected msg %q", resp.Msg)
+			}
+
+			resp = AsLocalizedErrorResponse(Wrap(errors.New("boom"), http.StatusConflict, "CONFLICT"), "courier", l)
+			if resp.Msg != "冲突" || resp.Errors[0].Message != "冲突" {
+				return fmt.Errorf("unexpected msg %q", resp.Msg)
+			}
+
+			resp = AsLocalizedErrorResponse(errors.New("plain"), "courier", l)
+			if resp.Msg != "plain" {
+				return fmt.Errorf("unexpected msg %q", resp.Msg)
+			}
+			return nil
+		}),
+		Expect(MessageArgsOf(&OrgConflict{Name: "demo"}), Equal(map[string]any{"Name": "demo"})),
+		Expect(ErrCodeOfTypeName("github.com/x/apis/org/v1", "ErrOrgNotFound"), Equal("orgv1.ErrOrgNotFound")),
+	)
+}
@@ -32,6 +32,14 @@ func (v *Descriptor) RuntimeDoc(names ...string) ([]string, bool) {
 			return []string{
 				"错误链",
 			}, true
+		case "Type":
+			return []string{
+				"问题类型 URI",
+			}, true
+		case "Instance":
+			return []string{
+				"问题实例 URI",
+			}, true
 		case "Status":
 			return []string{}, true
 
@@ -73,6 +81,49 @@ func (*IntOrString) RuntimeDoc(names ...string) ([]string, bool) {
 	return []string{}, true
 }
 
+func (v *ProblemDetails) RuntimeDoc(names ...string) ([]string, bool) {
+	if len(names) > 0 {
+		switch names[0] {
+		case "Type":
+			return []string{
+				"问题类型 URI，缺省视为 about:blank",
+			}, true
+		case "Title":
+			return []string{
+				"问题摘要",
+			}, true
+		case "Status":
+			return []string{
+				"错误状态码",
+			}, true
+		case "Detail":
+			return []string{
+				"问题详情",
+			}, true
+		case "Instance":
+			return []string{
+				"问题实例 URI",
+			}, true
+		case "Code":
+			return []string{
+				"错误编码",
+			}, true
+		case "Errors":
+			return []string{
+				"错误详情",
+			}, true
+		case "Extra":
+			return []string{}, true
+
+		}
+
+		return nil, false
+	}
+	return []string{
+		"表示 RFC 9457 定义的错误响应。",
+	}, true
+}
+
 // nolint:deadcode,unused
 func runtimeDoc(v any, prefix string, names ...string) ([]string, bool) {
 	if c, ok := v.(interface {
@@ -2,6 +2,8 @@ package errors
 
 import (
 	"fmt"
+
+	"github.com/octohelm/courier/pkg/i18n"
 )
 
 func ExampleErrMissingRequired() {
@@ -54,3 +56,45 @@ func ExampleErrOutOfRange() {
 	// Output:
 	// value should be larger than 1 and less than 10, but got 11
 }
+
+func ExampleErrOutOfRange_MessageKey() {
+	minimum := 1
+
+	fmt.Println((&ErrOutOfRange{Minimum: &minimum}).MessageKey())
+	fmt.Println((&ErrOutOfRange{Minimum: &minimum, ExclusiveMinimum: true}).MessageKey())
+	fmt.Println((&ErrOutOfRange{Maximum: &minimum, ExclusiveMaximum: true}).MessageKey())
+	fmt.Println((&ErrOutOfRange{Minimum: &minimum, Maximum: &minimum}).MessageKey())
+	fmt.Println((&ErrOutOfRange{Minimum: &minimum, Maximum: &minimum, ExclusiveMinimum: true}).MessageKey())
+	fmt.Println((&ErrOutOfRange{Minimum: &minimum, Maximum: &minimum, ExclusiveMaximum: true}).MessageKey())
+	fmt.Println((&ErrOutOfRange{Minimum: &minimum, Maximum: &minimum, ExclusiveMinimum: true, ExclusiveMaximum: true}).MessageKey())
+	fmt.Printf("%q\n", (&ErrOutOfRange{}).MessageKey())
+	// Output:
+	// validator.minimum
+	// validator.exclusiveMinimum
+	// validator.exclusiveMaximum
+	// validator.range
+	// validator.range.exclusiveMinimum
+	// validator.range.exclusiveMaximum
+	// validator.range.exclusive
+	// ""
+}
+
+func ExampleMessages() {
+	for _, e := range []*ErrOutOfRange{
+		{Subject: "value", Target: 0, Minimum: 0, ExclusiveMinimum: true},
+		{Subject: "value", Target: 10, Minimum: 1, Maximum: 10, ExclusiveMaximum: true},
+		{Subject: "value", Target: 10, Minimum: 1, Maximum: 10, ExclusiveMinimum: true, ExclusiveMaximum: true},
+	} {
+		msg := i18n.Format(Messages()[e.MessageKey()], map[string]any{
+			"Subject": e.Subject,
+			"Target":  e.Target,
+			"Minimum": e.Minimum,
+			"Maximum": e.Maximum,
+		})
+		fmt.Println(msg, msg == e.Error())
+	}
+	// Output:
+	// value should be larger than 0, but got 0 true
+	// value should be larger or equal than 1 and less than 10, but got 10 true
+	// value should be larger than 1 and less than 10, but got 10 true
+}
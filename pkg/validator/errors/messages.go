@@ -0,0 +1,91 @@
+package errors
+
+// 校验错误的本地化信息键，模板参数为对应错误类型的导出字段。
+const (
+	MessageKeyRequired   = "validator.required"
+	MessageKeyType       = "validator.type"
+	MessageKeyPattern    = "validator.pattern"
+	MessageKeyMultipleOf = "validator.multipleOf"
+	MessageKeyEnum       = "validator.enum"
+	MessageKeyMinimum    = "validator.minimum"
+	MessageKeyMaximum    = "validator.maximum"
+	MessageKeyRange      = "validator.range"
+
+	MessageKeyExclusiveMinimum      = "validator.exclusiveMinimum"
+	MessageKeyExclusiveMaximum      = "validator.exclusiveMaximum"
+	MessageKeyRangeExclusiveMinimum = "validator.range.exclusiveMinimum"
+	MessageKeyRangeExclusiveMaximum = "validator.range.exclusiveMaximum"
+	MessageKeyRangeExclusive        = "validator.range.exclusive"
+)
+
+// Messages 返回校验错误信息键及其默认模板，可作为翻译文件的源语言条目。
+func Messages() map[string]string {
+	return map[string]string{
+		MessageKeyRequired:   "missing required field",
+		MessageKeyType:       "invalid {Type}: {Target}",
+		MessageKeyPattern:    "{Subject} should match {Pattern}, but got {Target}",
+		MessageKeyMultipleOf: "{Subject} should be multiple of {MultipleOf}, but got {Target}",
+		MessageKeyEnum:       "{Subject} should be one of {Enums}, but got {Target}",
+		MessageKeyMinimum:    "{Subject} should be larger or equal than {Minimum}, but got {Target}",
+		MessageKeyMaximum:    "{Subject} should be less or equal than {Maximum}, but got {Target}",
+		MessageKeyRange:      "{Subject} should be larger or equal than {Minimum} and less or equal than {Maximum}, but got {Target}",
+
+		MessageKeyExclusiveMinimum:      "{Subject} should be larger than {Minimum}, but got {Target}",
+		MessageKeyExclusiveMaximum:      "{Subject} should be less than {Maximum}, but got {Target}",
+		MessageKeyRangeExclusiveMinimum: "{Subject} should be larger than {Minimum} and less or equal than {Maximum}, but got {Target}",
+		MessageKeyRangeExclusiveMaximum: "{Subject} should be larger or equal than {Minimum} and less than {Maximum}, but got {Target}",
+		MessageKeyRangeExclusive:        "{Subject} should be larger than {Minimum} and less than {Maximum}, but got {Target}",
+	}
+}
+
+func (*ErrMissingRequired) MessageKey() string {
+	return MessageKeyRequired
+}
+
+func (*ErrInvalidType) MessageKey() string {
+	return MessageKeyType
+}
+
+// MessageKey 在声明了自定义错误信息 ErrMsg 时返回空，保留原始信息。
+func (e *ErrPatternNotMatch) MessageKey() string {
+	if e.ErrMsg != "" {
+		return ""
+	}
+	return MessageKeyPattern
+}
+
+func (*ErrMultipleOf) MessageKey() string {
+	return MessageKeyMultipleOf
+}
+
+func (*ErrNotInEnum) MessageKey() string {
+	return MessageKeyEnum
+}
+
+// MessageKey 按声明的边界及其是否排他选择信息键，未声明边界时返回空，保留原始信息。
+func (e *ErrOutOfRange) MessageKey() string {
+	switch {
+	case e.Minimum != nil && e.Maximum != nil:
+		switch {
+		case e.ExclusiveMinimum && e.ExclusiveMaximum:
+			return MessageKeyRangeExclusive
+		case e.ExclusiveMinimum:
+			return MessageKeyRangeExclusiveMinimum
+		case e.ExclusiveMaximum:
+			return MessageKeyRangeExclusiveMaximum
+		}
+		return MessageKeyRange
+	case e.Minimum != nil:
+		if e.ExclusiveMinimum {
+			return MessageKeyExclusiveMinimum
+		}
+		return MessageKeyMinimum
+	case e.Maximum != nil:
+		if e.ExclusiveMaximum {
+			return MessageKeyExclusiveMaximum
+		}
+		return MessageKeyMaximum
+	default:
+		return ""
+	}
+}
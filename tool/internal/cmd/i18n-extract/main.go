@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/octohelm/gengo/pkg/gengo"
+	"github.com/octohelm/x/logr"
+	"github.com/octohelm/x/logr/slog"
+
+	"github.com/octohelm/courier/devpkg/operatorgen"
+	"github.com/octohelm/courier/pkg/i18n"
+	validatorerrors "github.com/octohelm/courier/pkg/validator/errors"
+)
+
+var (
+	output = flag.String("o", "i18n", "翻译文件输出目录")
+	langs  = flag.String("lang", "en", "逗号分隔的目标语言，第一个为源语言，其条目以默认信息填充")
+)
+
+func main() {
+	flag.Parse()
+
+	collector := operatorgen.NewStatusErrorCollector()
+
+	c, err := gengo.NewExecutor(&gengo.GeneratorArgs{
+		Entrypoint:         flag.Args(),
+		OutputFileBaseName: "zz_generated",
+		Globals:            map[string][]string{},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	ctx := logr.WithLogger(context.Background(), slog.Logger(slog.Default()))
+
+	if err := c.Execute(ctx, collector); err != nil {
+		panic(err)
+	}
+
+	source := collector.Messages()
+	for key, tmpl := range validatorerrors.Messages() {
+		source[key] = tmpl
+	}
+
+	for i, lang := range strings.Split(*langs, ",") {
+		lang = strings.TrimSpace(lang)
+		if lang == "" {
+			continue
+		}
+
+		filename := filepath.Join(*output, lang+".json")
+
+		messages, err := i18n.ReadMessagesFile(filename)
+		if err != nil {
+			_, _ = fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+
+		if err := i18n.WriteMessagesFile(filename, i18n.MergeMessages(messages, source, i == 0)); err != nil {
+			_, _ = fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/octohelm/x/logr"
+	"github.com/octohelm/x/logr/slog"
+
+	"github.com/octohelm/courier/pkg/courierhttp/mock"
+	"github.com/octohelm/courier/pkg/httputil"
+	"github.com/octohelm/courier/pkg/openapi"
+)
+
+var (
+	addr                     = flag.String("addr", "0.0.0.0:8080", "监听地址")
+	seed                     = flag.Uint64("seed", 0, "生成数据的基础随机种子")
+	disableRequestValidation = flag.Bool("disable-request-validation", false, "关闭请求校验")
+)
+
+func main() {
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		_, _ = fmt.Fprintln(os.Stderr, "usage: mock-server [flags] <openapi.json>")
+		os.Exit(2)
+	}
+
+	data, err := os.ReadFile(flag.Arg(0))
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	doc := &openapi.Payload{}
+	if err := doc.UnmarshalJSON(data); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	fns := []mock.OptionFunc{mock.WithSeed(*seed)}
+	if *disableRequestValidation {
+		fns = append(fns, mock.DisableRequestValidation())
+	}
+
+	s, err := mock.New(&doc.OpenAPI, fns...)
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	ctx := logr.WithLogger(context.Background(), slog.Logger(slog.Default()))
+
+	if err := httputil.ListenAndServe(ctx, *addr, s); err != nil {
+		panic(err)
+	}
+}